@@ -0,0 +1,90 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// AfterStop is like clock.After, but also returns a function that releases
+// the timer behind the channel, so waits abandoned in a select don't keep
+// timers alive until they fire. The function reports whether it stopped the
+// timer before it fired, and is safe to call more than once.
+//
+// The channel must not be used after calling the function: on the real clock,
+// the timer and its channel are reused by later calls.
+func AfterStop(clock Clock, d time.Duration) (<-chan time.Time, func() bool) {
+	if clock, ok := clock.(interface {
+		afterStop(d time.Duration) (<-chan time.Time, func() bool)
+	}); ok {
+		return clock.afterStop(d)
+	}
+
+	timer := clock.NewTimer(d)
+	return timer.C(), timer.Stop
+}
+
+// timerPool recycles stopped and drained timers of AfterStop on the real clock.
+var timerPool sync.Pool
+
+func (realClock) afterStop(d time.Duration) (<-chan time.Time, func() bool) {
+	timer, ok := timerPool.Get().(*time.Timer)
+	if ok {
+		timer.Reset(d)
+	} else {
+		timer = time.NewTimer(d)
+	}
+
+	var once sync.Once
+	var stopped bool
+	return timer.C, func() bool {
+		once.Do(func() {
+			stopped = timer.Stop()
+			if !stopped {
+				// drain a value the caller didn't receive
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timerPool.Put(timer)
+		})
+		return stopped
+	}
+}
+
+func (clock *fakeClock) afterStop(d time.Duration) (<-chan time.Time, func() bool) {
+	if d < 0 {
+		d = 0
+	}
+
+	timer := &fakeTimer{
+		clock: clock,
+		sleeper: sleeper{
+			c:      make(chan time.Time, 1),
+			kind:   KindAfter,
+			d:      d,
+			caller: clock.caller(2),
+		},
+	}
+
+	clock.mutex.Lock()
+	defer clock.unlock()
+
+	timer.sleeper.until = clock.at.Add(d)
+	clock.appendSleeper(&timer.sleeper)
+
+	return timer.sleeper.c, timer.Stop
+}
+
+// TickStop is like clock.Tick, but also returns a function that stops the
+// ticker, so tickers that are no longer needed don't keep ticking.
+// If d <= 0, the channel is nil and the function does nothing.
+func TickStop(clock Clock, d time.Duration) (<-chan time.Time, func()) {
+	if d <= 0 {
+		return nil, func() {}
+	}
+
+	// a CustomTicker keeps delivering on the same channel on any clock
+	ticker := NewCustomTicker(clock, d)
+	return ticker.C(), ticker.Stop
+}
@@ -0,0 +1,117 @@
+package clock_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestAfterStop_Fake(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	c, stop := clock.AfterStop(fake, 1*time.Second)
+	assertClockUntil(t, 1, fake)
+
+	if timers := fake.PendingTimers(); len(timers) != 1 || timers[0].Kind != clock.KindAfter {
+		t.Errorf("expected an After timer got %v", timers)
+	}
+
+	if !stop() {
+		t.Error("expected stop to return true")
+	}
+	if stop() {
+		t.Error("expected stop to return false")
+	}
+	if timers := fake.PendingTimers(); len(timers) != 0 {
+		t.Errorf("expected no pending timers got %v", timers)
+	}
+
+	fake.Advance(1 * time.Second)
+	assertNotSent(t, c)
+
+	c, stop = clock.AfterStop(fake, 1*time.Second)
+	fake.Advance(1 * time.Second)
+	assertSent(t, start.Add(2*time.Second), c)
+
+	if stop() {
+		t.Error("expected stop to return false")
+	}
+}
+
+func TestAfterStop_Real(t *testing.T) {
+	real := clock.NewRealClock()
+
+	// an abandoned timer is stopped and reused
+	_, stop := clock.AfterStop(real, 1*time.Hour)
+	if !stop() {
+		t.Error("expected stop to return true")
+	}
+
+	// a fired timer that wasn't received from is drained before reuse
+	_, stop = clock.AfterStop(real, 1*time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	stop()
+
+	c, stop := clock.AfterStop(real, 1*time.Hour)
+	defer stop()
+
+	select {
+	case <-c:
+		t.Error("time sent unexpectedly")
+	case <-time.After(notSentTimeout):
+	}
+}
+
+func TestRealTick_Collected(t *testing.T) {
+	tick := clock.NewRealClock().Tick(time.Millisecond)
+	c := tick()
+	<-c
+
+	// once the function is collected, its ticker is stopped
+	tick = nil
+	deadline := time.Now().Add(time.Second)
+	for {
+		runtime.GC()
+		for len(c) > 0 {
+			<-c
+		}
+
+		select {
+		case <-c:
+		case <-time.After(20 * time.Millisecond):
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timeout: the ticker of an abandoned Tick still ticks")
+		}
+	}
+}
+
+func TestTickStop(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	c, stop := clock.TickStop(fake, 1*time.Second)
+	for i := 1; i <= 3; i++ {
+		assertClockUntil(t, 1, fake)
+		fake.Advance(1 * time.Second)
+		assertSent(t, start.Add(time.Duration(i)*time.Second), c)
+	}
+
+	stop()
+	if timers := fake.PendingTimers(); len(timers) != 0 {
+		t.Errorf("expected no pending timers got %v", timers)
+	}
+
+	fake.Advance(1 * time.Second)
+	assertNotSent(t, c)
+
+	if c, stop := clock.TickStop(fake, 0); c != nil {
+		t.Error("expected a nil channel")
+	} else {
+		stop()
+	}
+}
@@ -0,0 +1,132 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Alarm fires at a wall-clock time of day, every day.
+type Alarm struct {
+	clock  Clock
+	hour   int
+	minute int
+	loc    *time.Location
+	c      chan time.Time
+
+	stop chan struct{}
+	done chan struct{}
+
+	mutex   sync.Mutex
+	day     time.Time
+	next    time.Time
+	stopped bool
+}
+
+// At returns an Alarm driven by clock that fires at the next occurrence of
+// hour:minute in loc, then daily at that wall time.
+//
+// Occurrences are computed on calendar days, so they follow daylight saving
+// time transitions: the alarm fires once a day even when the day is 23 or 25
+// hours long. If the wall time doesn't exist on a day, because clocks
+// spring forward over it, the alarm fires as much later as the clocks
+// skipped, such as 03:30 instead of 02:30. Like a Ticker, the alarm drops firings that the
+// consumer isn't ready to receive.
+//
+// At panics if hour or minute is out of range.
+func At(clock Clock, hour, minute int, loc *time.Location) *Alarm {
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		panic("clock: time of day out of range for At")
+	}
+
+	alarm := &Alarm{
+		clock:  clock,
+		hour:   hour,
+		minute: minute,
+		loc:    loc,
+		c:      make(chan time.Time, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	now := clock.Now().In(loc)
+	alarm.day = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	alarm.next = alarm.occurrence()
+	if !alarm.next.After(now) {
+		alarm.advance()
+	}
+
+	go alarm.run()
+	return alarm
+}
+
+// C returns the channel on which the alarm is delivered.
+func (alarm *Alarm) C() <-chan time.Time {
+	return alarm.c
+}
+
+// Next returns the time at which the alarm fires next.
+func (alarm *Alarm) Next() time.Time {
+	alarm.mutex.Lock()
+	defer alarm.mutex.Unlock()
+
+	return alarm.next
+}
+
+// Stop turns off the alarm. After Stop, no more alarms will be sent.
+func (alarm *Alarm) Stop() {
+	alarm.mutex.Lock()
+	if !alarm.stopped {
+		alarm.stopped = true
+		close(alarm.stop)
+	}
+	alarm.mutex.Unlock()
+
+	<-alarm.done
+}
+
+func (alarm *Alarm) run() {
+	defer close(alarm.done)
+
+	alarm.mutex.Lock()
+	timer := alarm.clock.NewTimer(alarm.next.Sub(alarm.clock.Now()))
+	alarm.mutex.Unlock()
+
+	defer timer.Stop()
+
+	for {
+		select {
+		case at := <-timer.C():
+			select {
+			case alarm.c <- at:
+			default:
+			}
+
+			alarm.mutex.Lock()
+			alarm.advance()
+			timer.Reset(alarm.next.Sub(alarm.clock.Now()))
+			alarm.mutex.Unlock()
+		case <-alarm.stop:
+			return
+		}
+	}
+}
+
+// advance moves the alarm to the next calendar day.
+func (alarm *Alarm) advance() {
+	alarm.day = alarm.day.AddDate(0, 0, 1)
+	alarm.next = alarm.occurrence()
+}
+
+func (alarm *Alarm) occurrence() time.Time {
+	year, month, day := alarm.day.Date()
+	t := time.Date(year, month, day, alarm.hour, alarm.minute, 0, 0, alarm.loc)
+
+	// time.Date may resolve a wall time skipped by a transition to an
+	// instant before it, so move such instants forward past the gap
+	wall := time.Date(year, month, day, alarm.hour, alarm.minute, 0, 0, time.UTC)
+	actual := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	if gap := wall.Sub(actual); gap > 0 {
+		t = t.Add(gap)
+	}
+	return t
+}
@@ -0,0 +1,95 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+// assertAlarm advances the clock to the alarm's next firing,
+// checking that it's expected and that the alarm fires then and not before.
+func assertAlarm(t *testing.T, fake clock.FakeClock, alarm *clock.Alarm, expected time.Time) {
+	t.Helper()
+
+	next := alarm.Next()
+	if !next.Equal(expected) {
+		t.Fatalf("expected next alarm at %s got %s", expected, next)
+	}
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(next.Sub(fake.Now()) - time.Nanosecond)
+	assertNotSent(t, alarm.C())
+
+	fake.Advance(time.Nanosecond)
+	assertSent(t, next, alarm.C())
+}
+
+func TestAt(t *testing.T) {
+	loc := loadLocation(t, "Europe/Paris")
+	fake := clock.NewFakeClockAt(time.Date(2024, 6, 1, 10, 0, 0, 0, loc))
+
+	alarm := clock.At(fake, 9, 0, loc)
+	defer alarm.Stop()
+
+	assertAlarm(t, fake, alarm, time.Date(2024, 6, 2, 9, 0, 0, 0, loc))
+	assertAlarm(t, fake, alarm, time.Date(2024, 6, 3, 9, 0, 0, 0, loc))
+}
+
+func TestAt_SpringForward(t *testing.T) {
+	loc := loadLocation(t, "America/New_York")
+	fake := clock.NewFakeClockAt(time.Date(2024, 3, 9, 12, 0, 0, 0, loc))
+
+	alarm := clock.At(fake, 2, 30, loc)
+	defer alarm.Stop()
+
+	// 02:30 doesn't exist on March 10, so the alarm fires after the transition
+	first := alarm.Next()
+	if first.Day() != 10 || first.Hour() != 3 || first.Minute() != 30 {
+		t.Errorf("expected the alarm at 03:30 on March 10 got %s", first)
+	}
+
+	assertAlarm(t, fake, alarm, first)
+	assertAlarm(t, fake, alarm, time.Date(2024, 3, 11, 2, 30, 0, 0, loc))
+}
+
+func TestAt_FallBack(t *testing.T) {
+	loc := loadLocation(t, "America/New_York")
+	fake := clock.NewFakeClockAt(time.Date(2024, 11, 2, 12, 0, 0, 0, loc))
+
+	alarm := clock.At(fake, 1, 30, loc)
+	defer alarm.Stop()
+
+	// 01:30 happens twice on November 3, but the alarm fires once
+	assertAlarm(t, fake, alarm, time.Date(2024, 11, 3, 1, 30, 0, 0, loc))
+
+	second := time.Date(2024, 11, 4, 1, 30, 0, 0, loc)
+	if d := second.Sub(fake.Now()); d != 25*time.Hour {
+		t.Errorf("expected %s until the next alarm got %s", 25*time.Hour, d)
+	}
+	assertAlarm(t, fake, alarm, second)
+}
+
+func TestAt_Stop(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	alarm := clock.At(fake, 0, 0, time.UTC)
+	assertClockUntil(t, 1, fake)
+
+	alarm.Stop()
+	alarm.Stop()
+
+	fake.Advance(24 * time.Hour)
+	assertNotSent(t, alarm.C())
+}
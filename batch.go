@@ -0,0 +1,135 @@
+package clock
+
+import "time"
+
+// A Deadline is a callback scheduled by AfterFuncs.
+type Deadline struct {
+	// D is the duration after which F is called.
+	D time.Duration
+	// F is called in its own goroutine.
+	F func()
+}
+
+// AfterFuncs is like calling clock.AfterFunc for each deadline, returning
+// the timers in the same order. On the fake clock, the deadlines are
+// registered under a single acquisition of its lock, and their timers are
+// allocated together.
+func AfterFuncs(clock Clock, deadlines []Deadline) []Timer {
+	if fake, ok := clock.(*fakeClock); ok {
+		return fake.afterFuncs(deadlines, fake.caller(1))
+	}
+
+	timers := make([]Timer, len(deadlines))
+	for i, deadline := range deadlines {
+		timers[i] = clock.AfterFunc(deadline.D, deadline.F)
+	}
+	return timers
+}
+
+// NewTimers is like calling clock.NewTimer for each duration, returning the
+// timers in the same order. On the fake clock, the timers are allocated
+// together.
+func NewTimers(clock Clock, ds ...time.Duration) []Timer {
+	if fake, ok := clock.(*fakeClock); ok {
+		return fake.newTimers(ds, fake.caller(1))
+	}
+
+	timers := make([]Timer, len(ds))
+	for i, d := range ds {
+		timers[i] = clock.NewTimer(d)
+	}
+	return timers
+}
+
+// StopTimers stops each timer, and returns how many were stopped before
+// they fired. Timers of the same fake clock are stopped under a single
+// acquisition of its lock.
+func StopTimers(timers []Timer) int {
+	stopped := 0
+
+	var fakes map[*fakeClock][]*fakeTimer
+	for _, timer := range timers {
+		if timer, ok := timer.(*fakeTimer); ok {
+			if fakes == nil {
+				fakes = map[*fakeClock][]*fakeTimer{}
+			}
+			fakes[timer.clock] = append(fakes[timer.clock], timer)
+			continue
+		}
+		if timer.Stop() {
+			stopped++
+		}
+	}
+
+	for clock, timers := range fakes {
+		stopped += clock.stopTimers(timers)
+	}
+	return stopped
+}
+
+func (clock *fakeClock) afterFuncs(deadlines []Deadline, pc uintptr) []Timer {
+	goid := clock.goroutine()
+	fakes := make([]fakeTimer, len(deadlines))
+	timers := make([]Timer, len(deadlines))
+	for i, deadline := range deadlines {
+		f := deadline.F
+		fakes[i] = fakeTimer{
+			clock: clock,
+			sleeper: sleeper{
+				f:      clock.callback(f, pc),
+				kind:   KindAfterFunc,
+				d:      deadline.D,
+				caller: pc,
+				goid:   goid,
+			},
+		}
+		timers[i] = &fakes[i]
+	}
+
+	clock.mutex.Lock()
+	defer clock.unlock()
+
+	for i := range fakes {
+		fakes[i].sleeper.until = clock.at.Add(fakes[i].sleeper.d)
+		clock.appendSleeper(&fakes[i].sleeper)
+	}
+	return timers
+}
+
+func (clock *fakeClock) newTimers(ds []time.Duration, pc uintptr) []Timer {
+	now, steps := clock.reading()
+	goid := clock.goroutine()
+
+	fakes := make([]fakeTimer, len(ds))
+	timers := make([]Timer, len(ds))
+	for i, d := range ds {
+		fakes[i] = fakeTimer{
+			clock: clock,
+			sleeper: sleeper{
+				i:      -1,
+				until:  now.Add(d),
+				c:      make(chan time.Time, 1),
+				kind:   KindTimer,
+				d:      d,
+				caller: pc,
+				goid:   goid,
+				steps:  steps,
+			},
+		}
+		timers[i] = &fakes[i]
+	}
+	return timers
+}
+
+func (clock *fakeClock) stopTimers(timers []*fakeTimer) int {
+	clock.mutex.Lock()
+	defer clock.unlock()
+
+	stopped := 0
+	for _, timer := range timers {
+		if timer.stop() {
+			stopped++
+		}
+	}
+	return stopped
+}
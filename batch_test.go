@@ -0,0 +1,101 @@
+package clock_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestAfterFuncs(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	fired := make(chan int, 3)
+	deadlines := make([]clock.Deadline, 3)
+	for i := range deadlines {
+		i := i
+		deadlines[i] = clock.Deadline{D: time.Duration(i+1) * time.Second, F: func() { fired <- i }}
+	}
+
+	timers := clock.AfterFuncs(fake, deadlines)
+	if len(timers) != 3 {
+		t.Fatalf("expected 3 timers, got %d", len(timers))
+	}
+
+	pending := fake.PendingTimers()
+	if len(pending) != 3 {
+		t.Fatalf("expected 3 pending timers, got %d", len(pending))
+	}
+	if !strings.Contains(pending[0].Caller, "batch_test.go") {
+		t.Errorf("expected the caller of AfterFuncs, got %s", pending[0].Caller)
+	}
+
+	timers[1].Stop()
+	fake.Advance(3 * time.Second)
+
+	got := map[int]bool{}
+	for len(got) < 2 {
+		select {
+		case i := <-fired:
+			got[i] = true
+		case <-time.After(sentTimeout):
+			t.Fatalf("timeout: callbacks run: %v", got)
+		}
+	}
+	if !got[0] || !got[2] {
+		t.Errorf("expected callbacks 0 and 2 to run, got %v", got)
+	}
+}
+
+func TestNewTimers(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	timers := clock.NewTimers(fake, time.Second, 2*time.Second)
+	c0, c1 := timers[0].C(), timers[1].C()
+
+	fake.Advance(time.Second)
+	assertSent(t, start.Add(time.Second), c0)
+	assertNotSent(t, c1)
+	fake.Advance(time.Second)
+	assertSent(t, start.Add(2*time.Second), c1)
+}
+
+func TestStopTimers(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	timers := clock.NewTimers(fake, time.Second, 2*time.Second)
+	timers = append(timers, clock.AfterFuncs(fake, []clock.Deadline{{D: time.Second, F: func() {}}})...)
+	timers = append(timers, clock.NewTimers(clock.NewRealClock(), time.Hour)...)
+
+	fake.Advance(time.Second)
+	if stopped := clock.StopTimers(timers); stopped != 2 {
+		t.Errorf("expected 2 timers stopped, got %d", stopped)
+	}
+	if pending := fake.PendingTimers(); len(pending) != 0 {
+		t.Errorf("expected no pending timers, got %v", pending)
+	}
+}
+
+func BenchmarkFakeClock_AfterFunc(b *testing.B) {
+	fake := clock.NewFakeClock()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		fake.AfterFunc(time.Second, func() {})
+	}
+}
+
+func BenchmarkFakeClock_AfterFuncs(b *testing.B) {
+	fake := clock.NewFakeClock()
+	deadlines := make([]clock.Deadline, 1000)
+	for i := range deadlines {
+		deadlines[i] = clock.Deadline{D: time.Second, F: func() {}}
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i += len(deadlines) {
+		clock.AfterFuncs(fake, deadlines)
+	}
+}
@@ -0,0 +1,80 @@
+package clock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BlockError is returned by BlockUntilContext when it gives up waiting.
+// It describes the sleepers that were waiting on the clock at the time,
+// so a test that expected n of them can tell which one never showed up.
+type BlockError struct {
+	// N is the number of sleepers that were expected.
+	N int
+
+	// Now is the time of the clock when the wait was given up.
+	Now time.Time
+
+	// Sleepers are the sleepers waiting on the clock, ordered by deadline.
+	Sleepers []PendingTimer
+
+	// Err is the error of the context, context.Canceled or
+	// context.DeadlineExceeded.
+	Err error
+}
+
+func (err *BlockError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "clock: %s waiting for %d blocked waiters, found %d at %s", err.Err, err.N, len(err.Sleepers), err.Now)
+	for _, sleeper := range err.Sleepers {
+		b.WriteString("\n\t")
+		b.WriteString(sleeper.String())
+	}
+	return b.String()
+}
+
+// Unwrap returns the error of the context, so errors.Is(err,
+// context.DeadlineExceeded) holds when the wait timed out.
+func (err *BlockError) Unwrap() error {
+	return err.Err
+}
+
+func (clock *fakeClock) BlockUntilContext(ctx context.Context, n int) error {
+	done := clock.Until(n)
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	clock.mutex.Lock()
+	clock.removeBlocker(done)
+	clock.mutex.Unlock()
+
+	// the sleepers may have shown up while the context was done
+	select {
+	case <-done:
+		return nil
+	default:
+	}
+
+	return &BlockError{
+		N:        n,
+		Now:      clock.Now(),
+		Sleepers: clock.PendingTimers(),
+		Err:      ctx.Err(),
+	}
+}
+
+// removeBlocker removes the blocker closing done, if it's still waiting.
+func (clock *fakeClock) removeBlocker(done <-chan struct{}) {
+	for i, blocker := range clock.blockers {
+		if blocker.done == done {
+			clock.blockers = append(clock.blockers[:i], clock.blockers[i+1:]...)
+			return
+		}
+	}
+}
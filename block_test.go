@@ -0,0 +1,45 @@
+package clock_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestBlockUntilContext(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	go fake.Sleep(time.Second)
+	if err := fake.BlockUntilContext(context.Background(), 1); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := fake.BlockUntilContext(ctx, 2)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error got %v", err)
+	}
+
+	var blockErr *clock.BlockError
+	if !errors.As(err, &blockErr) {
+		t.Fatalf("expected a *BlockError got %T", err)
+	}
+	if blockErr.N != 2 || len(blockErr.Sleepers) != 1 || blockErr.Sleepers[0].Kind != clock.KindSleep {
+		t.Errorf("unexpected error %+v", blockErr)
+	}
+	if message := err.Error(); !strings.Contains(message, "found 1") || !strings.Contains(message, "Sleep(1s)") {
+		t.Errorf("expected the sleeper in the message got %q", message)
+	}
+
+	// the blocker given up on is no longer waiting
+	if blockers := fake.Stats().Blockers; blockers != 0 {
+		t.Errorf("expected no blockers got %d", blockers)
+	}
+	fake.Advance(time.Second)
+}
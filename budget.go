@@ -0,0 +1,106 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Budget tracks a total allowance of time measured by a clock, which can be
+// split across the phases of a task with child budgets.
+//
+// Time elapsed on the clock counts against the budget, as does time reserved
+// explicitly with Consume. A child budget never outlasts its parent.
+type Budget struct {
+	clock  Clock
+	parent *Budget
+	start  time.Time
+	limit  time.Time
+
+	mutex    sync.Mutex
+	consumed time.Duration
+}
+
+// NewBudget returns a Budget of total, starting now.
+func NewBudget(clock Clock, total time.Duration) *Budget {
+	now := clock.Now()
+	return &Budget{
+		clock: clock,
+		start: now,
+		limit: now.Add(total),
+	}
+}
+
+// NewBudgetContext returns a Budget of total, starting now,
+// which runs out no later than the deadline of ctx, if any.
+func NewBudgetContext(ctx context.Context, clock Clock, total time.Duration) *Budget {
+	b := NewBudget(clock, total)
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(b.limit) {
+		b.limit = deadline
+	}
+	return b
+}
+
+// Child returns a budget of d for a phase of the task, starting now.
+// The child runs out when either it or b does, and time it consumes is
+// also consumed from b.
+func (b *Budget) Child(d time.Duration) *Budget {
+	child := NewBudget(b.clock, d)
+	child.parent = b
+	return child
+}
+
+// Fraction returns a child budget of the given fraction of the time remaining.
+func (b *Budget) Fraction(f float64) *Budget {
+	return b.Child(time.Duration(float64(b.Remaining()) * f))
+}
+
+// Deadline returns the time at which the budget runs out.
+func (b *Budget) Deadline() time.Time {
+	b.mutex.Lock()
+	deadline := b.limit.Add(-b.consumed)
+	b.mutex.Unlock()
+
+	if b.parent != nil {
+		if parent := b.parent.Deadline(); parent.Before(deadline) {
+			deadline = parent
+		}
+	}
+	return deadline
+}
+
+// Remaining returns the time left in the budget, which is never negative.
+func (b *Budget) Remaining() time.Duration {
+	d := b.Deadline().Sub(b.clock.Now())
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// Elapsed returns the time elapsed since the budget was created.
+func (b *Budget) Elapsed() time.Duration {
+	return b.clock.Since(b.start)
+}
+
+// Exhausted reports whether the budget has run out.
+func (b *Budget) Exhausted() bool {
+	return b.Remaining() == 0
+}
+
+// Consume reserves d from the budget and its ancestors,
+// and reports whether any time remains afterwards.
+func (b *Budget) Consume(d time.Duration) bool {
+	for budget := b; budget != nil; budget = budget.parent {
+		budget.mutex.Lock()
+		budget.consumed += d
+		budget.mutex.Unlock()
+	}
+	return !b.Exhausted()
+}
+
+// WithContext returns a copy of ctx that is canceled when the budget runs
+// out, as of now. Later calls to Consume don't affect the returned context.
+func (b *Budget) WithContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return WithDeadline(ctx, b.clock, b.Deadline())
+}
@@ -0,0 +1,88 @@
+package clock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func assertRemaining(t *testing.T, expected time.Duration, b *clock.Budget) {
+	t.Helper()
+
+	if remaining := b.Remaining(); remaining != expected {
+		t.Errorf("expected %s remaining got %s", expected, remaining)
+	}
+}
+
+func TestBudget(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	b := clock.NewBudget(fake, 10*time.Second)
+	assertRemaining(t, 10*time.Second, b)
+
+	fake.Advance(2 * time.Second)
+	assertRemaining(t, 8*time.Second, b)
+
+	if !b.Consume(3 * time.Second) {
+		t.Error("expected the budget to remain")
+	}
+	assertRemaining(t, 5*time.Second, b)
+
+	if elapsed := b.Elapsed(); elapsed != 2*time.Second {
+		t.Errorf("expected %s elapsed got %s", 2*time.Second, elapsed)
+	}
+
+	if b.Consume(5 * time.Second) {
+		t.Error("expected the budget to be exhausted")
+	}
+	assertRemaining(t, 0, b)
+}
+
+func TestBudget_Child(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	b := clock.NewBudget(fake, 10*time.Second)
+
+	half := b.Fraction(0.5)
+	assertRemaining(t, 5*time.Second, half)
+
+	half.Consume(1 * time.Second)
+	assertRemaining(t, 4*time.Second, half)
+	assertRemaining(t, 9*time.Second, b)
+
+	// a child is capped by its parent
+	long := b.Child(1 * time.Minute)
+	assertRemaining(t, 9*time.Second, long)
+
+	fake.Advance(4 * time.Second)
+	if !half.Exhausted() {
+		t.Error("expected the child budget to be exhausted")
+	}
+	assertRemaining(t, 5*time.Second, b)
+}
+
+func TestBudget_Context(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	parent, cancel := clock.WithTimeout(context.Background(), fake, 5*time.Second)
+	defer cancel()
+
+	b := clock.NewBudgetContext(parent, fake, 10*time.Second)
+	assertRemaining(t, 5*time.Second, b)
+
+	ctx, cancel := b.Fraction(0.5).WithContext(context.Background())
+	defer cancel()
+
+	fake.Advance(2500 * time.Millisecond)
+	select {
+	case <-ctx.Done():
+	case <-time.After(closedTimeout):
+		t.Fatal("timeout: budget context was not canceled")
+	}
+
+	if err := ctx.Err(); err != context.DeadlineExceeded {
+		t.Errorf("expected %v got %v", context.DeadlineExceeded, err)
+	}
+}
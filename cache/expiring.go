@@ -0,0 +1,263 @@
+// Package cache provides caches whose entries expire according to a clock.Clock.
+package cache
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// An EvictReason tells why an entry left an ExpiringMap.
+type EvictReason int
+
+const (
+	// Expired entries reached the end of their TTL.
+	Expired EvictReason = iota
+
+	// Deleted entries were removed by Delete.
+	Deleted
+
+	// Replaced entries were overwritten by Set.
+	Replaced
+)
+
+func (reason EvictReason) String() string {
+	switch reason {
+	case Expired:
+		return "expired"
+	case Deleted:
+		return "deleted"
+	case Replaced:
+		return "replaced"
+	default:
+		return "unknown"
+	}
+}
+
+// ExpiringMap is a map whose entries expire after a per-entry TTL measured by a clock.
+//
+// Expired entries are never returned (lazy expiration),
+// and are removed by a clock timer armed for the earliest expiry (active expiration),
+// which calls the eviction callback.
+// It is safe for concurrent use.
+type ExpiringMap[K comparable, V any] struct {
+	clock   clock.Clock
+	onEvict func(K, V, EvictReason)
+
+	mutex   sync.Mutex
+	entries map[K]*entry[K, V]
+	queue   entryQueue[K, V]
+	timer   clock.Timer
+	closed  bool
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+	index   int
+}
+
+// NewExpiringMap returns an empty ExpiringMap measuring TTLs by clock.
+func NewExpiringMap[K comparable, V any](clock clock.Clock) *ExpiringMap[K, V] {
+	return &ExpiringMap[K, V]{
+		clock:   clock,
+		entries: map[K]*entry[K, V]{},
+	}
+}
+
+// OnEvict sets a callback called, outside of the map's lock,
+// every time an entry leaves the map.
+func (m *ExpiringMap[K, V]) OnEvict(f func(key K, value V, reason EvictReason)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.onEvict = f
+}
+
+// Set stores value under key, expiring after ttl.
+// A non-positive ttl stores nothing, deleting any previous entry.
+func (m *ExpiringMap[K, V]) Set(key K, value V, ttl time.Duration) {
+	evicted := m.set(key, value, ttl)
+	m.evict(evicted)
+}
+
+func (m *ExpiringMap[K, V]) set(key K, value V, ttl time.Duration) []eviction[K, V] {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var evicted []eviction[K, V]
+	if old, ok := m.entries[key]; ok {
+		m.remove(old)
+
+		// a non-positive ttl deletes the entry rather than replacing it
+		reason := Replaced
+		if ttl <= 0 {
+			reason = Deleted
+		}
+		evicted = append(evicted, eviction[K, V]{old, reason})
+	}
+
+	if ttl <= 0 {
+		return evicted
+	}
+
+	e := &entry[K, V]{
+		key:     key,
+		value:   value,
+		expires: m.clock.Now().Add(ttl),
+	}
+	m.entries[key] = e
+	heap.Push(&m.queue, e)
+	m.schedule()
+
+	return evicted
+}
+
+// Get returns the value stored under key, if it has not expired.
+func (m *ExpiringMap[K, V]) Get(key K) (V, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok || !m.clock.Now().Before(e.expires) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// TTL returns the time left before the entry under key expires.
+func (m *ExpiringMap[K, V]) TTL(key K) (time.Duration, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return 0, false
+	}
+	ttl := e.expires.Sub(m.clock.Now())
+	return ttl, ttl > 0
+}
+
+// Delete removes the entry under key.
+func (m *ExpiringMap[K, V]) Delete(key K) {
+	m.mutex.Lock()
+	e, ok := m.entries[key]
+	if ok {
+		m.remove(e)
+	}
+	m.mutex.Unlock()
+
+	if ok {
+		m.evict([]eviction[K, V]{{e, Deleted}})
+	}
+}
+
+// Len returns the number of entries that have not expired.
+func (m *ExpiringMap[K, V]) Len() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := m.clock.Now()
+	n := 0
+	for _, e := range m.entries {
+		if now.Before(e.expires) {
+			n++
+		}
+	}
+	return n
+}
+
+// Close stops active expiration. Entries still expire lazily.
+func (m *ExpiringMap[K, V]) Close() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.closed = true
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+}
+
+func (m *ExpiringMap[K, V]) remove(e *entry[K, V]) {
+	delete(m.entries, e.key)
+	heap.Remove(&m.queue, e.index)
+}
+
+// schedule arms the timer for the earliest expiry.
+func (m *ExpiringMap[K, V]) schedule() {
+	if m.closed || len(m.queue) == 0 {
+		return
+	}
+
+	d := m.queue[0].expires.Sub(m.clock.Now())
+	if m.timer == nil {
+		m.timer = m.clock.AfterFunc(d, m.expire)
+		return
+	}
+	m.timer.Reset(d)
+}
+
+func (m *ExpiringMap[K, V]) expire() {
+	m.mutex.Lock()
+	now := m.clock.Now()
+	var evicted []eviction[K, V]
+	for len(m.queue) > 0 && !now.Before(m.queue[0].expires) {
+		e := heap.Pop(&m.queue).(*entry[K, V])
+		delete(m.entries, e.key)
+		evicted = append(evicted, eviction[K, V]{e, Expired})
+	}
+	m.schedule()
+	m.mutex.Unlock()
+
+	m.evict(evicted)
+}
+
+type eviction[K comparable, V any] struct {
+	entry  *entry[K, V]
+	reason EvictReason
+}
+
+func (m *ExpiringMap[K, V]) evict(evicted []eviction[K, V]) {
+	m.mutex.Lock()
+	onEvict := m.onEvict
+	m.mutex.Unlock()
+
+	if onEvict == nil {
+		return
+	}
+	for _, e := range evicted {
+		onEvict(e.entry.key, e.entry.value, e.reason)
+	}
+}
+
+// entryQueue is a min-heap of entries ordered by expiry.
+type entryQueue[K comparable, V any] []*entry[K, V]
+
+func (q entryQueue[K, V]) Len() int { return len(q) }
+
+func (q entryQueue[K, V]) Less(i, j int) bool { return q[i].expires.Before(q[j].expires) }
+
+func (q entryQueue[K, V]) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *entryQueue[K, V]) Push(x any) {
+	e := x.(*entry[K, V])
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *entryQueue[K, V]) Pop() any {
+	old := *q
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return e
+}
@@ -0,0 +1,138 @@
+package cache_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/cache"
+)
+
+const timeout = 100 * time.Millisecond
+
+func TestExpiringMap_Get(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	m := cache.NewExpiringMap[string, int](fake)
+	defer m.Close()
+
+	m.Set("a", 1, 2*time.Second)
+
+	if v, ok := m.Get("a"); !ok || v != 1 {
+		t.Errorf("expected %d got %d", 1, v)
+	}
+	if ttl, ok := m.TTL("a"); !ok || ttl != 2*time.Second {
+		t.Errorf("expected ttl %s got %s", 2*time.Second, ttl)
+	}
+
+	fake.Advance(2 * time.Second)
+
+	if _, ok := m.Get("a"); ok {
+		t.Error("expected entry to be expired")
+	}
+	if n := m.Len(); n != 0 {
+		t.Errorf("expected %d entries got %d", 0, n)
+	}
+}
+
+type evictions struct {
+	mutex   sync.Mutex
+	reasons map[string]bool
+	done    chan struct{}
+	n       int
+}
+
+func newEvictions(n int) *evictions {
+	return &evictions{
+		reasons: map[string]bool{},
+		done:    make(chan struct{}),
+		n:       n,
+	}
+}
+
+func (e *evictions) record(key string, value int, reason cache.EvictReason) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.reasons[fmt.Sprintf("%s=%d %s", key, value, reason)] = true
+	if len(e.reasons) == e.n {
+		close(e.done)
+	}
+}
+
+func (e *evictions) wait(t *testing.T) map[string]bool {
+	t.Helper()
+
+	select {
+	case <-e.done:
+	case <-time.After(timeout):
+		t.Fatal("timeout: waiting for evictions")
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.reasons
+}
+
+func TestExpiringMap_ActiveExpiration(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	m := cache.NewExpiringMap[string, int](fake)
+	defer m.Close()
+
+	evicted := newEvictions(4)
+	m.OnEvict(evicted.record)
+
+	m.Set("a", 1, 1*time.Second)
+	m.Set("b", 2, 3*time.Second)
+	m.Set("c", 3, 2*time.Second)
+	m.Set("c", 4, 2*time.Second)
+	m.Delete("b")
+
+	fake.BlockUntil(1)
+	fake.Advance(2 * time.Second)
+
+	reasons := evicted.wait(t)
+	for _, expected := range []string{"a=1 expired", "b=2 deleted", "c=3 replaced", "c=4 expired"} {
+		if !reasons[expected] {
+			t.Errorf("expected eviction %q in %v", expected, reasons)
+		}
+	}
+
+	if n := m.Len(); n != 0 {
+		t.Errorf("expected %d entries got %d", 0, n)
+	}
+}
+
+func TestExpiringMap_SetNonPositive(t *testing.T) {
+	m := cache.NewExpiringMap[string, int](clock.NewFakeClock())
+	defer m.Close()
+
+	evicted := newEvictions(1)
+	m.OnEvict(evicted.record)
+
+	m.Set("a", 1, time.Second)
+	m.Set("a", 2, 0)
+
+	if reasons := evicted.wait(t); !reasons["a=1 deleted"] {
+		t.Errorf("expected eviction %q in %v", "a=1 deleted", reasons)
+	}
+	if _, ok := m.Get("a"); ok {
+		t.Error("expected no entry")
+	}
+}
+
+func TestExpiringMap_Close(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	m := cache.NewExpiringMap[string, int](fake)
+	m.Set("a", 1, 1*time.Second)
+	m.Close()
+
+	if n := len(fake.PendingTimers()); n != 0 {
+		t.Errorf("expected %d pending timers got %d", 0, n)
+	}
+}
@@ -0,0 +1,161 @@
+// Package calendar computes business hours, and schedules timers that only run during them,
+// against a clock.Clock.
+package calendar
+
+import (
+	"sort"
+	"time"
+)
+
+// Hours is a working period within a day, as offsets from midnight.
+// Offsets are wall clock times, so 9*time.Hour is 09:00 even on days with a DST change.
+type Hours struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// maxSearchDays bounds how far ahead the calendar looks for business hours.
+const maxSearchDays = 2 * 366
+
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+// A Calendar describes the business hours of a week, and holidays without any.
+// A Calendar must not be modified while it's being read.
+type Calendar struct {
+	location *time.Location
+	week     [7][]Hours
+	holidays map[date]bool
+}
+
+// New returns a Calendar in the given location, without any business hours.
+func New(location *time.Location) *Calendar {
+	return &Calendar{
+		location: location,
+		holidays: map[date]bool{},
+	}
+}
+
+// NewWorkWeek returns a Calendar in the given location
+// whose business hours are 09:00 to 17:00, Monday to Friday.
+func NewWorkWeek(location *time.Location) *Calendar {
+	c := New(location)
+	for day := time.Monday; day <= time.Friday; day++ {
+		c.SetHours(day, Hours{Start: 9 * time.Hour, End: 17 * time.Hour})
+	}
+	return c
+}
+
+// SetHours replaces the business hours of day.
+// Empty or inverted periods are ignored.
+func (c *Calendar) SetHours(day time.Weekday, hours ...Hours) *Calendar {
+	var valid []Hours
+	for _, h := range hours {
+		if h.Start < h.End {
+			valid = append(valid, h)
+		}
+	}
+	sort.Slice(valid, func(i, j int) bool { return valid[i].Start < valid[j].Start })
+
+	c.week[day] = valid
+	return c
+}
+
+// AddHoliday marks a date as having no business hours.
+func (c *Calendar) AddHoliday(year int, month time.Month, day int) *Calendar {
+	c.holidays[date{year, month, day}] = true
+	return c
+}
+
+// IsBusinessInstant reports whether t is within business hours.
+func (c *Calendar) IsBusinessInstant(t time.Time) bool {
+	next, ok := c.NextBusinessInstant(t)
+	return ok && next.Equal(t)
+}
+
+// NextBusinessInstant returns t if it's within business hours,
+// or the start of the next business hours otherwise.
+// The boolean is false if the calendar has no business hours in the following two years.
+func (c *Calendar) NextBusinessInstant(t time.Time) (time.Time, bool) {
+	start, _, ok := c.next(t)
+	return start, ok
+}
+
+// AddBusinessDuration returns the instant at which d of business time has passed since t.
+// The boolean is false if the calendar runs out of business hours.
+func (c *Calendar) AddBusinessDuration(t time.Time, d time.Duration) (time.Time, bool) {
+	for {
+		start, end, ok := c.next(t)
+		if !ok {
+			return time.Time{}, false
+		}
+
+		available := end.Sub(start)
+		if d <= available {
+			return start.Add(d), true
+		}
+		d -= available
+		t = end
+	}
+}
+
+// BusinessDuration returns the business time between from and to.
+func (c *Calendar) BusinessDuration(from, to time.Time) time.Duration {
+	var total time.Duration
+	for from.Before(to) {
+		start, end, ok := c.next(from)
+		if !ok || !start.Before(to) {
+			break
+		}
+		if end.After(to) {
+			end = to
+		}
+		total += end.Sub(start)
+		from = end
+	}
+	return total
+}
+
+// next returns the business period containing t, starting at t,
+// or the following business period if t is outside of business hours.
+func (c *Calendar) next(t time.Time) (time.Time, time.Time, bool) {
+	t = t.In(c.location)
+	year, month, day := t.Date()
+
+	for i := 0; i < maxSearchDays; i++ {
+		midnight := time.Date(year, month, day+i, 0, 0, 0, 0, c.location)
+		y, m, d := midnight.Date()
+		if c.holidays[date{y, m, d}] {
+			continue
+		}
+
+		for _, hours := range c.week[midnight.Weekday()] {
+			start := at(y, m, d, hours.Start, c.location)
+			end := at(y, m, d, hours.End, c.location)
+			if !t.Before(end) {
+				continue
+			}
+			if t.After(start) {
+				start = t
+			}
+			return start, end, true
+		}
+	}
+
+	return time.Time{}, time.Time{}, false
+}
+
+// at returns the wall clock time offset from midnight on the given date.
+func at(year int, month time.Month, day int, offset time.Duration, location *time.Location) time.Time {
+	hour := offset / time.Hour
+	offset -= hour * time.Hour
+	minute := offset / time.Minute
+	offset -= minute * time.Minute
+	second := offset / time.Second
+	offset -= second * time.Second
+
+	return time.Date(year, month, day, int(hour), int(minute), int(second), int(offset), location)
+}
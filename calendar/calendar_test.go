@@ -0,0 +1,109 @@
+package calendar_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/calendar"
+	"github.com/go-toolbelt/clock/clocktest"
+)
+
+const timeout = 100 * time.Millisecond
+
+// monday is Monday, 6 January 2020, at 00:00 UTC.
+var monday = time.Date(2020, time.January, 6, 0, 0, 0, 0, time.UTC)
+
+func TestNextBusinessInstant(t *testing.T) {
+	cal := calendar.NewWorkWeek(time.UTC)
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		expected time.Time
+	}{
+		{"before opening", monday.Add(8 * time.Hour), monday.Add(9 * time.Hour)},
+		{"during hours", monday.Add(10 * time.Hour), monday.Add(10 * time.Hour)},
+		{"at closing", monday.Add(17 * time.Hour), monday.Add(33 * time.Hour)},
+		{"friday evening", monday.Add(4*24*time.Hour + 18*time.Hour), monday.Add(7*24*time.Hour + 9*time.Hour)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, ok := cal.NextBusinessInstant(test.t)
+			if !ok || actual != test.expected {
+				t.Errorf("expected %s got %s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestHoliday(t *testing.T) {
+	cal := calendar.NewWorkWeek(time.UTC).AddHoliday(2020, time.January, 6)
+
+	if cal.IsBusinessInstant(monday.Add(10 * time.Hour)) {
+		t.Error("expected holiday to have no business hours")
+	}
+
+	expected := monday.Add(33 * time.Hour)
+	if actual, ok := cal.NextBusinessInstant(monday); !ok || actual != expected {
+		t.Errorf("expected %s got %s", expected, actual)
+	}
+}
+
+func TestAddBusinessDuration(t *testing.T) {
+	cal := calendar.NewWorkWeek(time.UTC)
+
+	// 4h on Friday afternoon, then 6h on Monday morning
+	friday := monday.Add(4*24*time.Hour + 13*time.Hour)
+	expected := monday.Add(7*24*time.Hour + 15*time.Hour)
+
+	if actual, ok := cal.AddBusinessDuration(friday, 10*time.Hour); !ok || actual != expected {
+		t.Errorf("expected %s got %s", expected, actual)
+	}
+	if actual := cal.BusinessDuration(friday, expected); actual != 10*time.Hour {
+		t.Errorf("expected %s got %s", 10*time.Hour, actual)
+	}
+}
+
+func TestAddBusinessDuration_NoHours(t *testing.T) {
+	cal := calendar.New(time.UTC)
+
+	if _, ok := cal.AddBusinessDuration(monday, 1*time.Hour); ok {
+		t.Error("expected no business hours")
+	}
+}
+
+func TestAddBusinessDuration_DST(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// Daylight saving time starts on Sunday, 29 March 2020
+	cal := calendar.NewWorkWeek(location)
+	friday := time.Date(2020, time.March, 27, 16, 0, 0, 0, location)
+	expected := time.Date(2020, time.March, 30, 10, 0, 0, 0, location)
+
+	if actual, ok := cal.AddBusinessDuration(friday, 2*time.Hour); !ok || !actual.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, actual)
+	}
+}
+
+func TestNewTimer(t *testing.T) {
+	// Friday at 16:00
+	start := monday.Add(4*24*time.Hour + 16*time.Hour)
+	fake := clock.NewFakeClockAt(start)
+
+	cal := calendar.NewWorkWeek(time.UTC)
+	timer := cal.NewTimer(fake, 2*time.Hour)
+	c := timer.C()
+
+	// the timer pauses over the weekend
+	expected := monday.Add(7*24*time.Hour + 10*time.Hour)
+	fake.BlockUntil(1)
+	fake.Advance(expected.Sub(start) - time.Second)
+	clocktest.RequireNoFireFor(t, c, timeout)
+
+	fake.Advance(1 * time.Second)
+	clocktest.RequireFiresAtWithin(t, expected, c, timeout)
+}
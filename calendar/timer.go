@@ -0,0 +1,56 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// NewTimer returns a Timer that fires once d of business time has passed, as measured by c.
+// Time outside of business hours doesn't count towards d,
+// which is what SLA timers that pause overnight need.
+// If the calendar runs out of business hours, the timer never fires.
+func (cal *Calendar) NewTimer(c clock.Clock, d time.Duration) clock.Timer {
+	timer := &businessTimer{
+		calendar: cal,
+		clock:    c,
+	}
+	timer.Timer = c.NewTimer(timer.delay(d))
+	return timer
+}
+
+// AfterFunc is like NewTimer, but calls f in its own goroutine when the timer fires.
+func (cal *Calendar) AfterFunc(c clock.Clock, d time.Duration, f func()) clock.Timer {
+	timer := &businessTimer{
+		calendar: cal,
+		clock:    c,
+	}
+	timer.Timer = c.AfterFunc(timer.delay(d), f)
+	return timer
+}
+
+type businessTimer struct {
+	clock.Timer
+
+	calendar *Calendar
+	clock    clock.Clock
+}
+
+// never is the delay used when the calendar has no business hours left.
+const never = time.Duration(1<<63 - 1)
+
+// delay returns the clock time until d of business time has passed.
+func (timer *businessTimer) delay(d time.Duration) time.Duration {
+	now := timer.clock.Now()
+
+	deadline, ok := timer.calendar.AddBusinessDuration(now, d)
+	if !ok {
+		return never
+	}
+	return deadline.Sub(now)
+}
+
+// Reset changes the timer to fire once d of business time has passed.
+func (timer *businessTimer) Reset(d time.Duration) bool {
+	return timer.Timer.Reset(timer.delay(d))
+}
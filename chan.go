@@ -0,0 +1,75 @@
+package clock
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrClosed is returned by RecvTimeout when the channel is closed.
+var ErrClosed = errors.New("clock: receive from closed channel")
+
+// RecvTimeout receives a value from c, waiting at most d as measured by clock.
+// It returns ErrClosed if c is closed, and a *TimeoutError once d elapses.
+// If d <= 0, it only receives a value that's immediately available.
+func RecvTimeout[T any](clock Clock, c <-chan T, d time.Duration) (T, error) {
+	select {
+	case v, ok := <-c:
+		return recv(v, ok)
+	default:
+	}
+
+	var zero T
+	if d <= 0 {
+		return zero, &TimeoutError{Limit: d}
+	}
+
+	start := clock.Now()
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case v, ok := <-c:
+		return recv(v, ok)
+	case <-timer.C():
+		return zero, &TimeoutError{
+			Limit:   d,
+			Elapsed: clock.Since(start),
+		}
+	}
+}
+
+func recv[T any](v T, ok bool) (T, error) {
+	if !ok {
+		return v, ErrClosed
+	}
+	return v, nil
+}
+
+// SendTimeout sends v on c, waiting at most d as measured by clock.
+// It returns a *TimeoutError once d elapses.
+// If d <= 0, it only sends if c is immediately ready.
+func SendTimeout[T any](clock Clock, c chan<- T, v T, d time.Duration) error {
+	select {
+	case c <- v:
+		return nil
+	default:
+	}
+
+	if d <= 0 {
+		return &TimeoutError{Limit: d}
+	}
+
+	start := clock.Now()
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case c <- v:
+		return nil
+	case <-timer.C():
+		return &TimeoutError{
+			Limit:   d,
+			Elapsed: clock.Since(start),
+		}
+	}
+}
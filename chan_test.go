@@ -0,0 +1,86 @@
+package clock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestRecvTimeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	c := make(chan int, 1)
+	c <- 1
+
+	if v, err := clock.RecvTimeout(fake, c, 1*time.Second); err != nil || v != 1 {
+		t.Errorf("expected %d got %d, %v", 1, v, err)
+	}
+
+	close(c)
+	if _, err := clock.RecvTimeout(fake, c, 1*time.Second); err != clock.ErrClosed {
+		t.Errorf("expected %v got %v", clock.ErrClosed, err)
+	}
+}
+
+func TestRecvTimeout_Timeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := clock.RecvTimeout(fake, make(chan int), 1*time.Second)
+		errs <- err
+	}()
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected a timeout got %v", err)
+		}
+	case <-time.After(sentTimeout):
+		t.Fatal("timeout: RecvTimeout did not return")
+	}
+}
+
+func TestRecvTimeout_Zero(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	if _, err := clock.RecvTimeout(fake, make(chan int), 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a timeout got %v", err)
+	}
+}
+
+func TestSendTimeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	c := make(chan int, 1)
+	if err := clock.SendTimeout(fake, c, 1, 1*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- clock.SendTimeout(fake, c, 2, 1*time.Second)
+	}()
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected a timeout got %v", err)
+		}
+	case <-time.After(sentTimeout):
+		t.Fatal("timeout: SendTimeout did not return")
+	}
+
+	if v := <-c; v != 1 {
+		t.Errorf("expected %d got %d", 1, v)
+	}
+}
@@ -0,0 +1,94 @@
+package clock
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var errInvalidChaos = errors.New("clock: probability out of [0, 1] or negative maximum for Chaos")
+
+// A ChaosPolicy sets how often, and how badly, a clock returned by Chaos
+// misbehaves. Probabilities are between 0 and 1; a zero policy never
+// misbehaves.
+type ChaosPolicy struct {
+	// Stall is the probability, on each call to Now, that the time freezes
+	// for up to MaxStall of the underlying clock, then resumes where the
+	// underlying clock got to, as on a VM descheduled by its host.
+	Stall    float64
+	MaxStall time.Duration
+
+	// Jump is the probability, on each call to Now, that the time jumps
+	// forward by up to MaxJump, for good.
+	Jump    float64
+	MaxJump time.Duration
+
+	// Delay is the probability, for each timer, sleep or reset, that it
+	// wakes up to MaxDelay late.
+	Delay    float64
+	MaxDelay time.Duration
+}
+
+func (policy ChaosPolicy) valid() bool {
+	for _, p := range []float64{policy.Stall, policy.Jump, policy.Delay} {
+		if p < 0 || p > 1 {
+			return false
+		}
+	}
+	return policy.MaxStall >= 0 && policy.MaxJump >= 0 && policy.MaxDelay >= 0
+}
+
+// Chaos returns a Clock that, as drawn by policy from a source seeded with
+// seed, stalls its time, jumps it forward, or delays its timers, to harden
+// code against the pathological time of oversubscribed VMs. Its time never
+// goes back. Given the same seed and the same sequence of calls, it
+// misbehaves the same way, so failures can be reproduced.
+// Tickers are unaffected.
+// Chaos panics if a probability of policy is out of [0, 1], or a maximum is
+// negative.
+func Chaos(clock Clock, policy ChaosPolicy, seed int64) Clock {
+	if !policy.valid() {
+		panic(errInvalidChaos)
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	draw := func(p float64, max time.Duration) time.Duration {
+		if p == 0 || r.Float64() >= p {
+			return 0
+		}
+		return time.Duration(r.Int63n(int64(max) + 1))
+	}
+
+	var (
+		mutex  sync.Mutex
+		offset time.Duration
+		frozen time.Time
+		thawed time.Time
+	)
+	return &mappedClock{
+		Clock: clock,
+		now: func(t time.Time) time.Time {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if t.Before(thawed) {
+				return frozen
+			}
+
+			offset += draw(policy.Jump, policy.MaxJump)
+			now := t.Add(offset)
+			if stall := draw(policy.Stall, policy.MaxStall); stall > 0 {
+				frozen = now
+				thawed = t.Add(stall)
+			}
+			return now
+		},
+		timer: func(d time.Duration) time.Duration {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			return d + draw(policy.Delay, policy.MaxDelay)
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package clock_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestChaos_Now(t *testing.T) {
+	policy := clock.ChaosPolicy{
+		Stall:    0.2,
+		MaxStall: 5 * time.Second,
+		Jump:     0.2,
+		MaxJump:  time.Minute,
+	}
+
+	readings := func() []time.Time {
+		fake := clock.NewFakeClock()
+		c := clock.Chaos(fake, policy, 1)
+
+		var times []time.Time
+		for i := 0; i < 100; i++ {
+			times = append(times, c.Now())
+			fake.Advance(time.Second)
+		}
+		return times
+	}
+
+	times := readings()
+	stalled, jumped := false, false
+	for i := 1; i < len(times); i++ {
+		switch d := times[i].Sub(times[i-1]); {
+		case d < 0:
+			t.Fatalf("expected the time not to go back, got %s then %s", times[i-1], times[i])
+		case d == 0:
+			stalled = true
+		case d > time.Second:
+			jumped = true
+		}
+	}
+	if !stalled || !jumped {
+		t.Errorf("expected stalls and jumps, got stalls %t and jumps %t", stalled, jumped)
+	}
+
+	// the same seed misbehaves the same way
+	for i, now := range readings() {
+		if !now.Equal(times[i]) {
+			t.Fatalf("expected reading %d at %s got %s", i, times[i], now)
+		}
+	}
+}
+
+func TestChaos_Delay(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+	c := clock.Chaos(fake, clock.ChaosPolicy{Delay: 1, MaxDelay: time.Second}, 1)
+
+	for i := 0; i < 10; i++ {
+		c.NewTimer(time.Second).C()
+	}
+	for _, timer := range fake.PendingTimers() {
+		if d := timer.Deadline.Sub(start); d < time.Second || d > 2*time.Second {
+			t.Errorf("expected a deadline within [1s, 2s], got %s", d)
+		}
+	}
+}
+
+func TestChaos_Invalid(t *testing.T) {
+	_, err := clock.Wrap(clock.NewFakeClock()).Chaos(clock.ChaosPolicy{Stall: 2}, 1).Build()
+	if !errors.Is(err, clock.ErrInvalidDecorator) {
+		t.Errorf("expected %v got %v", clock.ErrInvalidDecorator, err)
+	}
+}
@@ -0,0 +1,50 @@
+package clock
+
+import "math/rand"
+
+// TB is the part of testing.TB that FakeClock.Child uses, so this package
+// doesn't depend on testing.
+type TB interface {
+	Name() string
+	Cleanup(func())
+}
+
+func (clock *fakeClock) Child(tb TB) FakeClock {
+	clock.mutex.Lock()
+	child := clock.derive()
+	clock.mutex.Unlock()
+
+	child.label = tb.Name()
+	tb.Cleanup(child.Pause)
+	return child
+}
+
+// derive returns a new clock at the time of clock, with its options, but
+// none of its sleepers, and no playback. The caller holds the clock's mutex.
+func (clock *fakeClock) derive() *fakeClock {
+	derived := &fakeClock{
+		at:         clock.at,
+		origin:     clock.at,
+		advancer:   make(chan struct{}, 1),
+		boundary:   clock.boundary,
+		strict:     clock.strict,
+		maxAdvance: clock.maxAdvance,
+		coalescing: clock.coalescing,
+		onPanic:    clock.onPanic,
+		order:      clock.order,
+		noCallers:  clock.noCallers,
+		goroutines: clock.goroutines,
+		label:      clock.label,
+		maxPending: clock.maxPending,
+		onLimit:    clock.onLimit,
+	}
+	derived.now.Store(clock.at)
+
+	if clock.rand != nil {
+		derived.rand = rand.New(rand.NewSource(clock.rand.Int63()))
+	}
+	if clock.executor != nil {
+		derived.executor = &executor{}
+	}
+	return derived
+}
@@ -0,0 +1,57 @@
+package clock_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestChild(t *testing.T) {
+	parent := clock.NewFakeClock(clock.WithStrict())
+	parent.After(time.Hour)
+	start := parent.Now()
+
+	for _, d := range []time.Duration{time.Second, time.Minute} {
+		d := d
+		t.Run(d.String(), func(t *testing.T) {
+			t.Parallel()
+
+			child := parent.Child(t)
+			assertClockAt(t, start, child)
+			if name := clock.Name(child); name != t.Name() {
+				t.Errorf("expected the child named %q got %q", t.Name(), name)
+			}
+
+			// the child has the options of the parent, but none of its sleepers
+			if err := child.Advance(time.Hour); !errors.Is(err, clock.ErrNothingScheduled) {
+				t.Errorf("expected the options of the parent, got %v", err)
+			}
+
+			c := child.After(d)
+			child.Advance(d)
+			assertSent(t, start.Add(d), c)
+		})
+	}
+
+	t.Cleanup(func() {
+		assertClockAt(t, start, parent)
+		if pending := len(parent.PendingTimers()); pending != 1 {
+			t.Errorf("expected the parent's sleeper only, got %d", pending)
+		}
+	})
+}
+
+func TestChild_Cleanup(t *testing.T) {
+	var child clock.FakeClock
+	t.Run("play", func(t *testing.T) {
+		child = clock.NewFakeClock().Child(t)
+		child.Play(1)
+	})
+
+	// the playback stopped with the subtest
+	now := child.Now()
+	time.Sleep(30 * time.Millisecond)
+	assertClockAt(t, now, child)
+}
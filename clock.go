@@ -1,6 +1,9 @@
 package clock
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Clock interface {
 	// Now returns the current local time.
@@ -50,10 +53,47 @@ type Clock interface {
 type FakeClock interface {
 	Clock
 
-	// Advance increments the time in the clock by d.
-	// If d < 0, this call is a noop.
-	// Time travel is not allowed.
-	Advance(d time.Duration)
+	// Advance increments the time in the clock by d,
+	// waking the sleepers whose deadline is reached.
+	// Advance(0) wakes the sleepers already due without moving the clock.
+	// Time travel is not allowed: if d < 0, the clock is left unchanged
+	// and an error wrapping ErrNegativeAdvance is returned. Options such as
+	// WithStrict and WithMaxAdvance add errors of their own.
+	//
+	// Advance may be called from several goroutines. The calls, and those to
+	// SetTime and Suspend, are applied one at a time, in the order they are
+	// made. Each returns once the sleepers it made due have woken: their
+	// values are on their channels, their AfterFunc callbacks have started,
+	// and the functions watching the steps of the clock have returned. The
+	// next call waits until then, so it must not be made by those functions.
+	Advance(d time.Duration) error
+
+	// SetTime steps the wall time of the clock to t, forward or backward,
+	// like NTP or an operator setting the system clock. Timers, tickers and
+	// sleeps wait for durations, so each keeps the time it had left, and the
+	// monotonic readings of NowBoth are unchanged. Wall alarms (see
+	// NewWallAlarm) are re-evaluated against the new time.
+	SetTime(t time.Time)
+
+	// Suspend simulates a suspend of the system for d, followed by a resume:
+	// the wall time steps forward by d, as with SetTime, while timers keep
+	// the time they had left, as Go's timers do on Linux. Suspend watchers
+	// (see WatchSuspends) are notified. A negative d counts as 0.
+	Suspend(d time.Duration)
+
+	// Play makes the clock advance by itself, at rate times the speed of
+	// real time, until Pause is called, in addition to the calls to Advance.
+	// Calling Play again changes the rate. Play panics if rate isn't
+	// positive.
+	Play(rate float64)
+
+	// Pause stops the playback started by Play, if any.
+	Pause()
+
+	// Resume restarts the playback stopped by Pause, at the rate last given
+	// to Play, or at the speed of real time if Play wasn't called. It does
+	// nothing if the clock is playing.
+	Resume()
 
 	// Until waits until n goroutines are blocked on the clock.
 	// The returned channel is then closed
@@ -62,6 +102,56 @@ type FakeClock interface {
 	// BlockUntil blocks until n goroutines are blocked on the clock.
 	// It's a convenience method for `<-clock.Until(n)`.
 	BlockUntil(n int)
+
+	// BlockUntilContext is like BlockUntil, but gives up when ctx is done,
+	// returning a *BlockError that lists the sleepers waiting on the clock.
+	BlockUntilContext(ctx context.Context, n int) error
+
+	// UntilTagged is like Until, but only counts the sleepers created
+	// through a view of the clock returned by Tagged with tag.
+	UntilTagged(tag string, n int) <-chan struct{}
+
+	// BlockUntilTagged blocks until n sleepers tagged with tag are waiting
+	// on the clock. It's a convenience method for `<-clock.UntilTagged(tag, n)`.
+	BlockUntilTagged(tag string, n int)
+
+	// PendingTimers returns the sleepers currently waiting on the clock,
+	// ordered by deadline.
+	PendingTimers() []PendingTimer
+
+	// NextDeadline returns the earliest deadline of the sleepers waiting on
+	// the clock, and false if there are none.
+	NextDeadline() (time.Time, bool)
+
+	// Stats summarizes the sleepers of the clock.
+	Stats() Stats
+
+	// Child returns an independent fake clock for the test or subtest tb,
+	// starting at the current time of the clock, with its options, and named
+	// after tb, so parallel subtests don't share sleepers. The child stops
+	// playing, if Play was called on it, when tb is cleaned up.
+	Child(tb TB) FakeClock
+
+	// Clone returns an independent fake clock at the current time of the
+	// clock, with its options and a copy of its pending sleepers, so a test
+	// can explore alternative futures from the same prepared state. The
+	// copies send on the same channels and call the same functions as the
+	// originals, except those of Sleep, which wake no goroutine: advancing
+	// both clocks past a deadline sends the value, or calls the function,
+	// twice. Timers and tickers created before the call are still stopped
+	// and reset on the original clock only.
+	//
+	// The functions of AfterFunc are shared, not rebound to the clone: a
+	// copy fired by the clone runs the same closure, as a callback of the
+	// original clock, with its panic handler and serial callbacks, and
+	// whatever the closure schedules, such as the next run of a periodic
+	// task, lands on the clock it captured, usually the original.
+	Clone() FakeClock
+
+	// ReadOnly returns a view of the clock as a Clock, which can't be
+	// asserted back to a FakeClock, to hand to the code under test while
+	// the test keeps the handle that moves the time.
+	ReadOnly() Clock
 }
 
 // The Timer type represents a single event.
@@ -70,6 +160,8 @@ type FakeClock interface {
 // A Timer must be created with clock.NewTimer or clock.AfterFunc.
 type Timer interface {
 	// C returns the channel on which the time is delivered.
+	// It returns the same channel for the lifetime of the timer,
+	// including across calls to Reset.
 	C() <-chan time.Time
 
 	// Stop prevents the Timer from firing.
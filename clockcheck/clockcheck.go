@@ -0,0 +1,139 @@
+// Package clockcheck provides an analyzer that flags direct uses of the time
+// package's clock in packages that already depend on clock.Clock.
+//
+// A package that imports github.com/go-toolbelt/clock is expected to take
+// its time from an injected clock, so a stray time.Now or time.After is a
+// bug that a fake clock can't control: tests stall, or pass by accident.
+// The analyzer reports calls to the functions of the time package that read
+// or wait on the system clock, and leaves the rest of the package alone.
+//
+// A call that must use the system clock, such as a real-time safety timeout
+// in a test helper, is allowed by a comment on its line, or on the line
+// above, starting with the directive
+//
+//	//clockcheck:ignore
+//
+// optionally followed by the reason.
+package clockcheck
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// clockPath is the import path that marks a package as using clock.Clock.
+const clockPath = "github.com/go-toolbelt/clock"
+
+// directive allows a call flagged by the analyzer.
+const directive = "//clockcheck:ignore"
+
+// Analyzer flags calls to time.Now, time.Since, time.Until, time.Sleep,
+// time.After, time.AfterFunc, time.NewTimer, time.NewTicker and time.Tick in
+// packages that import github.com/go-toolbelt/clock.
+var Analyzer = &analysis.Analyzer{
+	Name:     "clockcheck",
+	Doc:      "report uses of the system clock in packages that depend on clock.Clock",
+	Run:      run,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+}
+
+// replacements maps the flagged functions of the time package to the
+// calls on a clock.Clock to make instead.
+var replacements = map[string]string{
+	"Now":       "clock.Now()",
+	"Since":     "clock.Since(t)",
+	"Until":     "t.Sub(clock.Now())",
+	"Sleep":     "clock.Sleep(d)",
+	"After":     "clock.After(d)",
+	"AfterFunc": "clock.AfterFunc(d, f)",
+	"NewTimer":  "clock.NewTimer(d)",
+	"NewTicker": "clock.NewTicker(d)",
+	"Tick":      "clock.Tick(d)",
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	if !importsClock(pass.Pkg) {
+		return nil, nil
+	}
+
+	ignored := ignoredLines(pass)
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "time" {
+			return
+		}
+		// methods such as time.Time.Add don't read the clock
+		if fn.Type().(*types.Signature).Recv() != nil {
+			return
+		}
+
+		replacement, ok := replacements[fn.Name()]
+		if !ok {
+			return
+		}
+
+		position := pass.Fset.Position(call.Pos())
+		if ignored[lineKey{position.Filename, position.Line}] {
+			return
+		}
+
+		pass.Reportf(call.Pos(), "time.%s uses the system clock; use %s on the injected clock.Clock", fn.Name(), replacement)
+	})
+
+	return nil, nil
+}
+
+// importsClock reports whether pkg imports the clock package directly.
+func importsClock(pkg *types.Package) bool {
+	for _, imported := range pkg.Imports() {
+		if imported.Path() == clockPath {
+			return true
+		}
+	}
+	return false
+}
+
+type lineKey struct {
+	file string
+	line int
+}
+
+// ignoredLines returns the lines on which the directive allows calls: the
+// line of each directive, and the line below it.
+func ignoredLines(pass *analysis.Pass) map[lineKey]bool {
+	ignored := make(map[lineKey]bool)
+	for _, file := range pass.Files {
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				if !isDirective(comment.Text) {
+					continue
+				}
+
+				position := pass.Fset.Position(comment.Slash)
+				ignored[lineKey{position.Filename, position.Line}] = true
+				ignored[lineKey{position.Filename, position.Line + 1}] = true
+			}
+		}
+	}
+	return ignored
+}
+
+// isDirective reports whether a comment is the directive, alone or
+// followed by a reason.
+func isDirective(text string) bool {
+	if !strings.HasPrefix(text, directive) {
+		return false
+	}
+	rest := text[len(directive):]
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t'
+}
@@ -0,0 +1,12 @@
+package clockcheck_test
+
+import (
+	"testing"
+
+	"github.com/go-toolbelt/clock/clockcheck"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), clockcheck.Analyzer, "a", "b")
+}
@@ -0,0 +1,14 @@
+// Command clockcheck reports uses of the system clock in packages that depend
+// on clock.Clock. It runs on its own, or under go vet:
+//
+//	go vet -vettool=$(which clockcheck) ./...
+package main
+
+import (
+	"github.com/go-toolbelt/clock/clockcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(clockcheck.Analyzer)
+}
@@ -0,0 +1,31 @@
+package a
+
+import (
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func uses(c clock.Clock) {
+	_ = time.Now()               // want `time.Now uses the system clock; use clock.Now\(\) on the injected clock.Clock`
+	_ = time.Since(c.Now())      // want `time.Since uses the system clock`
+	time.Sleep(time.Second)      // want `time.Sleep uses the system clock`
+	<-time.After(time.Second)    // want `time.After uses the system clock`
+	time.NewTimer(time.Second)   // want `time.NewTimer uses the system clock`
+	time.AfterFunc(0, func() {}) // want `time.AfterFunc uses the system clock`
+	_ = time.Tick(time.Second)   // want `time.Tick uses the system clock`
+	f := time.Now                // not a call
+	_ = f
+
+	// functions and methods that don't read the clock are fine
+	_ = c.Now().Add(time.Second)
+	_ = time.Unix(0, 0)
+	_, _ = time.ParseDuration("1s")
+
+	//clockcheck:ignore real-time safety timeout
+	_ = time.After(time.Minute)
+	_ = time.Now() //clockcheck:ignore
+
+	//clockcheck:ignored isn't the directive
+	_ = time.Now() // want `time.Now uses the system clock`
+}
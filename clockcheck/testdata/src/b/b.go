@@ -0,0 +1,9 @@
+// Package b doesn't depend on the clock package, so it may use the system clock.
+package b
+
+import "time"
+
+func uses() time.Time {
+	time.Sleep(time.Millisecond)
+	return time.Now()
+}
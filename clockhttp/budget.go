@@ -0,0 +1,40 @@
+package clockhttp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+type budgetKey struct{}
+
+func withBudget(ctx context.Context, clock clock.Clock) context.Context {
+	return context.WithValue(ctx, budgetKey{}, clock)
+}
+
+// Deadline returns the time at which the request's budget runs out.
+// The boolean is false if r is not being served by a TimeoutHandler.
+func Deadline(r *http.Request) (time.Time, bool) {
+	if _, ok := r.Context().Value(budgetKey{}).(clock.Clock); !ok {
+		return time.Time{}, false
+	}
+	return r.Context().Deadline()
+}
+
+// Remaining returns the budget left to serve the request,
+// measured by the clock of the TimeoutHandler serving r.
+// The boolean is false if r is not being served by a TimeoutHandler.
+func Remaining(r *http.Request) (time.Duration, bool) {
+	clock, ok := r.Context().Value(budgetKey{}).(clock.Clock)
+	if !ok {
+		return 0, false
+	}
+
+	deadline, ok := r.Context().Deadline()
+	if !ok {
+		return 0, false
+	}
+	return deadline.Sub(clock.Now()), true
+}
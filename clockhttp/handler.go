@@ -0,0 +1,141 @@
+// Package clockhttp provides net/http helpers whose timeouts are measured by a clock.Clock.
+package clockhttp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// TimeoutHandler returns a Handler that runs h with the given time limit,
+// measured by clock.
+//
+// It behaves like http.TimeoutHandler: if h does not complete within dt,
+// the handler responds with a 503 Service Unavailable error and msg in its body
+// (a default message is used if msg is empty), and later writes by h to its
+// ResponseWriter return http.ErrHandlerTimeout.
+// The request context passed to h is canceled once the limit is reached;
+// use Remaining or Deadline to read the budget left inside h.
+func TimeoutHandler(clock clock.Clock, h http.Handler, dt time.Duration, msg string) http.Handler {
+	return &timeoutHandler{
+		clock:   clock,
+		handler: h,
+		dt:      dt,
+		body:    msg,
+	}
+}
+
+type timeoutHandler struct {
+	clock   clock.Clock
+	handler http.Handler
+	dt      time.Duration
+	body    string
+}
+
+const defaultTimeoutBody = "<html><head><title>Timeout</title></head><body><h1>Timeout</h1></body></html>"
+
+func (h *timeoutHandler) errorBody() string {
+	if h.body != "" {
+		return h.body
+	}
+	return defaultTimeoutBody
+}
+
+func (h *timeoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := clock.WithTimeout(r.Context(), h.clock, h.dt)
+	defer cancel()
+
+	ctx = withBudget(ctx, h.clock)
+	r = r.WithContext(ctx)
+
+	tw := &timeoutWriter{
+		header: make(http.Header),
+	}
+
+	done := make(chan struct{})
+	panics := make(chan interface{}, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				panics <- p
+			}
+		}()
+		h.handler.ServeHTTP(tw, r)
+		close(done)
+	}()
+
+	select {
+	case p := <-panics:
+		panic(p)
+	case <-done:
+		tw.mutex.Lock()
+		defer tw.mutex.Unlock()
+
+		dst := w.Header()
+		for k, vv := range tw.header {
+			dst[k] = vv
+		}
+		if !tw.wroteHeader {
+			tw.code = http.StatusOK
+		}
+		w.WriteHeader(tw.code)
+		_, _ = w.Write(tw.buf.Bytes())
+	case <-ctx.Done():
+		tw.mutex.Lock()
+		defer tw.mutex.Unlock()
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+		if ctx.Err() == context.DeadlineExceeded {
+			_, _ = io.WriteString(w, h.errorBody())
+			tw.err = http.ErrHandlerTimeout
+		} else {
+			tw.err = ctx.Err()
+		}
+	}
+}
+
+type timeoutWriter struct {
+	mutex       sync.Mutex
+	header      http.Header
+	buf         bytes.Buffer
+	err         error
+	wroteHeader bool
+	code        int
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	if tw.err != nil {
+		return 0, tw.err
+	}
+	if !tw.wroteHeader {
+		tw.writeHeaderLocked(http.StatusOK)
+	}
+	return tw.buf.Write(p)
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	if tw.err != nil || tw.wroteHeader {
+		return
+	}
+	tw.writeHeaderLocked(code)
+}
+
+func (tw *timeoutWriter) writeHeaderLocked(code int) {
+	tw.wroteHeader = true
+	tw.code = code
+}
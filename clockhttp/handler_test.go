@@ -0,0 +1,111 @@
+package clockhttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clockhttp"
+)
+
+const timeout = 100 * time.Millisecond
+
+func TestTimeoutHandler(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	h := clockhttp.TimeoutHandler(fake, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "ok")
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("done"))
+	}), 1*time.Second, "")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusTeapot {
+		t.Errorf("expected %d got %d", http.StatusTeapot, w.Code)
+	}
+	if actual := w.Header().Get("X-Test"); actual != "ok" {
+		t.Errorf("expected header %q got %q", "ok", actual)
+	}
+	if actual := w.Body.String(); actual != "done" {
+		t.Errorf("expected body %q got %q", "done", actual)
+	}
+}
+
+func TestTimeoutHandler_Timeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	release := make(chan struct{})
+	writeErr := make(chan error, 1)
+	h := clockhttp.TimeoutHandler(fake, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		<-release
+		_, err := w.Write([]byte("late"))
+		writeErr <- err
+	}), 1*time.Second, "too slow")
+
+	w := httptest.NewRecorder()
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+
+	select {
+	case <-served:
+	case <-time.After(timeout):
+		t.Fatal("timeout: handler did not return")
+	}
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected %d got %d", http.StatusServiceUnavailable, w.Code)
+	}
+	if actual := w.Body.String(); actual != "too slow" {
+		t.Errorf("expected body %q got %q", "too slow", actual)
+	}
+
+	close(release)
+	select {
+	case err := <-writeErr:
+		if err != http.ErrHandlerTimeout {
+			t.Errorf("expected %v got %v", http.ErrHandlerTimeout, err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout: handler did not write")
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	h := clockhttp.TimeoutHandler(fake, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.Advance(400 * time.Millisecond)
+
+		if remaining, ok := clockhttp.Remaining(r); !ok || remaining != 600*time.Millisecond {
+			t.Errorf("expected remaining %s got %s", 600*time.Millisecond, remaining)
+		}
+		if deadline, ok := clockhttp.Deadline(r); !ok || deadline != start.Add(1*time.Second) {
+			t.Errorf("expected deadline %s got %s", start.Add(1*time.Second), deadline)
+		}
+	}), 1*time.Second, "")
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestRemaining_NoBudget(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if _, ok := clockhttp.Remaining(r); ok {
+		t.Error("expected no remaining budget")
+	}
+	if _, ok := clockhttp.Deadline(r); ok {
+		t.Error("expected no deadline")
+	}
+}
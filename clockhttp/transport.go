@@ -0,0 +1,202 @@
+package clockhttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// Transport is an http.RoundTripper that applies per-attempt timeouts
+// and retry backoff measured by a clock.
+//
+// Unlike http.Client.Timeout, which is measured by wall time,
+// every wait performed by Transport can be driven by a fake clock.
+type Transport struct {
+	// Clock measures timeouts and backoff. It must be set.
+	Clock clock.Clock
+
+	// Base performs the requests.
+	// If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	// Timeout limits the duration of each attempt,
+	// including reading the response body.
+	// Zero means no timeout.
+	Timeout time.Duration
+
+	// MaxRetries is the number of times a request is retried
+	// after its first attempt. Requests whose body cannot be
+	// replayed (see http.Request.GetBody) are never retried.
+	MaxRetries int
+
+	// Backoff is the wait before the first retry.
+	// It doubles on every following retry, up to MaxBackoff if it is positive.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// ShouldRetry reports whether an attempt should be retried.
+	// If nil, attempts of idempotent requests are retried on transport
+	// errors and on 502, 503 and 504 responses. A request is idempotent
+	// if its method is GET, HEAD, OPTIONS, TRACE, PUT or DELETE, or if it
+	// has an Idempotency-Key or X-Idempotency-Key header, so a POST or
+	// PATCH that may have had an effect isn't sent twice.
+	ShouldRetry func(resp *http.Response, err error) bool
+
+	// DeadlineHeader, if set, names a request header that carries
+	// the remaining budget of the request's context in milliseconds,
+	// measured by Clock, so the deadline propagates to the server.
+	DeadlineHeader string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	backoff := t.Backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := t.roundTrip(req)
+
+		if attempt >= t.MaxRetries || !t.shouldRetry(req, resp, err) || !replayable(req) {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := t.sleep(req.Context(), backoff); err != nil {
+			return nil, err
+		}
+		backoff *= 2
+		if t.MaxBackoff > 0 && backoff > t.MaxBackoff {
+			backoff = t.MaxBackoff
+		}
+
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *Transport) roundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if t.Timeout > 0 {
+		ctx, cancel = clock.WithTimeout(ctx, t.Clock, t.Timeout)
+	}
+
+	req = req.WithContext(ctx)
+	if t.DeadlineHeader != "" {
+		if deadline, ok := ctx.Deadline(); ok {
+			req.Header = req.Header.Clone()
+			remaining := deadline.Sub(t.Clock.Now()).Milliseconds()
+			req.Header.Set(t.DeadlineHeader, strconv.FormatInt(remaining, 10))
+		}
+	}
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelBody{
+		ReadCloser: resp.Body,
+		cancel:     cancel,
+	}
+	return resp, nil
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if t.ShouldRetry != nil {
+		return t.ShouldRetry(resp, err)
+	}
+	if !idempotent(req) {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *Transport) sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d <= 0 {
+		return nil
+	}
+
+	timer := t.Clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// idempotent reports whether sending req twice has the same effect as
+// sending it once, like the retry policy of http.Transport.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	_, ok := req.Header["Idempotency-Key"]
+	if !ok {
+		_, ok = req.Header["X-Idempotency-Key"]
+	}
+	return ok
+}
+
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = body
+	return req, nil
+}
+
+// cancelBody releases the attempt's timeout once the response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (body *cancelBody) Close() error {
+	err := body.ReadCloser.Close()
+	body.cancel()
+	return err
+}
@@ -0,0 +1,200 @@
+package clockhttp_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clockhttp"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func respond(code int) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+}
+
+func TestTransport_Retry(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	attempts := 0
+	transport := &clockhttp.Transport{
+		Clock: fake,
+		Base: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			attempts++
+			if attempts < 3 {
+				return respond(http.StatusServiceUnavailable), nil
+			}
+			return respond(http.StatusOK), nil
+		}),
+		MaxRetries: 3,
+		Backoff:    1 * time.Second,
+	}
+
+	done := make(chan *http.Response, 1)
+	go func() {
+		resp, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "/", nil))
+		if err != nil {
+			t.Error(err)
+		}
+		done <- resp
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+	assertNoResponse(t, done)
+	fake.Advance(1 * time.Second)
+
+	select {
+	case resp := <-done:
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected %d got %d", http.StatusOK, resp.StatusCode)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout: round trip did not return")
+	}
+
+	if attempts != 3 {
+		t.Errorf("expected %d attempts got %d", 3, attempts)
+	}
+}
+
+func TestTransport_RetriesExhausted(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	attempts := 0
+	transport := &clockhttp.Transport{
+		Clock: fake,
+		Base: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			attempts++
+			return nil, errors.New("connection refused")
+		}),
+		MaxRetries: 1,
+	}
+
+	if _, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "/", nil)); err == nil {
+		t.Error("expected an error")
+	}
+	if attempts != 2 {
+		t.Errorf("expected %d attempts got %d", 2, attempts)
+	}
+}
+
+func TestTransport_NotIdempotent(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	attempts := 0
+	transport := &clockhttp.Transport{
+		Clock: fake,
+		Base: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			attempts++
+			return nil, errors.New("connection reset")
+		}),
+		MaxRetries: 1,
+	}
+
+	body := func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader("body")), nil }
+
+	// a POST may have had an effect, so it's only retried with a key,
+	// even if its body can be replayed
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("body"))
+	req.GetBody = body
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Error("expected an error")
+	}
+	if attempts != 1 {
+		t.Errorf("expected %d attempts got %d", 1, attempts)
+	}
+
+	attempts = 0
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("body"))
+	req.GetBody = body
+	req.Header.Set("Idempotency-Key", "1")
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Error("expected an error")
+	}
+	if attempts != 2 {
+		t.Errorf("expected %d attempts got %d", 2, attempts)
+	}
+}
+
+func TestTransport_Timeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	transport := &clockhttp.Transport{
+		Clock: fake,
+		Base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}),
+		Timeout: 1 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "/", nil))
+		errs <- err
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+
+	select {
+	case err := <-errs:
+		if err != context.DeadlineExceeded {
+			t.Errorf("expected %v got %v", context.DeadlineExceeded, err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout: round trip did not return")
+	}
+}
+
+func TestTransport_DeadlineHeader(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	var header string
+	transport := &clockhttp.Transport{
+		Clock: fake,
+		Base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			header = req.Header.Get("X-Timeout-Ms")
+			return respond(http.StatusOK), nil
+		}),
+		Timeout:        1500 * time.Millisecond,
+		DeadlineHeader: "X-Timeout-Ms",
+	}
+
+	resp, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if header != "1500" {
+		t.Errorf("expected header %q got %q", "1500", header)
+	}
+}
+
+func assertNoResponse(t *testing.T, c <-chan *http.Response) {
+	t.Helper()
+
+	select {
+	case <-c:
+		t.Error("round trip returned unexpectedly")
+	case <-time.After(timeout):
+	}
+}
@@ -0,0 +1,210 @@
+// Package clocknet provides net.Conn and net.Listener wrappers
+// whose deadlines are measured by a clock.Clock.
+//
+// When a deadline measured by the clock passes, the wrapper sets a deadline in the past
+// on the underlying connection, so blocked reads and writes fail with a timeout error.
+// This works with real connections as well as with net.Pipe,
+// which lets protocol code be tested against a fake clock entirely in memory.
+package clocknet
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// Config configures the deadlines enforced on wrapped connections.
+type Config struct {
+	// IdleTimeout expires the connection once no data has been read or written
+	// for the given duration. Zero disables the idle timeout.
+	IdleTimeout time.Duration
+
+	// MaxLifetime expires the connection once the given duration has passed since it was wrapped.
+	// Zero disables the limit.
+	MaxLifetime time.Duration
+}
+
+// Conn is a net.Conn whose deadlines are measured by a clock.
+// Once the idle timeout or maximum lifetime is reached,
+// every following read and write fails with a timeout error.
+type Conn struct {
+	net.Conn
+
+	clock       clock.Clock
+	idleTimeout time.Duration
+
+	mutex    sync.Mutex
+	expired  bool
+	idle     clock.Timer
+	lifetime clock.Timer
+	read     clock.Timer
+	write    clock.Timer
+}
+
+// past is a deadline that has always passed on the underlying connection.
+var past = time.Unix(1, 0)
+
+// WrapConn wraps conn, enforcing the deadlines in config as measured by clock.
+func WrapConn(clock clock.Clock, conn net.Conn, config Config) *Conn {
+	c := &Conn{
+		Conn:        conn,
+		clock:       clock,
+		idleTimeout: config.IdleTimeout,
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if config.IdleTimeout > 0 {
+		c.idle = clock.AfterFunc(config.IdleTimeout, c.expire)
+	}
+	if config.MaxLifetime > 0 {
+		c.lifetime = clock.AfterFunc(config.MaxLifetime, c.expire)
+	}
+
+	return c
+}
+
+// Read implements net.Conn, resetting the idle timeout when data is read.
+func (c *Conn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		c.touch()
+	}
+	return n, err
+}
+
+// Write implements net.Conn, resetting the idle timeout when data is written.
+func (c *Conn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	if n > 0 {
+		c.touch()
+	}
+	return n, err
+}
+
+// Close implements net.Conn, releasing the connection's timers.
+func (c *Conn) Close() error {
+	c.mutex.Lock()
+	for _, timer := range []clock.Timer{c.idle, c.lifetime, c.read, c.write} {
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+	c.mutex.Unlock()
+
+	return c.Conn.Close()
+}
+
+// SetDeadline implements net.Conn, measuring t by the clock.
+func (c *Conn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+// SetReadDeadline implements net.Conn, measuring t by the clock.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.schedule(&c.read, t, c.Conn.SetReadDeadline)
+}
+
+// SetWriteDeadline implements net.Conn, measuring t by the clock.
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.schedule(&c.write, t, c.Conn.SetWriteDeadline)
+}
+
+// Expired reports whether the idle timeout or maximum lifetime has been reached.
+func (c *Conn) Expired() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.expired
+}
+
+func (c *Conn) schedule(timer *clock.Timer, t time.Time, set func(time.Time) error) error {
+	if *timer != nil {
+		(*timer).Stop()
+		*timer = nil
+	}
+
+	if c.expired {
+		return nil
+	}
+	if t.IsZero() {
+		return set(time.Time{})
+	}
+
+	d := t.Sub(c.clock.Now())
+	if d <= 0 {
+		return set(past)
+	}
+
+	if err := set(time.Time{}); err != nil {
+		return err
+	}
+
+	var scheduled clock.Timer
+	scheduled = c.clock.AfterFunc(d, func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		if *timer == scheduled {
+			_ = set(past)
+		}
+	})
+	*timer = scheduled
+
+	return nil
+}
+
+func (c *Conn) touch() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.idle != nil && !c.expired {
+		c.idle.Reset(c.idleTimeout)
+	}
+}
+
+func (c *Conn) expire() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.expired = true
+	_ = c.Conn.SetDeadline(past)
+}
+
+// Listener is a net.Listener whose accepted connections are wrapped by WrapConn.
+type Listener struct {
+	net.Listener
+
+	clock  clock.Clock
+	config Config
+}
+
+// WrapListener wraps l so every accepted connection enforces the deadlines in config.
+func WrapListener(clock clock.Clock, l net.Listener, config Config) *Listener {
+	return &Listener{
+		Listener: l,
+		clock:    clock,
+		config:   config,
+	}
+}
+
+// Accept implements net.Listener, returning a *Conn.
+func (l *Listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return WrapConn(l.clock, conn, l.config), nil
+}
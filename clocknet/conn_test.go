@@ -0,0 +1,168 @@
+package clocknet_test
+
+import (
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocknet"
+)
+
+const timeout = 100 * time.Millisecond
+
+func read(conn net.Conn) <-chan error {
+	errs := make(chan error, 1)
+	go func() {
+		_, err := conn.Read(make([]byte, 1))
+		errs <- err
+	}()
+	return errs
+}
+
+func assertTimeout(t *testing.T, errs <-chan error) {
+	t.Helper()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Errorf("expected %v got %v", os.ErrDeadlineExceeded, err)
+		}
+	case <-time.After(timeout):
+		t.Error("timeout: read did not return")
+	}
+}
+
+func assertBlocked(t *testing.T, errs <-chan error) {
+	t.Helper()
+
+	select {
+	case err := <-errs:
+		t.Errorf("read returned unexpectedly with %v", err)
+	case <-time.After(timeout):
+	}
+}
+
+func TestConn_IdleTimeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	client, server := net.Pipe()
+	defer server.Close()
+
+	conn := clocknet.WrapConn(fake, client, clocknet.Config{IdleTimeout: 2 * time.Second})
+	defer conn.Close()
+
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+
+	// activity resets the idle timeout
+	go func() { _, _ = server.Write([]byte("x")) }()
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := read(conn)
+	fake.Advance(1 * time.Second)
+	assertBlocked(t, errs)
+
+	fake.Advance(1 * time.Second)
+	assertTimeout(t, errs)
+
+	if !conn.Expired() {
+		t.Error("expected connection to be expired")
+	}
+}
+
+func TestConn_MaxLifetime(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	client, server := net.Pipe()
+	defer server.Close()
+
+	conn := clocknet.WrapConn(fake, client, clocknet.Config{MaxLifetime: 1 * time.Second})
+	defer conn.Close()
+
+	errs := read(conn)
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+	assertTimeout(t, errs)
+}
+
+func TestConn_SetReadDeadline(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	client, server := net.Pipe()
+	defer server.Close()
+
+	conn := clocknet.WrapConn(fake, client, clocknet.Config{})
+	defer conn.Close()
+
+	if err := conn.SetReadDeadline(start.Add(1 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := read(conn)
+	fake.BlockUntil(1)
+	assertBlocked(t, errs)
+
+	fake.Advance(1 * time.Second)
+	assertTimeout(t, errs)
+
+	// clearing the deadline allows reads again
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	errs = read(conn)
+	assertBlocked(t, errs)
+	server.Close()
+	<-errs
+}
+
+func TestConn_SetDeadline_Past(t *testing.T) {
+	start := time.Unix(2, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	client, server := net.Pipe()
+	defer server.Close()
+
+	conn := clocknet.WrapConn(fake, client, clocknet.Config{})
+	defer conn.Close()
+
+	if err := conn.SetDeadline(start.Add(-1 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	assertTimeout(t, read(conn))
+}
+
+type pipeListener struct {
+	conns chan net.Conn
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) { return <-l.conns, nil }
+func (l *pipeListener) Close() error              { return nil }
+func (l *pipeListener) Addr() net.Addr            { return nil }
+
+func TestListener(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	client, server := net.Pipe()
+	defer client.Close()
+
+	l := &pipeListener{conns: make(chan net.Conn, 1)}
+	l.conns <- server
+
+	wrapped := clocknet.WrapListener(fake, l, clocknet.Config{IdleTimeout: 1 * time.Second})
+	conn, err := wrapped.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	errs := read(conn)
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+	assertTimeout(t, errs)
+}
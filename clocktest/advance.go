@@ -0,0 +1,94 @@
+package clocktest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// ErrBudgetExhausted is returned by AdvanceUntilRecv when the channel
+// doesn't yield a value within the simulated budget.
+var ErrBudgetExhausted = errors.New("clocktest: simulated budget exhausted")
+
+// AdvanceUntilRecv advances fake to each pending deadline in turn, until c
+// yields a value, which it returns, for at most budget of simulated time:
+//
+//	go worker(fake, results)
+//	result, err := clocktest.AdvanceUntilRecv(ctx, fake, results, time.Minute)
+//
+// Between steps, it waits briefly in real time for c to yield, and for the
+// code under test to arm its timers. While no sleeper is pending, it waits
+// for one to be armed, or for c to yield, until ctx is done.
+//
+// It returns an error wrapping ErrBudgetExhausted if the next deadline is
+// past the budget, one wrapping clock.ErrNothingScheduled if ctx is done
+// before a sleeper is armed, and clock.ErrClosed if c is closed. The clock
+// is left at the time c yielded.
+func AdvanceUntilRecv[T any](ctx context.Context, fake clock.FakeClock, c <-chan T, budget time.Duration) (T, error) {
+	var zero T
+	end := fake.Now().Add(budget)
+
+	timer := time.NewTimer(settleTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case v, ok := <-c:
+			if !ok {
+				return zero, clock.ErrClosed
+			}
+			return v, nil
+		case <-timer.C:
+			timer.Reset(settleTimeout)
+		}
+
+		now := fake.Now()
+		next, ok := fake.NextDeadline()
+		if !ok {
+			v, received, err := awaitArmed(ctx, fake, c)
+			switch {
+			case received:
+				return v, nil
+			case err == clock.ErrClosed:
+				return zero, err
+			case err != nil:
+				return zero, fmt.Errorf("%w at %s, waiting to receive: %v", clock.ErrNothingScheduled, now, err)
+			}
+			continue
+		}
+		if next.After(end) {
+			return zero, fmt.Errorf("%w: %s at %s; pending sleepers:\n%s", ErrBudgetExhausted, budget, now, pendingReport(fake))
+		}
+
+		if err := fake.Advance(next.Sub(now)); err != nil {
+			return zero, err
+		}
+	}
+}
+
+// awaitArmed waits until a sleeper is armed on fake, or c yields, which
+// it reports, or ctx is done. The waiter it registers on fake is gone once
+// it returns.
+func awaitArmed[T any](ctx context.Context, fake clock.FakeClock, c <-chan T) (T, bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	armed := make(chan error, 1)
+	go func() {
+		armed <- fake.BlockUntilContext(ctx, 1)
+	}()
+
+	var zero T
+	select {
+	case v, ok := <-c:
+		if !ok {
+			return zero, false, clock.ErrClosed
+		}
+		return v, true, nil
+	case err := <-armed:
+		return zero, false, err
+	}
+}
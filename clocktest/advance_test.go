@@ -0,0 +1,89 @@
+package clocktest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+)
+
+func TestAdvanceUntilRecv(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	// the worker needs three retries a second apart to get a result
+	results := make(chan string)
+	go func() {
+		for i := 0; i < 3; i++ {
+			fake.Sleep(time.Second)
+		}
+		results <- "done"
+	}()
+
+	result, err := clocktest.AdvanceUntilRecv(context.Background(), fake, results, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "done" {
+		t.Errorf("expected %q got %q", "done", result)
+	}
+	if now := fake.Now(); !now.Equal(start.Add(3 * time.Second)) {
+		t.Errorf("expected the clock left at %s got %s", start.Add(3*time.Second), now)
+	}
+}
+
+func TestAdvanceUntilRecv_BudgetExhausted(t *testing.T) {
+	fake := clock.NewFakeClock()
+	fake.NewTimer(time.Hour).C()
+
+	_, err := clocktest.AdvanceUntilRecv(context.Background(), fake, make(chan int), time.Minute)
+	if !errors.Is(err, clocktest.ErrBudgetExhausted) {
+		t.Errorf("expected %v got %v", clocktest.ErrBudgetExhausted, err)
+	}
+}
+
+func TestAdvanceUntilRecv_LateTimer(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	// the worker arms its timer well after the brief settling wait
+	results := make(chan string)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		fake.Sleep(time.Second)
+		results <- "done"
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	result, err := clocktest.AdvanceUntilRecv(ctx, fake, results, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "done" {
+		t.Errorf("expected %q got %q", "done", result)
+	}
+}
+
+func TestAdvanceUntilRecv_NothingScheduled(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_, err := clocktest.AdvanceUntilRecv(ctx, fake, make(chan int), time.Minute)
+	if !errors.Is(err, clock.ErrNothingScheduled) {
+		t.Errorf("expected %v got %v", clock.ErrNothingScheduled, err)
+	}
+}
+
+func TestAdvanceUntilRecv_Closed(t *testing.T) {
+	c := make(chan int)
+	close(c)
+
+	if _, err := clocktest.AdvanceUntilRecv(context.Background(), clock.NewFakeClock(), c, time.Minute); err != clock.ErrClosed {
+		t.Errorf("expected %v got %v", clock.ErrClosed, err)
+	}
+}
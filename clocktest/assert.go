@@ -0,0 +1,114 @@
+package clocktest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// settleTimeout bounds how long AssertFires and AssertNotFires wait in real
+// time after each step of the clock, for values sent by other goroutines,
+// such as tickers built on AfterFunc.
+const settleTimeout = 10 * time.Millisecond
+
+// AssertFires advances clock, one pending deadline at a time, for at most
+// within, and reports an error unless c delivers a value by then.
+// It returns the value received, and whether c fired.
+// The clock is left at the time c fired.
+//
+//	timer := clock.NewTimer(time.Second)
+//	clocktest.AssertFires(t, clock, timer.C(), 2*time.Second)
+//
+// On failure, the sleepers pending on clock are reported.
+func AssertFires(tb testing.TB, clock clock.FakeClock, c <-chan time.Time, within time.Duration) (time.Time, bool) {
+	tb.Helper()
+
+	start := clock.Now()
+	at, fired, err := step(clock, c, start.Add(within))
+	switch {
+	case err != nil:
+		tb.Errorf("advancing the clock: %s", err)
+	case !fired:
+		tb.Errorf("channel did not fire within %s from %s; pending sleepers:\n%s", within, start, pendingReport(clock))
+	}
+	return at, fired
+}
+
+// AssertNotFires advances clock, one pending deadline at a time, by within,
+// and reports an error if c delivers a value by then.
+// It returns true if c didn't fire.
+//
+// On failure, the sleepers pending on clock are reported.
+func AssertNotFires(tb testing.TB, clock clock.FakeClock, c <-chan time.Time, within time.Duration) bool {
+	tb.Helper()
+
+	start := clock.Now()
+	at, fired, err := step(clock, c, start.Add(within))
+	switch {
+	case err != nil:
+		tb.Errorf("advancing the clock: %s", err)
+	case fired:
+		tb.Errorf("channel fired with %s after %s, within %s from %s; pending sleepers:\n%s",
+			at, clock.Now().Sub(start), within, start, pendingReport(clock))
+	}
+	return !fired
+}
+
+// step advances fake to each pending deadline up to end, then to end,
+// until c fires.
+func step(fake clock.FakeClock, c <-chan time.Time, end time.Time) (time.Time, bool, error) {
+	for {
+		if at, ok := receive(c); ok {
+			return at, true, nil
+		}
+
+		now := fake.Now()
+		if !now.Before(end) {
+			return time.Time{}, false, nil
+		}
+
+		next := end
+		for _, timer := range fake.PendingTimers() {
+			if timer.Deadline.After(now) && timer.Deadline.Before(next) {
+				next = timer.Deadline
+			}
+		}
+
+		if err := fake.Advance(next.Sub(now)); err != nil {
+			if errors.Is(err, clock.ErrNothingScheduled) {
+				// nothing can fire c through the clock
+				at, ok := receive(c)
+				return at, ok, nil
+			}
+			return time.Time{}, false, err
+		}
+	}
+}
+
+// receive waits briefly for a value from c.
+func receive(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case at := <-c:
+		return at, true
+	default:
+	}
+
+	timer := time.NewTimer(settleTimeout)
+	defer timer.Stop()
+
+	select {
+	case at := <-c:
+		return at, true
+	case <-timer.C:
+		return time.Time{}, false
+	}
+}
+
+func pendingReport(clock clock.FakeClock) string {
+	if report := Report(clock); report != "" {
+		return report
+	}
+	return "\t(none)\n"
+}
@@ -0,0 +1,96 @@
+package clocktest_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+)
+
+func TestAssertFires(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	timer := fake.NewTimer(time.Second)
+	other := fake.NewTimer(500 * time.Millisecond)
+	other.C()
+
+	r := &errorRecorder{recorder{TB: t}}
+	at, ok := clocktest.AssertFires(r, fake, timer.C(), 2*time.Second)
+	if r.failed || !ok {
+		t.Fatalf("unexpected failure: %s", r.message)
+	}
+	if expected := start.Add(time.Second); !at.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, at)
+	}
+	if now := fake.Now(); !now.Equal(at) {
+		t.Errorf("expected the clock left at %s, got %s", at, now)
+	}
+}
+
+func TestAssertFires_CustomTicker(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := clock.NewCustomTicker(fake, time.Second)
+	defer ticker.Stop()
+
+	r := &errorRecorder{recorder{TB: t}}
+	if _, ok := clocktest.AssertFires(r, fake, ticker.C(), time.Second); !ok {
+		t.Fatalf("unexpected failure: %s", r.message)
+	}
+}
+
+func TestAssertFires_Timeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	timer := fake.NewTimer(3 * time.Second)
+
+	r := &errorRecorder{recorder{TB: t}}
+	if _, ok := clocktest.AssertFires(r, fake, timer.C(), 2*time.Second); ok || !r.failed {
+		t.Fatal("expected failure")
+	}
+	if !strings.Contains(r.message, "Timer(3s)") {
+		t.Errorf("expected the pending timer in the message, got %q", r.message)
+	}
+}
+
+func TestAssertNotFires(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	timer := fake.NewTimer(3 * time.Second)
+
+	r := &errorRecorder{recorder{TB: t}}
+	if !clocktest.AssertNotFires(r, fake, timer.C(), 2*time.Second) {
+		t.Fatalf("unexpected failure: %s", r.message)
+	}
+	if now, expected := fake.Now(), start.Add(2*time.Second); !now.Equal(expected) {
+		t.Errorf("expected the clock at %s, got %s", expected, now)
+	}
+}
+
+func TestAssertNotFires_Fired(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	timer := fake.NewTimer(time.Second)
+
+	r := &errorRecorder{recorder{TB: t}}
+	if clocktest.AssertNotFires(r, fake, timer.C(), 2*time.Second) || !r.failed {
+		t.Fatal("expected failure")
+	}
+	if !strings.Contains(r.message, "after 1s") {
+		t.Errorf("expected when the channel fired in the message, got %q", r.message)
+	}
+}
+
+func TestAssertNotFires_Strict(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithStrict())
+
+	r := &errorRecorder{recorder{TB: t}}
+	if !clocktest.AssertNotFires(r, fake, make(chan time.Time), time.Second) {
+		t.Fatalf("unexpected failure: %s", r.message)
+	}
+}
@@ -0,0 +1,107 @@
+// Package clocktest provides test helpers for code that depends on a clock.Clock.
+//
+// The helpers wait in real time, bounded by an explicit timeout, so a broken
+// expectation fails the test instead of hanging it. Timeouts are also capped
+// to end a couple of seconds before the deadline of the test, set by the
+// -timeout flag of go test, so a failing helper reports the state of the
+// clock rather than the test binary being killed without output.
+package clocktest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// RequireFiresWithin fails the test unless c delivers a value within timeout.
+// The received value is returned.
+func RequireFiresWithin(tb testing.TB, c <-chan time.Time, timeout time.Duration) time.Time {
+	tb.Helper()
+
+	timeout, limited := limitTimeout(tb, timeout)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case at := <-c:
+		return at
+	case <-timer.C:
+		tb.Fatalf("timeout: channel did not fire within %s%s", timeout, capped(limited))
+		return time.Time{}
+	}
+}
+
+// RequireFiresAtWithin is like RequireFiresWithin
+// but also fails the test if the received value is not expected.
+func RequireFiresAtWithin(tb testing.TB, expected time.Time, c <-chan time.Time, timeout time.Duration) {
+	tb.Helper()
+
+	if actual := RequireFiresWithin(tb, c, timeout); !actual.Equal(expected) {
+		tb.Fatalf("expected %s got %s", expected, actual)
+	}
+}
+
+// RequireNoFireFor fails the test if c delivers a value within d.
+func RequireNoFireFor(tb testing.TB, c <-chan time.Time, d time.Duration) {
+	tb.Helper()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case at := <-c:
+		tb.Fatalf("channel fired unexpectedly with %s", at)
+	case <-timer.C:
+	}
+}
+
+// RequireClosedWithin fails the test unless c is closed within timeout.
+func RequireClosedWithin(tb testing.TB, c <-chan struct{}, timeout time.Duration) {
+	tb.Helper()
+
+	timeout, limited := limitTimeout(tb, timeout)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case _, open := <-c:
+		if open {
+			tb.Fatal("channel received a value instead of closing")
+		}
+	case <-timer.C:
+		tb.Fatalf("timeout: channel not closed within %s%s", timeout, capped(limited))
+	}
+}
+
+// RequireNotClosedFor fails the test if c is closed or receives within d.
+func RequireNotClosedFor(tb testing.TB, c <-chan struct{}, d time.Duration) {
+	tb.Helper()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-c:
+		tb.Fatal("channel closed unexpectedly")
+	case <-timer.C:
+	}
+}
+
+// RequireBlockedWaiters fails the test unless
+// at least n goroutines are blocked on the clock within timeout,
+// listing the sleepers waiting on the clock on failure.
+func RequireBlockedWaiters(tb testing.TB, clock clock.FakeClock, n int, timeout time.Duration) {
+	tb.Helper()
+
+	timeout, limited := limitTimeout(tb, timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// unlike Until, BlockUntilContext leaves no blocker behind on timeout
+	if err := clock.BlockUntilContext(ctx, n); err != nil {
+		tb.Fatalf("timeout: after %s waiting for %d blocked waiters%s, found %d on %s at %s:\n%s",
+			timeout, n, capped(limited), len(clock.PendingTimers()), describe(clock), clock.Now(), Report(clock))
+	}
+}
@@ -0,0 +1,141 @@
+package clocktest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+)
+
+// recorder is a testing.TB that records failures instead of stopping the test.
+type recorder struct {
+	testing.TB
+	failed  bool
+	message string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatal(args ...interface{}) {
+	r.failed = true
+	r.message = fmt.Sprint(args...)
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failed = true
+	r.message = fmt.Sprintf(format, args...)
+}
+
+const timeout = 100 * time.Millisecond
+
+func TestRequireFiresWithin(t *testing.T) {
+	start := time.Unix(1, 0)
+	clock := clock.NewFakeClockAt(start)
+
+	c := clock.After(0)
+
+	r := &recorder{TB: t}
+	if actual := clocktest.RequireFiresWithin(r, c, timeout); actual != start {
+		t.Errorf("expected %s got %s", start, actual)
+	}
+	if r.failed {
+		t.Errorf("unexpected failure: %s", r.message)
+	}
+}
+
+func TestRequireFiresWithin_Timeout(t *testing.T) {
+	clock := clock.NewFakeClock()
+
+	c := clock.After(1 * time.Second)
+
+	r := &recorder{TB: t}
+	clocktest.RequireFiresWithin(r, c, timeout)
+	if !r.failed {
+		t.Error("expected failure")
+	}
+}
+
+func TestRequireFiresAtWithin_Mismatch(t *testing.T) {
+	start := time.Unix(1, 0)
+	clock := clock.NewFakeClockAt(start)
+
+	c := clock.After(0)
+
+	r := &recorder{TB: t}
+	clocktest.RequireFiresAtWithin(r, start.Add(1*time.Second), c, timeout)
+	if !r.failed {
+		t.Error("expected failure")
+	}
+}
+
+func TestRequireNoFireFor(t *testing.T) {
+	clock := clock.NewFakeClock()
+
+	r := &recorder{TB: t}
+	clocktest.RequireNoFireFor(r, clock.After(1*time.Second), timeout)
+	if r.failed {
+		t.Errorf("unexpected failure: %s", r.message)
+	}
+
+	clocktest.RequireNoFireFor(r, clock.After(0), timeout)
+	if !r.failed {
+		t.Error("expected failure")
+	}
+}
+
+func TestRequireClosedWithin(t *testing.T) {
+	c := make(chan struct{})
+	close(c)
+
+	r := &recorder{TB: t}
+	clocktest.RequireClosedWithin(r, c, timeout)
+	if r.failed {
+		t.Errorf("unexpected failure: %s", r.message)
+	}
+
+	clocktest.RequireClosedWithin(r, make(chan struct{}), timeout)
+	if !r.failed {
+		t.Error("expected failure")
+	}
+}
+
+func TestRequireNotClosedFor(t *testing.T) {
+	r := &recorder{TB: t}
+	clocktest.RequireNotClosedFor(r, make(chan struct{}), timeout)
+	if r.failed {
+		t.Errorf("unexpected failure: %s", r.message)
+	}
+
+	c := make(chan struct{})
+	close(c)
+	clocktest.RequireNotClosedFor(r, c, timeout)
+	if !r.failed {
+		t.Error("expected failure")
+	}
+}
+
+func TestRequireBlockedWaiters(t *testing.T) {
+	clock := clock.NewFakeClock()
+
+	go clock.Sleep(1 * time.Second)
+
+	r := &recorder{TB: t}
+	clocktest.RequireBlockedWaiters(r, clock, 1, timeout)
+	if r.failed {
+		t.Errorf("unexpected failure: %s", r.message)
+	}
+
+	clocktest.RequireBlockedWaiters(r, clock, 2, timeout)
+	if !r.failed {
+		t.Error("expected failure")
+	}
+	if !strings.Contains(r.message, "found 1") || !strings.Contains(r.message, "Sleep(1s)") {
+		t.Errorf("expected the sleeper in the message got %q", r.message)
+	}
+	if n := clock.Stats().Blockers; n != 0 {
+		t.Errorf("expected no blockers left, got %d", n)
+	}
+}
@@ -0,0 +1,59 @@
+package clocktest
+
+import (
+	"testing"
+	"time"
+)
+
+// deadlineGrace is how long before the deadline of the test the helpers
+// give up waiting in real time, leaving room to report the state of the
+// clock before go test kills the test binary, which prints no output of
+// the tests that are still running.
+const deadlineGrace = 2 * time.Second
+
+// deadliner is implemented by testing.T, whose deadline is set by the
+// -timeout flag of go test. testing.TB doesn't include Deadline.
+type deadliner interface {
+	Deadline() (time.Time, bool)
+}
+
+// giveUpIn returns how long, in real time, tb can wait before giving up:
+// until a grace period before its deadline, or half the time left if that's
+// shorter. It returns false if tb has no deadline.
+func giveUpIn(tb testing.TB) (time.Duration, bool) {
+	d, ok := tb.(deadliner)
+	if !ok {
+		return 0, false
+	}
+	deadline, ok := d.Deadline()
+	if !ok {
+		return 0, false
+	}
+
+	remaining := time.Until(deadline)
+	grace := deadlineGrace
+	if grace > remaining/2 {
+		grace = remaining / 2
+	}
+	if remaining-grace < 0 {
+		return 0, true
+	}
+	return remaining - grace, true
+}
+
+// limitTimeout caps a real-time timeout so that waiting for it fails the
+// test before its deadline. It also reports whether the timeout was capped.
+func limitTimeout(tb testing.TB, timeout time.Duration) (time.Duration, bool) {
+	if limit, ok := giveUpIn(tb); ok && limit < timeout {
+		return limit, true
+	}
+	return timeout, false
+}
+
+// capped describes a timeout capped by limitTimeout in failure messages.
+func capped(limited bool) string {
+	if limited {
+		return ", giving up before the test deadline"
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package clocktest_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+)
+
+// deadlineRecorder is an errorRecorder with a deadline, like testing.T run
+// with -timeout.
+type deadlineRecorder struct {
+	errorRecorder
+	deadline time.Time
+}
+
+func (r *deadlineRecorder) Deadline() (time.Time, bool) {
+	return r.deadline, true
+}
+
+func TestRequireFiresWithin_Deadline(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	r := &deadlineRecorder{errorRecorder{recorder{TB: t}}, time.Now().Add(100 * time.Millisecond)}
+	start := time.Now()
+	clocktest.RequireFiresWithin(r, fake.After(time.Second), time.Hour)
+
+	if !r.failed {
+		t.Fatal("expected failure")
+	}
+	if !strings.Contains(r.message, "before the test deadline") {
+		t.Errorf("expected the deadline in the message, got %q", r.message)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected to give up before the deadline, waited %s", elapsed)
+	}
+}
+
+func TestRun_Deadline(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	r := &deadlineRecorder{errorRecorder{recorder{TB: t}}, time.Now().Add(100 * time.Millisecond)}
+	clocktest.Run(r, fake, time.Hour, func(ctx context.Context) {
+		// blocks on something other than the clock, which Run can't advance
+		<-ctx.Done()
+	})
+
+	if !r.failed {
+		t.Fatal("expected failure")
+	}
+	if !strings.Contains(r.message, "before the test deadline") || !strings.Contains(r.message, "(none)") {
+		t.Errorf("expected the deadline and no pending sleepers in the message, got %q", r.message)
+	}
+}
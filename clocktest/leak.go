@@ -0,0 +1,43 @@
+package clocktest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// VerifyNone fails the test if any sleepers are still waiting on clock,
+// reporting the kind, requested duration and caller of each one.
+//
+// It is intended to run at the end of a test, either deferred or via tb.Cleanup.
+// Goroutines parked in clock.Sleep or on a clock channel also show up as leaks in goleak;
+// running VerifyNone first reports them in terms of the clock calls that created them.
+func VerifyNone(tb testing.TB, clock clock.FakeClock) {
+	tb.Helper()
+
+	if report := Report(clock); report != "" {
+		tb.Errorf("found sleepers waiting on %s at %s:\n%s", describe(clock), clock.Now(), report)
+	}
+}
+
+// describe names clock in messages, if it has a name.
+func describe(c clock.Clock) string {
+	if name := clock.Name(c); name != "" {
+		return fmt.Sprintf("clock %q", name)
+	}
+	return "the clock"
+}
+
+// Report formats the sleepers waiting on clock, one per line.
+// It returns an empty string if there are none.
+func Report(clock clock.FakeClock) string {
+	var b strings.Builder
+	for _, timer := range clock.PendingTimers() {
+		b.WriteString("\t")
+		b.WriteString(timer.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,49 @@
+package clocktest_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+)
+
+type errorRecorder struct {
+	recorder
+}
+
+func (r *errorRecorder) Errorf(format string, args ...interface{}) {
+	r.Fatalf(format, args...)
+}
+
+func TestVerifyNone(t *testing.T) {
+	clock := clock.NewFakeClock()
+
+	r := &errorRecorder{recorder{TB: t}}
+	clocktest.VerifyNone(r, clock)
+	if r.failed {
+		t.Errorf("unexpected failure: %s", r.message)
+	}
+}
+
+func TestVerifyNone_SleepingGoroutine(t *testing.T) {
+	clock := clock.NewFakeClock()
+
+	go func() { clock.Sleep(5 * time.Minute) }()
+	clock.BlockUntil(1)
+
+	r := &errorRecorder{recorder{TB: t}}
+	clocktest.VerifyNone(r, clock)
+	if !r.failed {
+		t.Fatal("expected failure")
+	}
+	if !strings.Contains(r.message, "Sleep(5m)") {
+		t.Errorf("expected the requested duration in %q", r.message)
+	}
+	if !strings.Contains(r.message, "leak_test.go") {
+		t.Errorf("expected the caller in %q", r.message)
+	}
+
+	clock.Advance(5 * time.Minute)
+}
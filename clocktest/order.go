@@ -0,0 +1,41 @@
+package clocktest
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// SeedEnv is the environment variable RandomOrder reads its seed from.
+const SeedEnv = "CLOCKTEST_SEED"
+
+// RandomOrder returns a clock.WithRandomOrder option, seeded from the
+// CLOCKTEST_SEED environment variable if it's set, or from the current time.
+// If the test fails, the seed is logged so the failure can be reproduced:
+//
+//	clock := clock.NewFakeClock(clocktest.RandomOrder(t))
+//
+// Running the suite repeatedly shakes out tests that depend on the order
+// sleepers with equal deadlines wake in.
+func RandomOrder(tb testing.TB) clock.FakeOption {
+	tb.Helper()
+
+	seed := time.Now().UnixNano()
+	if s := os.Getenv(SeedEnv); s != "" {
+		var err error
+		seed, err = strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			tb.Fatalf("invalid %s %q: %s", SeedEnv, s, err)
+		}
+	}
+
+	tb.Cleanup(func() {
+		if tb.Failed() {
+			tb.Logf("fake clock wake order seed: %d (rerun with %s=%d)", seed, SeedEnv, seed)
+		}
+	})
+	return clock.WithRandomOrder(seed)
+}
@@ -0,0 +1,77 @@
+package clocktest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+)
+
+type logRecorder struct {
+	recorder
+	cleanups []func()
+	logs     []string
+}
+
+func (r *logRecorder) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+func (r *logRecorder) Failed() bool {
+	return r.failed
+}
+
+func (r *logRecorder) Logf(format string, args ...interface{}) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func (r *logRecorder) cleanup() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func TestRandomOrder(t *testing.T) {
+	t.Setenv(clocktest.SeedEnv, "42")
+
+	r := &logRecorder{recorder: recorder{TB: t}}
+	fake := clock.NewFakeClock(clocktest.RandomOrder(r))
+	if r.failed {
+		t.Fatalf("unexpected failure: %s", r.message)
+	}
+
+	c := fake.After(time.Second)
+	_ = fake.Advance(time.Second)
+	clocktest.RequireFiresWithin(t, c, timeout)
+
+	r.cleanup()
+	if len(r.logs) != 0 {
+		t.Errorf("expected no seed logged for a passing test, got %q", r.logs)
+	}
+}
+
+func TestRandomOrder_LogsSeedOnFailure(t *testing.T) {
+	t.Setenv(clocktest.SeedEnv, "42")
+
+	r := &logRecorder{recorder: recorder{TB: t}}
+	clocktest.RandomOrder(r)
+	r.failed = true
+
+	r.cleanup()
+	if len(r.logs) != 1 || !strings.Contains(r.logs[0], clocktest.SeedEnv+"=42") {
+		t.Errorf("expected the seed to be logged, got %q", r.logs)
+	}
+}
+
+func TestRandomOrder_InvalidSeed(t *testing.T) {
+	t.Setenv(clocktest.SeedEnv, "nope")
+
+	r := &logRecorder{recorder: recorder{TB: t}}
+	clocktest.RandomOrder(r)
+	if !r.failed {
+		t.Fatal("expected failure")
+	}
+}
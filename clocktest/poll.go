@@ -0,0 +1,107 @@
+package clocktest
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// Eventually fails the test unless cond returns true within timeout, polling every interval.
+//
+// Time is measured by clock. If clock is a clock.FakeClock,
+// Eventually advances it by interval between polls instead of waiting,
+// so the test takes no wall time.
+func Eventually(tb testing.TB, clock clock.Clock, cond func() bool, timeout, interval time.Duration) {
+	tb.Helper()
+
+	met, err := poll(clock, func() bool { return !cond() }, timeout, interval)
+	switch {
+	case err != nil:
+		tb.Fatalf("advancing the clock: %s", err)
+	case !met:
+		tb.Fatalf("condition not met within %s", timeout)
+	}
+}
+
+// Never fails the test if cond returns true within d, polling every interval.
+// See Eventually for how time is measured.
+func Never(tb testing.TB, clock clock.Clock, cond func() bool, d, interval time.Duration) {
+	tb.Helper()
+
+	met, err := poll(clock, func() bool { return !cond() }, d, interval)
+	switch {
+	case err != nil:
+		tb.Fatalf("advancing the clock: %s", err)
+	case met:
+		tb.Fatalf("condition met within %s", d)
+	}
+}
+
+// Consistently fails the test unless cond keeps returning true for d, polling every interval.
+// See Eventually for how time is measured.
+func Consistently(tb testing.TB, clock clock.Clock, cond func() bool, d, interval time.Duration) {
+	tb.Helper()
+
+	failed, err := poll(clock, cond, d, interval)
+	switch {
+	case err != nil:
+		tb.Fatalf("advancing the clock: %s", err)
+	case failed:
+		tb.Fatalf("condition not met at some point within %s", d)
+	}
+}
+
+// poll calls cond every interval for d as long as it returns true.
+// It reports whether cond returned false, or the error of a fake clock that
+// refused to advance, such as one built with clock.WithStrict or
+// clock.WithMaxAdvance.
+func poll(c clock.Clock, cond func() bool, d, interval time.Duration) (bool, error) {
+	if fake, ok := c.(clock.FakeClock); ok {
+		return pollFake(fake, cond, d, interval)
+	}
+
+	timer := c.NewTimer(d)
+	defer timer.Stop()
+	ticker := c.NewTicker(interval)
+	defer ticker.Stop()
+
+	expired := timer.C()
+	for tick := ticker.C(); ; {
+		if !cond() {
+			return true, nil
+		}
+
+		select {
+		case <-expired:
+			return !cond(), nil
+		case <-tick:
+			tick = ticker.C()
+		}
+	}
+}
+
+func pollFake(fake clock.FakeClock, cond func() bool, d, interval time.Duration) (bool, error) {
+	deadline := fake.Now().Add(d)
+	for {
+		// give goroutines woken by the last advance a chance to run
+		runtime.Gosched()
+
+		if !cond() {
+			return true, nil
+		}
+
+		remaining := deadline.Sub(fake.Now())
+		if remaining <= 0 {
+			return false, nil
+		}
+		if remaining > interval {
+			remaining = interval
+		}
+		// the clock doesn't move, so the deadline would never be reached
+		if err := fake.Advance(remaining); err != nil {
+			return false, err
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package clocktest_test
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+)
+
+func TestEventually(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	r := &recorder{TB: t}
+	clocktest.Eventually(r, fake, func() bool {
+		return !fake.Now().Before(start.Add(3 * time.Second))
+	}, 1*time.Minute, 1*time.Second)
+	if r.failed {
+		t.Errorf("unexpected failure: %s", r.message)
+	}
+
+	if actual := fake.Now(); actual != start.Add(3*time.Second) {
+		t.Errorf("expected clock at %s got %s", start.Add(3*time.Second), actual)
+	}
+}
+
+func TestEventually_Timeout(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	r := &recorder{TB: t}
+	clocktest.Eventually(r, fake, func() bool { return false }, 1*time.Minute, 7*time.Second)
+	if !r.failed {
+		t.Error("expected failure")
+	}
+
+	if actual := fake.Now(); actual != start.Add(1*time.Minute) {
+		t.Errorf("expected clock at %s got %s", start.Add(1*time.Minute), actual)
+	}
+}
+
+func TestEventually_RealClock(t *testing.T) {
+	var done int32
+	time.AfterFunc(20*time.Millisecond, func() { atomic.StoreInt32(&done, 1) })
+
+	r := &recorder{TB: t}
+	clocktest.Eventually(r, clock.NewRealClock(), func() bool {
+		return atomic.LoadInt32(&done) == 1
+	}, 1*time.Second, 5*time.Millisecond)
+	if r.failed {
+		t.Errorf("unexpected failure: %s", r.message)
+	}
+}
+
+func TestNever(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	r := &recorder{TB: t}
+	clocktest.Never(r, fake, func() bool { return false }, 1*time.Minute, 1*time.Second)
+	if r.failed {
+		t.Errorf("unexpected failure: %s", r.message)
+	}
+
+	clocktest.Never(r, fake, func() bool { return true }, 1*time.Minute, 1*time.Second)
+	if !r.failed {
+		t.Error("expected failure")
+	}
+}
+
+func TestConsistently(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	r := &recorder{TB: t}
+	clocktest.Consistently(r, fake, func() bool { return true }, 1*time.Minute, 1*time.Second)
+	if r.failed {
+		t.Errorf("unexpected failure: %s", r.message)
+	}
+
+	clocktest.Consistently(r, fake, func() bool {
+		return fake.Now().Before(start.Add(90 * time.Second))
+	}, 1*time.Minute, 1*time.Second)
+	if !r.failed {
+		t.Error("expected failure")
+	}
+}
+
+func TestEventually_Strict(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithStrict())
+
+	// nothing is scheduled, so the clock refuses to advance
+	r := &recorder{TB: t}
+	clocktest.Eventually(r, fake, func() bool { return false }, 1*time.Minute, 1*time.Second)
+	if !r.failed || !strings.Contains(r.message, "advancing the clock") {
+		t.Errorf("expected failure advancing the clock got %q", r.message)
+	}
+}
+
+func TestConsistently_MaxAdvance(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithMaxAdvance(time.Second))
+
+	r := &recorder{TB: t}
+	clocktest.Consistently(r, fake, func() bool { return true }, 1*time.Minute, 2*time.Second)
+	if !r.failed || !strings.Contains(r.message, clock.ErrAdvanceTooFar.Error()) {
+		t.Errorf("expected failure advancing the clock got %q", r.message)
+	}
+}
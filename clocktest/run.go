@@ -0,0 +1,167 @@
+package clocktest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// quietPeriod is how long, in real time, the sleepers on the clock must stay
+// unchanged before Run advances it, so goroutines woken by the last advance
+// can register their next sleepers first.
+const quietPeriod = time.Millisecond
+
+// Run calls f, advancing clock to the next pending deadline whenever the
+// sleepers waiting on it settle, so time flows as f and the goroutines it
+// starts need, without scripting each Advance.
+//
+// Advancing stops once it would take the clock more than budget past its
+// time when Run was called: ctx is canceled and the test fails, with the
+// sleepers still pending. f should return once ctx is done.
+//
+// If the test has a deadline, ctx is also canceled a couple of seconds
+// before it, and the test fails with the sleepers pending then, so a test
+// that hangs reports them before go test kills it.
+//
+// f should only block on the clock, or briefly on its own goroutines:
+// while f waits on anything else for more than a moment, Run may
+// advance the clock past timers f has yet to create.
+func Run(tb testing.TB, clock clock.FakeClock, budget time.Duration, f func(ctx context.Context)) {
+	tb.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stopped, stop := context.WithCancel(context.Background())
+	controller := &autoAdvance{
+		clock:   clock,
+		end:     clock.Now().Add(budget),
+		stopped: stopped,
+		done:    stopped.Done(),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		controller.run(cancel)
+	}()
+
+	expired := make(chan string, 1)
+	if limit, ok := giveUpIn(tb); ok {
+		timer := time.AfterFunc(limit, func() {
+			expired <- pendingReport(clock)
+			cancel()
+		})
+		defer timer.Stop()
+	}
+
+	f(ctx)
+
+	stop()
+	wg.Wait()
+
+	select {
+	case pending := <-expired:
+		tb.Errorf("giving up before the test deadline at %s; pending sleepers:\n%s", clock.Now(), pending)
+		return
+	default:
+	}
+
+	switch {
+	case controller.err != nil:
+		tb.Errorf("advancing the clock: %s", controller.err)
+	case controller.exhausted:
+		tb.Errorf("simulated budget of %s exhausted at %s; pending sleepers:\n%s",
+			budget, clock.Now(), controller.pending)
+	}
+}
+
+// autoAdvance is the controller advancing the clock for Run.
+type autoAdvance struct {
+	clock clock.FakeClock
+	end   time.Time
+
+	// stopped is canceled, closing done, once f returns
+	stopped context.Context
+	done    <-chan struct{}
+
+	// set before run returns
+	err       error
+	exhausted bool
+	pending   string
+}
+
+func (a *autoAdvance) run(cancel context.CancelFunc) {
+	for {
+		// unlike Until, BlockUntilContext leaves no blocker behind once
+		// Run is done
+		if err := a.clock.BlockUntilContext(a.stopped, 1); err != nil {
+			return
+		}
+
+		next, ok := a.settle()
+		if !ok {
+			continue
+		}
+
+		if next.After(a.end) {
+			a.exhausted = true
+			a.pending = pendingReport(a.clock)
+			cancel()
+			<-a.done
+			return
+		}
+
+		d := next.Sub(a.clock.Now())
+		if d < 0 {
+			d = 0
+		}
+		if err := a.clock.Advance(d); err != nil {
+			a.err = err
+			cancel()
+			<-a.done
+			return
+		}
+	}
+}
+
+// settle waits until the earliest pending deadline stays the same for a
+// quiet period, and returns it. It returns false if Run is done, or if the
+// sleepers are all gone.
+func (a *autoAdvance) settle() (time.Time, bool) {
+	timer := time.NewTimer(quietPeriod)
+	defer timer.Stop()
+
+	last, n := a.earliest()
+	for {
+		select {
+		case <-a.done:
+			return time.Time{}, false
+		case <-timer.C:
+		}
+
+		next, m := a.earliest()
+		if m == 0 {
+			return time.Time{}, false
+		}
+		if m == n && next.Equal(last) {
+			return next, true
+		}
+
+		last, n = next, m
+		timer.Reset(quietPeriod)
+	}
+}
+
+// earliest returns the earliest pending deadline, and the number of sleepers.
+func (a *autoAdvance) earliest() (time.Time, int) {
+	timers := a.clock.PendingTimers()
+	if len(timers) == 0 {
+		return time.Time{}, 0
+	}
+	return timers[0].Deadline, len(timers)
+}
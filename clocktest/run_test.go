@@ -0,0 +1,94 @@
+package clocktest_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+)
+
+func TestRun(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	clocktest.Run(t, fake, time.Hour, func(ctx context.Context) {
+		// sleeps in goroutines and in f itself
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 3; i++ {
+				fake.Sleep(10 * time.Second)
+			}
+		}()
+
+		fake.Sleep(time.Minute)
+		<-done
+	})
+
+	if now, expected := fake.Now(), start.Add(time.Minute); !now.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, now)
+	}
+}
+
+func TestRun_Ticker(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticks := 0
+	clocktest.Run(t, fake, time.Hour, func(ctx context.Context) {
+		ticker := fake.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for c := ticker.C(); ticks < 5; c = ticker.C() {
+			<-c
+			ticks++
+		}
+	})
+
+	if now, expected := fake.Now(), start.Add(5*time.Second); !now.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, now)
+	}
+}
+
+func TestRun_NoBlockersLeft(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	for i := 0; i < 10; i++ {
+		clocktest.Run(t, fake, time.Hour, func(ctx context.Context) {
+			fake.Sleep(time.Second)
+		})
+	}
+
+	if n := fake.Stats().Blockers; n != 0 {
+		t.Errorf("expected no blockers left, got %d", n)
+	}
+}
+
+func TestRun_BudgetExhausted(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	r := &errorRecorder{recorder{TB: t}}
+	clocktest.Run(r, fake, time.Minute, func(ctx context.Context) {
+		timer := fake.NewTimer(time.Hour)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+		}
+	})
+
+	if !r.failed {
+		t.Fatal("expected failure")
+	}
+	if !strings.Contains(r.message, "Timer(1h)") {
+		t.Errorf("expected the pending timer in the message, got %q", r.message)
+	}
+	if now := fake.Now(); !now.Equal(start) {
+		t.Errorf("expected the clock not to advance, got %s", now)
+	}
+}
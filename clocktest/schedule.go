@@ -0,0 +1,428 @@
+package clocktest
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// OpKind is the kind of an Op in a Schedule.
+type OpKind int
+
+const (
+	// OpNewTimer creates a timer firing after D.
+	OpNewTimer OpKind = iota
+	// OpNewTicker creates a ticker ticking every D.
+	OpNewTicker
+	// OpAfterFunc schedules a callback after D.
+	OpAfterFunc
+	// OpStop stops the Target-th timer, ticker or callback created.
+	OpStop
+	// OpReset resets the Target-th timer, ticker or callback created to D.
+	// A ticker is reset to 1s instead of a non-positive D.
+	OpReset
+	// OpAdvance advances the clock by D.
+	OpAdvance
+)
+
+var opNames = [...]string{
+	OpNewTimer:  "NewTimer",
+	OpNewTicker: "NewTicker",
+	OpAfterFunc: "AfterFunc",
+	OpStop:      "Stop",
+	OpReset:     "Reset",
+	OpAdvance:   "Advance",
+}
+
+func (kind OpKind) String() string {
+	if kind < 0 || int(kind) >= len(opNames) {
+		return fmt.Sprintf("OpKind(%d)", int(kind))
+	}
+	return opNames[kind]
+}
+
+// An Op is a step of a Schedule.
+type Op struct {
+	Kind OpKind
+	// Target is the index, in creation order, of the timer an OpStop or
+	// OpReset applies to. It wraps around the number of timers created,
+	// and the op is skipped if there are none.
+	Target int
+	D      time.Duration
+}
+
+func (op Op) String() string {
+	switch op.Kind {
+	case OpStop:
+		return fmt.Sprintf("%s(#%d)", op.Kind, op.Target)
+	case OpReset:
+		return fmt.Sprintf("%s(#%d, %s)", op.Kind, op.Target, op.D)
+	default:
+		return fmt.Sprintf("%s(%s)", op.Kind, op.D)
+	}
+}
+
+// A Schedule is a sequence of operations on a fake clock.
+type Schedule []Op
+
+// String formats the schedule one op per line.
+func (s Schedule) String() string {
+	var b strings.Builder
+	for i, op := range s {
+		fmt.Fprintf(&b, "\t%d: %s\n", i, op)
+	}
+	return b.String()
+}
+
+// GenerateSchedule returns a random schedule of n ops drawn from seed.
+// The same seed always gives the same schedule.
+//
+// Durations are whole seconds from a small range, so deadlines often tie.
+func GenerateSchedule(seed int64, n int) Schedule {
+	r := rand.New(rand.NewSource(seed))
+
+	s := make(Schedule, 0, n)
+	created := 0
+	for len(s) < n {
+		var op Op
+		switch k := r.Intn(10); {
+		case k < 2:
+			op = Op{Kind: OpNewTimer, D: time.Duration(r.Intn(6)) * time.Second}
+		case k < 3:
+			op = Op{Kind: OpNewTicker, D: time.Duration(1+r.Intn(3)) * time.Second}
+		case k < 4:
+			op = Op{Kind: OpAfterFunc, D: time.Duration(r.Intn(6)) * time.Second}
+		case k < 5:
+			op = Op{Kind: OpStop, Target: r.Intn(created + 1)}
+		case k < 7:
+			op = Op{Kind: OpReset, Target: r.Intn(created + 1), D: time.Duration(r.Intn(6)) * time.Second}
+		default:
+			op = Op{Kind: OpAdvance, D: time.Duration(r.Intn(5)) * time.Second}
+		}
+		if op.Kind <= OpAfterFunc {
+			created++
+		}
+		s = append(s, op)
+	}
+	return s
+}
+
+// callbackTimeout bounds how long CheckSchedule waits in real time for
+// AfterFunc callbacks, which run on their own goroutines.
+const callbackTimeout = time.Second
+
+// CheckSchedule runs s against a clock from newClock, checking after every
+// op that no wakeup is lost or delivered twice, that channels deliver their
+// deadlines in order, that Stop and Reset report whether the timer was
+// active, and that the clock counts the expected number of pending sleepers.
+// It returns an error describing the first violation.
+//
+// Timer and ticker channels are received from as soon as they're due, and a
+// ticker's C is called again after each tick, as the README recommends.
+// The clock must use the default Inclusive boundary.
+func CheckSchedule(newClock func() clock.FakeClock, s Schedule) error {
+	run := &scheduleRun{clock: newClock()}
+	for i, op := range s {
+		if err := run.apply(op); err != nil {
+			return fmt.Errorf("op %d %s: %w", i, op, err)
+		}
+		if err := run.check(); err != nil {
+			return fmt.Errorf("after op %d %s at %s: %w", i, op, run.clock.Now(), err)
+		}
+	}
+	return nil
+}
+
+// MinimizeSchedule returns a shorter schedule failing CheckSchedule, found by
+// removing ops and shrinking targets and durations from s as long as it keeps failing.
+// It returns s if s doesn't fail.
+func MinimizeSchedule(newClock func() clock.FakeClock, s Schedule) Schedule {
+	if CheckSchedule(newClock, s) == nil {
+		return s
+	}
+
+	for shrunk := true; shrunk; {
+		shrunk = false
+
+		for i := len(s) - 1; i >= 0; i-- {
+			candidate := append(append(Schedule{}, s[:i]...), s[i+1:]...)
+			if CheckSchedule(newClock, candidate) != nil {
+				s = candidate
+				shrunk = true
+			}
+		}
+
+		for i := range s {
+			for s[i].Target > 0 {
+				candidate := append(Schedule{}, s...)
+				candidate[i].Target--
+				if CheckSchedule(newClock, candidate) == nil {
+					break
+				}
+				s = candidate
+				shrunk = true
+			}
+			for s[i].D >= time.Second {
+				candidate := append(Schedule{}, s...)
+				candidate[i].D -= time.Second
+				if CheckSchedule(newClock, candidate) == nil {
+					break
+				}
+				s = candidate
+				shrunk = true
+			}
+		}
+	}
+	return s
+}
+
+// CheckSchedules runs n random schedules of size ops, drawn from seeds seed
+// to seed+n-1, against clocks from newClock. If one fails, the test fails
+// with the seed and the minimized schedule, which can be replayed with:
+//
+//	err := clocktest.CheckSchedule(newClock, clocktest.GenerateSchedule(seed, size))
+func CheckSchedules(tb testing.TB, newClock func() clock.FakeClock, seed int64, n, size int) {
+	tb.Helper()
+
+	for i := int64(0); i < int64(n); i++ {
+		s := GenerateSchedule(seed+i, size)
+		if err := CheckSchedule(newClock, s); err != nil {
+			minimized := MinimizeSchedule(newClock, s)
+			tb.Fatalf("schedule from seed %d (size %d) failed: %s\nminimized to:\n%s%s",
+				seed+i, size, err, minimized, CheckSchedule(newClock, minimized))
+		}
+	}
+}
+
+// scheduleEntry models a timer, ticker or callback created by a schedule.
+type scheduleEntry struct {
+	kind     OpKind
+	timer    clock.Timer
+	ticker   clock.Ticker
+	c        <-chan time.Time
+	fired    chan struct{}
+	deadline time.Time
+	interval time.Duration
+	active   bool
+}
+
+type scheduleRun struct {
+	clock   clock.FakeClock
+	entries []*scheduleEntry
+
+	// callbacks expected to have run so far
+	callbacks int
+	fired     int
+}
+
+func (run *scheduleRun) target(op Op) *scheduleEntry {
+	if len(run.entries) == 0 {
+		return nil
+	}
+	return run.entries[op.Target%len(run.entries)]
+}
+
+func (run *scheduleRun) apply(op Op) error {
+	now := run.clock.Now()
+
+	switch op.Kind {
+	case OpNewTimer:
+		timer := run.clock.NewTimer(op.D)
+		run.entries = append(run.entries, &scheduleEntry{
+			kind:     op.Kind,
+			timer:    timer,
+			c:        timer.C(),
+			deadline: now.Add(op.D),
+			active:   true,
+		})
+
+	case OpNewTicker:
+		ticker := run.clock.NewTicker(op.D)
+		run.entries = append(run.entries, &scheduleEntry{
+			kind:     op.Kind,
+			ticker:   ticker,
+			c:        ticker.C(),
+			deadline: now.Add(op.D),
+			interval: op.D,
+			active:   true,
+		})
+
+	case OpAfterFunc:
+		fired := make(chan struct{}, 1)
+		entry := &scheduleEntry{
+			kind:     op.Kind,
+			fired:    fired,
+			deadline: now.Add(op.D),
+			active:   true,
+		}
+		entry.timer = run.clock.AfterFunc(op.D, func() { fired <- struct{}{} })
+		run.entries = append(run.entries, entry)
+
+	case OpStop:
+		entry := run.target(op)
+		if entry == nil {
+			return nil
+		}
+		if entry.kind == OpNewTicker {
+			entry.ticker.Stop()
+			entry.active = false
+			return nil
+		}
+		if active := entry.timer.Stop(); active != entry.active {
+			return fmt.Errorf("Stop returned %t for a timer that was active: %t", active, entry.active)
+		}
+		entry.active = false
+
+	case OpReset:
+		entry := run.target(op)
+		if entry == nil {
+			return nil
+		}
+		if entry.kind == OpNewTicker {
+			d := op.D
+			if d <= 0 {
+				d = time.Second
+			}
+			entry.ticker.Reset(d)
+			entry.deadline = now.Add(d)
+			entry.interval = d
+			entry.active = true
+			return nil
+		}
+		if active := entry.timer.Reset(op.D); active != entry.active {
+			return fmt.Errorf("Reset returned %t for a timer that was active: %t", active, entry.active)
+		}
+		entry.deadline = now.Add(op.D)
+		entry.active = true
+
+	case OpAdvance:
+		if err := run.clock.Advance(op.D); err != nil {
+			return err
+		}
+		if actual := run.clock.Now(); !actual.Equal(now.Add(op.D)) {
+			return fmt.Errorf("expected the clock at %s, got %s", now.Add(op.D), actual)
+		}
+	}
+	return nil
+}
+
+func (run *scheduleRun) check() error {
+	now := run.clock.Now()
+
+	pending := 0
+	for i, entry := range run.entries {
+		// tickers deliver every tick due, one at a time, as C is called
+		for entry.active && !entry.deadline.After(now) {
+			if err := entry.expect(entry.deadline); err != nil {
+				return fmt.Errorf("#%d %s: %w", i, entry.kind, err)
+			}
+
+			switch entry.kind {
+			case OpNewTicker:
+				entry.deadline = entry.deadline.Add(entry.interval)
+				entry.c = entry.ticker.C()
+			case OpAfterFunc:
+				run.callbacks++
+				entry.active = false
+			default:
+				entry.active = false
+			}
+		}
+
+		if entry.active {
+			pending++
+		}
+		if err := entry.expectNone(); err != nil {
+			return fmt.Errorf("#%d %s due at %s: %w", i, entry.kind, entry.deadline, err)
+		}
+	}
+
+	if err := run.waitCallbacks(); err != nil {
+		return err
+	}
+	for i, entry := range run.entries {
+		if entry.kind != OpAfterFunc {
+			continue
+		}
+		select {
+		case <-entry.fired:
+			return fmt.Errorf("#%d %s: callback ran more than once", i, entry.kind)
+		default:
+		}
+	}
+
+	if timers := run.clock.PendingTimers(); len(timers) != pending {
+		return fmt.Errorf("expected %d pending sleepers, got %d:\n%s", pending, len(timers), Report(run.clock))
+	}
+	return nil
+}
+
+// waitCallbacks waits for the callbacks expected so far to run.
+func (run *scheduleRun) waitCallbacks() error {
+	if run.fired == run.callbacks {
+		return nil
+	}
+
+	timer := time.NewTimer(callbackTimeout)
+	defer timer.Stop()
+
+	for run.fired < run.callbacks {
+		fired := false
+		for _, entry := range run.entries {
+			if entry.kind != OpAfterFunc {
+				continue
+			}
+			select {
+			case <-entry.fired:
+				run.fired++
+				fired = true
+			default:
+			}
+		}
+		if fired {
+			continue
+		}
+
+		select {
+		case <-timer.C:
+			return fmt.Errorf("timeout: %d of %d callbacks ran within %s", run.fired, run.callbacks, callbackTimeout)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	return nil
+}
+
+// expect checks that the entry delivered a value at at.
+func (entry *scheduleEntry) expect(at time.Time) error {
+	if entry.kind == OpAfterFunc {
+		return nil
+	}
+
+	select {
+	case actual := <-entry.c:
+		if !actual.Equal(at) {
+			return fmt.Errorf("expected a value at %s, got %s", at, actual)
+		}
+		return nil
+	default:
+		return fmt.Errorf("lost wakeup: expected a value at %s", at)
+	}
+}
+
+// expectNone checks that the entry has delivered nothing more.
+func (entry *scheduleEntry) expectNone() error {
+	if entry.kind == OpAfterFunc {
+		return nil
+	}
+
+	select {
+	case actual := <-entry.c:
+		return fmt.Errorf("unexpected value %s", actual)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,80 @@
+package clocktest_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+)
+
+func newFakeClock() clock.FakeClock {
+	return clock.NewFakeClock()
+}
+
+func TestCheckSchedules(t *testing.T) {
+	clocktest.CheckSchedules(t, newFakeClock, 1, 200, 40)
+}
+
+func FuzzCheckSchedule(f *testing.F) {
+	for seed := int64(0); seed < 10; seed++ {
+		f.Add(seed, uint8(30))
+	}
+
+	f.Fuzz(func(t *testing.T, seed int64, size uint8) {
+		s := clocktest.GenerateSchedule(seed, int(size))
+		if err := clocktest.CheckSchedule(newFakeClock, s); err != nil {
+			t.Fatalf("%s\n%s", err, clocktest.MinimizeSchedule(newFakeClock, s))
+		}
+	})
+}
+
+func TestGenerateSchedule(t *testing.T) {
+	s := clocktest.GenerateSchedule(7, 25)
+	if len(s) != 25 {
+		t.Fatalf("expected 25 ops, got %d", len(s))
+	}
+	if again := clocktest.GenerateSchedule(7, 25); again.String() != s.String() {
+		t.Errorf("expected the same schedule for the same seed, got:\n%s\nand:\n%s", s, again)
+	}
+}
+
+// lateClock fires its timers a second late.
+type lateClock struct {
+	clock.FakeClock
+}
+
+func (c lateClock) NewTimer(d time.Duration) clock.Timer {
+	return c.FakeClock.NewTimer(d + time.Second)
+}
+
+func TestCheckSchedule_LostWakeup(t *testing.T) {
+	newClock := func() clock.FakeClock { return lateClock{clock.NewFakeClock()} }
+
+	s := clocktest.Schedule{
+		{Kind: clocktest.OpNewTicker, D: time.Second},
+		{Kind: clocktest.OpAdvance, D: 2 * time.Second},
+		{Kind: clocktest.OpNewTimer, D: 3 * time.Second},
+		{Kind: clocktest.OpAfterFunc, D: time.Second},
+		{Kind: clocktest.OpAdvance, D: 3 * time.Second},
+	}
+
+	err := clocktest.CheckSchedule(newClock, s)
+	if err == nil || !strings.Contains(err.Error(), "lost wakeup") {
+		t.Fatalf("expected a lost wakeup, got %v", err)
+	}
+
+	minimized := clocktest.MinimizeSchedule(newClock, s)
+	expected := clocktest.Schedule{{Kind: clocktest.OpNewTimer}}
+	if minimized.String() != expected.String() {
+		t.Errorf("expected the schedule minimized to:\n%s\ngot:\n%s", expected, minimized)
+	}
+}
+
+func TestMinimizeSchedule_Passing(t *testing.T) {
+	s := clocktest.GenerateSchedule(3, 10)
+	if minimized := clocktest.MinimizeSchedule(newFakeClock, s); minimized.String() != s.String() {
+		t.Errorf("expected a passing schedule unchanged, got:\n%s", minimized)
+	}
+}
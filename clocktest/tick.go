@@ -0,0 +1,83 @@
+package clocktest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// tickTimeout bounds how long TickAssert waits in real time for the ticker
+// to arm its next tick, and for the tick to be delivered.
+const tickTimeout = 100 * time.Millisecond
+
+// TickAssert advances clock by k intervals, one at a time, and reports an
+// error unless ticker delivers exactly one tick per interval, at the end of
+// it. The ticker must tick every interval from the current time, as one
+// created or reset at the current time, or that just ticked, does.
+// It returns the ticks received, and whether they were all as expected.
+//
+// TickAssert calls C before each tick, as the ticker returned by
+// FakeClock.NewTicker requires, so it works with it as well as with a
+// CustomTicker, whose C always returns the same channel.
+func TickAssert(tb testing.TB, clock clock.FakeClock, ticker clock.Ticker, interval time.Duration, k int) ([]time.Time, bool) {
+	tb.Helper()
+
+	ticks := make([]time.Time, 0, k)
+	var c <-chan time.Time
+	for i := 0; i < k; i++ {
+		c = ticker.C()
+
+		expected := clock.Now().Add(interval)
+		armed(clock, expected)
+		if err := clock.Advance(interval); err != nil {
+			tb.Errorf("advancing the clock: %s", err)
+			return ticks, false
+		}
+
+		at, ok := receiveWithin(c, tickTimeout)
+		if !ok {
+			tb.Errorf("tick %d of %d not delivered at %s; pending sleepers:\n%s", i+1, k, expected, pendingReport(clock))
+			return ticks, false
+		}
+		ticks = append(ticks, at)
+		if !at.Equal(expected) {
+			tb.Errorf("tick %d of %d: expected %s got %s", i+1, k, expected, at)
+			return ticks, false
+		}
+	}
+
+	if at, ok := receive(c); ok {
+		tb.Errorf("extra tick %s after %d ticks", at, k)
+		return ticks, false
+	}
+	return ticks, true
+}
+
+// armed waits for a sleeper due by deadline to be pending on clock, such as
+// the timer a CustomTicker arms from its own goroutine, for at most
+// tickTimeout.
+func armed(clock clock.FakeClock, deadline time.Time) {
+	timeout := time.Now().Add(tickTimeout)
+	for time.Now().Before(timeout) {
+		for _, timer := range clock.PendingTimers() {
+			if !timer.Deadline.After(deadline) {
+				return
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// receiveWithin waits for a value from c for at most timeout.
+func receiveWithin(c <-chan time.Time, timeout time.Duration) (time.Time, bool) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case at := <-c:
+		return at, true
+	case <-timer.C:
+		return time.Time{}, false
+	}
+}
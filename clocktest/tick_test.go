@@ -0,0 +1,56 @@
+package clocktest_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+)
+
+func TestTickAssert(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := fake.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	r := &errorRecorder{recorder{TB: t}}
+	ticks, ok := clocktest.TickAssert(r, fake, ticker, time.Second, 3)
+	if r.failed || !ok {
+		t.Fatalf("unexpected failure: %s", r.message)
+	}
+	for i, at := range ticks {
+		if expected := start.Add(time.Duration(i+1) * time.Second); !at.Equal(expected) {
+			t.Errorf("expected tick %d at %s got %s", i, expected, at)
+		}
+	}
+}
+
+func TestTickAssert_CustomTicker(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	ticker := clock.NewCustomTicker(fake, time.Second)
+	defer ticker.Stop()
+
+	r := &errorRecorder{recorder{TB: t}}
+	if _, ok := clocktest.TickAssert(r, fake, ticker, time.Second, 5); !ok {
+		t.Fatalf("unexpected failure: %s", r.message)
+	}
+}
+
+func TestTickAssert_WrongInterval(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	ticker := fake.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	r := &errorRecorder{recorder{TB: t}}
+	if _, ok := clocktest.TickAssert(r, fake, ticker, time.Second, 2); ok || !r.failed {
+		t.Fatal("expected failure")
+	}
+	if !strings.Contains(r.message, "tick 1 of 2") {
+		t.Errorf("expected the missing tick in the message, got %q", r.message)
+	}
+}
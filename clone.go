@@ -0,0 +1,39 @@
+package clock
+
+import "time"
+
+func (clock *fakeClock) Clone() FakeClock {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	clone := clock.derive()
+	clone.origin = clock.origin
+	clone.seq = clock.seq
+	clone.steps = clock.steps
+	clone.fired = clock.fired
+	clone.stopped = clock.stopped
+
+	clone.sleepers = make([]*sleeper, len(clock.sleepers))
+	for i, s := range clock.sleepers {
+		copied := &sleeper{
+			i:      i,
+			until:  s.until,
+			c:      s.c,
+			f:      s.f,
+			kind:   s.kind,
+			d:      s.d,
+			caller: s.caller,
+			goid:   s.goid,
+			seq:    s.seq,
+			labels: s.labels,
+			steps:  s.steps,
+		}
+		// the channel of a Sleep is recycled once its goroutine wakes, so
+		// the clone mustn't send on it
+		if s.kind == KindSleep {
+			copied.c = make(chan time.Time, 1)
+		}
+		clone.sleepers[i] = copied
+	}
+	return clone
+}
@@ -0,0 +1,78 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestClone(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithName("original"))
+	start := fake.Now()
+
+	retry := make(chan time.Time, 2)
+	fake.AfterFunc(2*time.Second, func() { retry <- start.Add(2 * time.Second) })
+	timeout := fake.After(30 * time.Second)
+	go fake.Sleep(time.Hour)
+	fake.BlockUntil(3)
+
+	clone := fake.Clone()
+	assertClockAt(t, start, clone)
+	if name := clock.Name(clone); name != "original" {
+		t.Errorf("expected the options of the original, got the name %q", name)
+	}
+	if timers := clone.PendingTimers(); len(timers) != 3 || timers[0].Kind != clock.KindAfterFunc || timers[1].Kind != clock.KindAfter {
+		t.Fatalf("expected a copy of the schedule, got %v", timers)
+	}
+
+	// the retry succeeds at t+2s on the clone, the original still waits
+	clone.Advance(2 * time.Second)
+	assertSent(t, start.Add(2*time.Second), retry)
+	assertNotSent(t, timeout)
+	if pending := len(fake.PendingTimers()); pending != 3 {
+		t.Errorf("expected the original schedule untouched, got %d sleepers", pending)
+	}
+
+	// the clone's Sleep wakes nobody, and a new sleeper is the clone's own
+	clone.Advance(time.Hour)
+	<-timeout
+	c := clone.After(time.Second)
+	if pending := len(fake.PendingTimers()); pending != 3 {
+		t.Errorf("expected the original schedule untouched, got %d sleepers", pending)
+	}
+
+	// the original times out at t+30s
+	fake.Advance(30 * time.Second)
+	assertSent(t, start.Add(2*time.Second), retry)
+	assertSent(t, start.Add(30*time.Second), timeout)
+
+	clone.Advance(time.Second)
+	assertSent(t, start.Add(time.Hour+3*time.Second), c)
+	fake.Advance(time.Hour)
+}
+
+func TestClone_SharedCallbacks(t *testing.T) {
+	fake := clock.NewFakeClock()
+	start := fake.Now()
+
+	// a periodic task rescheduling itself on the clock it captured
+	runs := make(chan time.Time, 2)
+	var run func()
+	run = func() {
+		runs <- fake.Now()
+		fake.AfterFunc(time.Second, run)
+	}
+	fake.AfterFunc(time.Second, run)
+
+	clone := fake.Clone()
+	clone.Advance(time.Second)
+
+	// the copy runs the shared callback, which reads and reschedules on
+	// the original clock
+	assertSent(t, start, runs)
+	assertClockUntil(t, 2, fake)
+	if pending := len(clone.PendingTimers()); pending != 0 {
+		t.Errorf("expected nothing rescheduled on the clone, got %d sleepers", pending)
+	}
+}
@@ -0,0 +1,148 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Coalescer collapses bursts of notifications into a single delivery,
+// measured by a Clock.
+//
+// A delivery happens once no notification has arrived for the quiet period,
+// or once the first notification of the burst is maxDelay old, whichever
+// comes first, so a steady stream of notifications is still delivered.
+//
+// It is safe for concurrent use.
+type Coalescer struct {
+	clock    Clock
+	quiet    time.Duration
+	maxDelay time.Duration
+	f        func(n int)
+
+	deliver sync.Mutex
+
+	mutex    sync.Mutex
+	timer    Timer
+	first    time.Time
+	deadline time.Time
+	n        int
+	stopped  bool
+}
+
+// NewCoalescer returns a Coalescer that calls f with the number of
+// notifications in each burst. Deliveries are serialized.
+// A maxDelay <= 0 means bursts have no limit.
+func NewCoalescer(clock Clock, quiet, maxDelay time.Duration, f func(n int)) *Coalescer {
+	return &Coalescer{
+		clock:    clock,
+		quiet:    quiet,
+		maxDelay: maxDelay,
+		f:        f,
+	}
+}
+
+// Notify records a notification, delaying the delivery of the current burst
+// by the quiet period, up to its max delay.
+func (coalescer *Coalescer) Notify() {
+	coalescer.mutex.Lock()
+	defer coalescer.mutex.Unlock()
+
+	if coalescer.stopped {
+		return
+	}
+
+	now := coalescer.clock.Now()
+	if coalescer.n == 0 {
+		coalescer.first = now
+	}
+	coalescer.n++
+
+	deadline := now.Add(coalescer.quiet)
+	if coalescer.maxDelay > 0 {
+		if max := coalescer.first.Add(coalescer.maxDelay); max.Before(deadline) {
+			deadline = max
+		}
+	}
+	coalescer.schedule(deadline)
+}
+
+// Pending returns the number of notifications waiting to be delivered.
+func (coalescer *Coalescer) Pending() int {
+	coalescer.mutex.Lock()
+	defer coalescer.mutex.Unlock()
+
+	return coalescer.n
+}
+
+// Flush delivers the pending notifications right away, if any,
+// and waits for the delivery to return.
+func (coalescer *Coalescer) Flush() {
+	coalescer.deliver.Lock()
+	defer coalescer.deliver.Unlock()
+
+	coalescer.mutex.Lock()
+	n := coalescer.take()
+	coalescer.mutex.Unlock()
+
+	if n > 0 {
+		coalescer.f(n)
+	}
+}
+
+// Stop discards pending notifications and ignores later ones.
+// It returns the number of notifications discarded.
+func (coalescer *Coalescer) Stop() int {
+	coalescer.mutex.Lock()
+	defer coalescer.mutex.Unlock()
+
+	coalescer.stopped = true
+	return coalescer.take()
+}
+
+func (coalescer *Coalescer) schedule(deadline time.Time) {
+	if deadline.Equal(coalescer.deadline) {
+		return
+	}
+	coalescer.deadline = deadline
+
+	d := deadline.Sub(coalescer.clock.Now())
+	if coalescer.timer == nil {
+		coalescer.timer = coalescer.clock.AfterFunc(d, coalescer.fire)
+		return
+	}
+	coalescer.timer.Reset(d)
+}
+
+// take resets the burst, returning its number of notifications.
+func (coalescer *Coalescer) take() int {
+	n := coalescer.n
+	coalescer.n = 0
+	coalescer.deadline = time.Time{}
+	if coalescer.timer != nil {
+		coalescer.timer.Stop()
+	}
+	return n
+}
+
+func (coalescer *Coalescer) fire() {
+	coalescer.deliver.Lock()
+	defer coalescer.deliver.Unlock()
+
+	coalescer.mutex.Lock()
+	if coalescer.n == 0 {
+		coalescer.mutex.Unlock()
+		return
+	}
+
+	// the timer may fire just as a notification postpones it
+	if d := coalescer.deadline.Sub(coalescer.clock.Now()); d > 0 {
+		coalescer.timer.Reset(d)
+		coalescer.mutex.Unlock()
+		return
+	}
+
+	n := coalescer.take()
+	coalescer.mutex.Unlock()
+
+	coalescer.f(n)
+}
@@ -0,0 +1,102 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func assertDelivered(t *testing.T, expected int, delivered <-chan int) {
+	t.Helper()
+
+	select {
+	case n := <-delivered:
+		if n != expected {
+			t.Errorf("expected %d notifications got %d", expected, n)
+		}
+	case <-time.After(sentTimeout):
+		t.Fatal("timeout: nothing delivered")
+	}
+}
+
+func assertNotDelivered(t *testing.T, delivered <-chan int) {
+	t.Helper()
+
+	select {
+	case n := <-delivered:
+		t.Errorf("expected no delivery got %d notifications", n)
+	case <-time.After(notSentTimeout):
+	}
+}
+
+func TestCoalescer_Quiet(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	delivered := make(chan int, 1)
+	coalescer := clock.NewCoalescer(fake, 1*time.Second, 0, func(n int) { delivered <- n })
+
+	coalescer.Notify()
+	fake.Advance(500 * time.Millisecond)
+	coalescer.Notify()
+	coalescer.Notify()
+
+	fake.Advance(999 * time.Millisecond)
+	assertNotDelivered(t, delivered)
+
+	fake.Advance(1 * time.Millisecond)
+	assertDelivered(t, 3, delivered)
+
+	if pending := coalescer.Pending(); pending != 0 {
+		t.Errorf("expected %d pending got %d", 0, pending)
+	}
+}
+
+func TestCoalescer_MaxDelay(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	delivered := make(chan int, 1)
+	coalescer := clock.NewCoalescer(fake, 1*time.Second, 3*time.Second, func(n int) { delivered <- n })
+
+	// a steady stream never goes quiet, but is delivered after the max delay
+	for i := 0; i < 6; i++ {
+		coalescer.Notify()
+		fake.Advance(500 * time.Millisecond)
+	}
+	assertDelivered(t, 6, delivered)
+
+	coalescer.Notify()
+	fake.Advance(1 * time.Second)
+	assertDelivered(t, 1, delivered)
+}
+
+func TestCoalescer_Flush(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	delivered := make(chan int, 1)
+	coalescer := clock.NewCoalescer(fake, 1*time.Second, 0, func(n int) { delivered <- n })
+
+	coalescer.Notify()
+	coalescer.Notify()
+	coalescer.Flush()
+	assertDelivered(t, 2, delivered)
+
+	fake.Advance(1 * time.Second)
+	assertNotDelivered(t, delivered)
+}
+
+func TestCoalescer_Stop(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	delivered := make(chan int, 1)
+	coalescer := clock.NewCoalescer(fake, 1*time.Second, 0, func(n int) { delivered <- n })
+
+	coalescer.Notify()
+	if n := coalescer.Stop(); n != 1 {
+		t.Errorf("expected %d discarded got %d", 1, n)
+	}
+
+	coalescer.Notify()
+	fake.Advance(1 * time.Second)
+	assertNotDelivered(t, delivered)
+}
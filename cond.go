@@ -0,0 +1,114 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Cond is a condition variable like sync.Cond, whose waits can be bounded by
+// a timeout measured by a clock.
+//
+// A Cond must not be copied after first use.
+type Cond struct {
+	// L is held while observing or changing the condition.
+	L sync.Locker
+
+	clock   Clock
+	mutex   sync.Mutex
+	waiters []chan struct{}
+}
+
+// NewCond returns a Cond with Locker l, whose timeouts are measured by clock.
+func NewCond(clock Clock, l sync.Locker) *Cond {
+	return &Cond{
+		L:     l,
+		clock: clock,
+	}
+}
+
+// Wait atomically unlocks c.L and suspends the calling goroutine until woken
+// by Signal or Broadcast, then locks c.L again before returning.
+func (c *Cond) Wait() {
+	done := c.add()
+	c.L.Unlock()
+	<-done
+	c.L.Lock()
+}
+
+// WaitTimeout is like Wait, but gives up once d has elapsed.
+// It reports whether the goroutine was woken by Signal or Broadcast.
+func (c *Cond) WaitTimeout(d time.Duration) bool {
+	done := c.add()
+	c.L.Unlock()
+	defer c.L.Lock()
+
+	if d <= 0 {
+		return !c.remove(done)
+	}
+
+	timer := c.clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C():
+		// a wakeup that raced the timeout still counts,
+		// so that a Signal is never lost
+		return !c.remove(done)
+	}
+}
+
+// WaitUntil is like Wait, but gives up once the clock reaches t.
+// It reports whether the goroutine was woken by Signal or Broadcast.
+func (c *Cond) WaitUntil(t time.Time) bool {
+	return c.WaitTimeout(t.Sub(c.clock.Now()))
+}
+
+// Signal wakes the goroutine that has been waiting the longest, if any.
+func (c *Cond) Signal() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.waiters) == 0 {
+		return
+	}
+
+	close(c.waiters[0])
+	c.waiters[0] = nil
+	c.waiters = c.waiters[1:]
+}
+
+// Broadcast wakes all goroutines waiting on c.
+func (c *Cond) Broadcast() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, done := range c.waiters {
+		close(done)
+	}
+	c.waiters = nil
+}
+
+func (c *Cond) add() chan struct{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	done := make(chan struct{})
+	c.waiters = append(c.waiters, done)
+	return done
+}
+
+// remove removes a waiter, reporting whether it was still waiting.
+func (c *Cond) remove(done chan struct{}) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for i, waiter := range c.waiters {
+		if waiter == done {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
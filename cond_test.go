@@ -0,0 +1,114 @@
+package clock_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestCond_Signal(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	var mutex sync.Mutex
+	cond := clock.NewCond(fake, &mutex)
+
+	woken := make(chan bool, 1)
+	mutex.Lock()
+	go func() {
+		mutex.Lock()
+		woken <- cond.WaitTimeout(1 * time.Second)
+		mutex.Unlock()
+	}()
+	mutex.Unlock()
+
+	assertClockUntil(t, 1, fake)
+
+	mutex.Lock()
+	cond.Signal()
+	mutex.Unlock()
+
+	select {
+	case ok := <-woken:
+		if !ok {
+			t.Error("expected WaitTimeout to be signaled")
+		}
+	case <-time.After(sentTimeout):
+		t.Fatal("timeout: WaitTimeout did not return")
+	}
+}
+
+func TestCond_Timeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	var mutex sync.Mutex
+	cond := clock.NewCond(fake, &mutex)
+
+	woken := make(chan bool, 1)
+	go func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		woken <- cond.WaitUntil(fake.Now().Add(1 * time.Second))
+	}()
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+
+	select {
+	case ok := <-woken:
+		if ok {
+			t.Error("expected WaitUntil to time out")
+		}
+	case <-time.After(sentTimeout):
+		t.Fatal("timeout: WaitUntil did not return")
+	}
+
+	// a timed out waiter must not swallow a later signal
+	mutex.Lock()
+	cond.Signal()
+	if cond.WaitTimeout(0) {
+		t.Error("expected WaitTimeout(0) to time out")
+	}
+	mutex.Unlock()
+}
+
+func TestCond_Broadcast(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	var mutex sync.Mutex
+	cond := clock.NewCond(fake, &mutex)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mutex.Lock()
+			defer mutex.Unlock()
+			cond.Wait()
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// waiters may not have called Wait yet, so keep broadcasting
+	timeout := time.After(closedTimeout)
+	for {
+		mutex.Lock()
+		cond.Broadcast()
+		mutex.Unlock()
+
+		select {
+		case <-done:
+			return
+		case <-timeout:
+			t.Fatal("timeout: Broadcast did not wake all waiters")
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithTimeout returns WithDeadline(parent, clock, clock.Now().Add(d)).
+func WithTimeout(parent context.Context, clock Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	return WithDeadline(parent, clock, clock.Now().Add(d))
+}
+
+// WithDeadline returns a copy of the parent context that is canceled
+// once clock reaches deadline, when the returned cancel function is called,
+// or when the parent's Done channel is closed, whichever happens first.
+// Once the deadline passes, the context's Err method returns context.DeadlineExceeded.
+//
+// Unlike context.WithDeadline, the deadline is measured by clock,
+// so it can be driven by a fake clock in tests.
+func WithDeadline(parent context.Context, clock Clock, deadline time.Time) (context.Context, context.CancelFunc) {
+	ctx := &deadlineCtx{
+		Context:  parent,
+		deadline: deadline,
+		done:     make(chan struct{}),
+	}
+
+	if err := parent.Err(); err != nil {
+		ctx.cancel(err)
+		return ctx, func() {}
+	}
+	if done := parent.Done(); done != nil {
+		go func() {
+			select {
+			case <-done:
+				ctx.cancel(parent.Err())
+			case <-ctx.done:
+			}
+		}()
+	}
+
+	d := deadline.Sub(clock.Now())
+	if d <= 0 {
+		ctx.cancel(context.DeadlineExceeded)
+		return ctx, func() {}
+	}
+
+	timer := clock.AfterFunc(d, func() { ctx.cancel(context.DeadlineExceeded) })
+
+	return ctx, func() {
+		timer.Stop()
+		ctx.cancel(context.Canceled)
+	}
+}
+
+// deadlineCtx has its own Done channel and error, rather than embedding a
+// context.WithCancel of the parent: the contexts derived from it would
+// attach to that inner context, and report its context.Canceled instead
+// of context.DeadlineExceeded. The parent is embedded for its values.
+type deadlineCtx struct {
+	context.Context
+	deadline time.Time
+	done     chan struct{}
+
+	mutex sync.Mutex
+	err   error
+}
+
+// cancel sets the error of the context and closes Done, unless it's
+// already canceled. Both happen under the mutex, so Err never reports an
+// error before Done is closed.
+func (ctx *deadlineCtx) cancel(err error) {
+	ctx.mutex.Lock()
+	defer ctx.mutex.Unlock()
+
+	if ctx.err != nil {
+		return
+	}
+	ctx.err = err
+	close(ctx.done)
+}
+
+// Deadline returns the earlier of the deadline and the parent's.
+func (ctx *deadlineCtx) Deadline() (time.Time, bool) {
+	if parent, ok := ctx.Context.Deadline(); ok && parent.Before(ctx.deadline) {
+		return parent, true
+	}
+	return ctx.deadline, true
+}
+
+func (ctx *deadlineCtx) Done() <-chan struct{} {
+	return ctx.done
+}
+
+func (ctx *deadlineCtx) Err() error {
+	ctx.mutex.Lock()
+	defer ctx.mutex.Unlock()
+
+	return ctx.err
+}
@@ -0,0 +1,123 @@
+package clock_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+)
+
+func TestWithTimeout(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ctx, cancel := clock.WithTimeout(context.Background(), fake, 2*time.Second)
+	defer cancel()
+
+	if deadline, ok := ctx.Deadline(); !ok || deadline != start.Add(2*time.Second) {
+		t.Errorf("expected deadline %s got %s", start.Add(2*time.Second), deadline)
+	}
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+	assertNotClosed(t, ctx.Done())
+
+	fake.Advance(1 * time.Second)
+	clocktest.RequireClosedWithin(t, ctx.Done(), closedTimeout)
+	if err := ctx.Err(); err != context.DeadlineExceeded {
+		t.Errorf("expected %v got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestWithTimeout_Cancel(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	ctx, cancel := clock.WithTimeout(context.Background(), fake, 1*time.Second)
+	cancel()
+
+	clocktest.RequireClosedWithin(t, ctx.Done(), closedTimeout)
+	if err := ctx.Err(); err != context.Canceled {
+		t.Errorf("expected %v got %v", context.Canceled, err)
+	}
+}
+
+func TestWithTimeout_ParentCanceled(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel := clock.WithTimeout(parent, fake, 1*time.Second)
+	defer cancel()
+
+	cancelParent()
+
+	clocktest.RequireClosedWithin(t, ctx.Done(), closedTimeout)
+	if err := ctx.Err(); err != context.Canceled {
+		t.Errorf("expected %v got %v", context.Canceled, err)
+	}
+}
+
+func TestWithDeadline_Past(t *testing.T) {
+	start := time.Unix(2, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ctx, cancel := clock.WithDeadline(context.Background(), fake, start.Add(-1*time.Second))
+	defer cancel()
+
+	clocktest.RequireClosedWithin(t, ctx.Done(), closedTimeout)
+	if err := ctx.Err(); err != context.DeadlineExceeded {
+		t.Errorf("expected %v got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestWithDeadline_ParentEarlier(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	parent, cancelParent := clock.WithTimeout(context.Background(), fake, 1*time.Second)
+	defer cancelParent()
+	ctx, cancel := clock.WithTimeout(parent, fake, 2*time.Second)
+	defer cancel()
+
+	if deadline, ok := ctx.Deadline(); !ok || deadline != start.Add(1*time.Second) {
+		t.Errorf("expected deadline %s got %s", start.Add(1*time.Second), deadline)
+	}
+}
+
+func TestWithTimeout_ErrAfterDone(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		fake := clock.NewFakeClock()
+		ctx, cancel := clock.WithTimeout(context.Background(), fake, 1*time.Second)
+
+		go fake.Advance(1 * time.Second)
+		for ctx.Err() == nil {
+			runtime.Gosched()
+		}
+		select {
+		case <-ctx.Done():
+		default:
+			t.Fatal("Err is not nil before Done is closed")
+		}
+		cancel()
+	}
+}
+
+func TestWithTimeout_Derived(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	ctx, cancel := clock.WithTimeout(context.Background(), fake, 1*time.Second)
+	defer cancel()
+	child, cancelChild := context.WithCancel(ctx)
+	defer cancelChild()
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+
+	// contexts derived from it report the deadline too
+	clocktest.RequireClosedWithin(t, child.Done(), closedTimeout)
+	if err := child.Err(); err != context.DeadlineExceeded {
+		t.Errorf("expected %v got %v", context.DeadlineExceeded, err)
+	}
+}
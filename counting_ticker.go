@@ -0,0 +1,117 @@
+package clock
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CountingTicker wraps a Ticker to count the ticks its consumer missed.
+//
+// Ticks are forwarded on a channel that holds a single tick, like the channel
+// of a time.Ticker. A tick that arrives while the previous one is still
+// waiting to be received is dropped and counted. Ticks dropped by the wrapped
+// ticker itself, such as a time.Ticker whose own consumer fell behind, are
+// inferred from the gaps between the times of the ticks received.
+type CountingTicker struct {
+	ticker  Ticker
+	c       chan time.Time
+	skipped int64
+	period  int64
+
+	reset    chan struct{}
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// CountSkips returns a CountingTicker forwarding the ticks of ticker,
+// which ticks every d.
+func CountSkips(ticker Ticker, d time.Duration) *CountingTicker {
+	t := &CountingTicker{
+		ticker: ticker,
+		c:      make(chan time.Time, 1),
+		period: int64(d),
+		reset:  make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	go t.run()
+	return t
+}
+
+// C returns the channel on which the ticks are delivered.
+func (t *CountingTicker) C() <-chan time.Time {
+	return t.c
+}
+
+// Skipped returns the number of ticks the consumer missed.
+func (t *CountingTicker) Skipped() int64 {
+	return atomic.LoadInt64(&t.skipped)
+}
+
+// Stop turns off the wrapped ticker. After Stop, no more ticks will be sent.
+func (t *CountingTicker) Stop() {
+	t.stopOnce.Do(func() {
+		t.ticker.Stop()
+		close(t.stop)
+	})
+	<-t.done
+}
+
+// Reset stops the wrapped ticker and resets its period to d.
+func (t *CountingTicker) Reset(d time.Duration) {
+	atomic.StoreInt64(&t.period, int64(d))
+	t.ticker.Reset(d)
+
+	// the gap to the first tick after a reset isn't a period
+	select {
+	case t.reset <- struct{}{}:
+	default:
+	}
+}
+
+func (t *CountingTicker) run() {
+	defer close(t.done)
+
+	// the channel of a fake ticker delivers a single tick, so C is called
+	// again once it has, but not before: Reset rearms the channel last
+	// returned, and calling C again would arm a second one
+	c := t.ticker.C()
+
+	var last time.Time
+	for {
+		select {
+		case at := <-c:
+			c = t.ticker.C()
+			if !last.IsZero() {
+				t.infer(at.Sub(last))
+			}
+			last = at
+
+			select {
+			case t.c <- at:
+			default:
+				atomic.AddInt64(&t.skipped, 1)
+			}
+		case <-t.reset:
+			last = time.Time{}
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+// infer counts the ticks missing from a gap between two ticks,
+// rounding to absorb the latency of real ticks.
+func (t *CountingTicker) infer(gap time.Duration) {
+	period := time.Duration(atomic.LoadInt64(&t.period))
+	if period <= 0 {
+		return
+	}
+
+	if missed := int64((gap+period/2)/period) - 1; missed > 0 {
+		atomic.AddInt64(&t.skipped, missed)
+	}
+}
@@ -0,0 +1,102 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestCountSkips_SlowConsumer(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := clock.CountSkips(fake.NewTicker(1*time.Second), 1*time.Second)
+	defer ticker.Stop()
+
+	for i := 0; i < 3; i++ {
+		assertClockUntil(t, 1, fake)
+		fake.Advance(1 * time.Second)
+	}
+	assertClockUntil(t, 1, fake)
+
+	assertSent(t, start.Add(1*time.Second), ticker.C())
+	if skipped := ticker.Skipped(); skipped != 2 {
+		t.Errorf("expected %d skipped got %d", 2, skipped)
+	}
+
+	fake.Advance(1 * time.Second)
+	assertSent(t, start.Add(4*time.Second), ticker.C())
+	if skipped := ticker.Skipped(); skipped != 2 {
+		t.Errorf("expected %d skipped got %d", 2, skipped)
+	}
+}
+
+// stubTicker delivers the ticks sent on its channel.
+type stubTicker struct {
+	c chan time.Time
+}
+
+func (ticker stubTicker) C() <-chan time.Time { return ticker.c }
+func (ticker stubTicker) Stop()               {}
+func (ticker stubTicker) Reset(time.Duration) {}
+
+func TestCountSkips_Gaps(t *testing.T) {
+	start := time.Unix(1, 0)
+	inner := stubTicker{c: make(chan time.Time)}
+
+	ticker := clock.CountSkips(inner, 1*time.Second)
+	defer ticker.Stop()
+
+	// the wrapped ticker dropped the ticks at 2s and 3s,
+	// and the tick at 5s is a little late
+	for _, d := range []time.Duration{1 * time.Second, 4 * time.Second, 5*time.Second + 100*time.Millisecond} {
+		at := start.Add(d)
+		inner.c <- at
+		assertSent(t, at, ticker.C())
+	}
+
+	if skipped := ticker.Skipped(); skipped != 2 {
+		t.Errorf("expected %d skipped got %d", 2, skipped)
+	}
+}
+
+func TestCountSkips_Stop(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	ticker := clock.CountSkips(fake.NewTicker(1*time.Second), 1*time.Second)
+	assertClockUntil(t, 1, fake)
+
+	ticker.Stop()
+	ticker.Stop()
+
+	fake.Advance(1 * time.Second)
+	assertNotSent(t, ticker.C())
+}
+
+func TestCountSkips_Reset(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := clock.CountSkips(fake.NewTicker(1*time.Second), 1*time.Second)
+	defer ticker.Stop()
+
+	assertClockUntil(t, 1, fake)
+	ticker.Reset(2 * time.Second)
+
+	// the reset rearms the channel being read, rather than arming another
+	assertClockUntil(t, 1, fake)
+	if n := len(fake.PendingTimers()); n != 1 {
+		t.Fatalf("expected %d pending timer got %d", 1, n)
+	}
+
+	fake.Advance(2 * time.Second)
+	assertSent(t, start.Add(2*time.Second), ticker.C())
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(2 * time.Second)
+	assertSent(t, start.Add(4*time.Second), ticker.C())
+	if skipped := ticker.Skipped(); skipped != 0 {
+		t.Errorf("expected %d skipped got %d", 0, skipped)
+	}
+}
@@ -0,0 +1,59 @@
+package clock
+
+import "time"
+
+// A Reader reads the time, without timers: the part of Clock that code
+// measuring durations needs. Every Clock is a Reader.
+type Reader interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+}
+
+// NewProcessCPUClock returns a Reader measuring the CPU time consumed by the
+// process, in user and system mode, across all its threads. Its times are
+// the Unix epoch plus the CPU time consumed so far, and are only meaningful
+// relative to each other:
+//
+//	cpu := clock.NewProcessCPUClock()
+//	start := cpu.Now()
+//	work()
+//	log.Printf("work took %s of CPU", cpu.Since(start))
+//
+// It reads CLOCK_PROCESS_CPUTIME_ID on Linux, getrusage on macOS and the
+// BSDs, and GetProcessTimes on Windows, whose resolution may be coarse.
+// Elsewhere, it falls back to the real clock, measuring wall time.
+func NewProcessCPUClock() Reader {
+	if read := processCPUSource(); read != nil {
+		return cpuClock{read: read}
+	}
+	return realClock{}
+}
+
+// NewThreadCPUClock returns a Reader measuring the CPU time consumed by the
+// calling OS thread, as NewProcessCPUClock does for the process. As the Go
+// scheduler moves goroutines between threads, the goroutine reading it
+// should be locked to its thread with runtime.LockOSThread.
+//
+// It reads CLOCK_THREAD_CPUTIME_ID on Linux. Elsewhere, it falls back to
+// NewProcessCPUClock.
+func NewThreadCPUClock() Reader {
+	if read := threadCPUSource(); read != nil {
+		return cpuClock{read: read}
+	}
+	return NewProcessCPUClock()
+}
+
+type cpuClock struct {
+	read func() time.Duration
+}
+
+func (clock cpuClock) Now() time.Time {
+	return time.Unix(0, 0).Add(clock.read())
+}
+
+func (clock cpuClock) Since(t time.Time) time.Duration {
+	return clock.Now().Sub(t)
+}
@@ -0,0 +1,22 @@
+//go:build (darwin || dragonfly || freebsd || netbsd || openbsd) && !tinygo
+
+package clock
+
+import (
+	"syscall"
+	"time"
+)
+
+func processCPUSource() func() time.Duration {
+	return func() time.Duration {
+		var usage syscall.Rusage
+		if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+			return 0
+		}
+		return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+	}
+}
+
+func threadCPUSource() func() time.Duration {
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build (!linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !windows) || tinygo
+
+package clock
+
+import "time"
+
+func processCPUSource() func() time.Duration {
+	return nil
+}
+
+func threadCPUSource() func() time.Duration {
+	return nil
+}
@@ -0,0 +1,46 @@
+package clock_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+var _ clock.Reader = clock.NewRealClock()
+
+// spin keeps the CPU busy for d of wall time.
+func spin(d time.Duration) int {
+	n := 0
+	for start := time.Now(); time.Since(start) < d; {
+		n++
+	}
+	return n
+}
+
+func TestNewProcessCPUClock(t *testing.T) {
+	cpu := clock.NewProcessCPUClock()
+
+	start := cpu.Now()
+	spin(50 * time.Millisecond)
+	if d := cpu.Since(start); d < 10*time.Millisecond {
+		t.Errorf("expected spinning for 50ms to use at least 10ms of CPU, got %s", d)
+	}
+	if now := cpu.Now(); now.Before(start) {
+		t.Errorf("expected CPU time to never go back, got %s after %s", now, start)
+	}
+}
+
+func TestNewThreadCPUClock(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	cpu := clock.NewThreadCPUClock()
+
+	start := cpu.Now()
+	spin(50 * time.Millisecond)
+	if d := cpu.Since(start); d < 10*time.Millisecond {
+		t.Errorf("expected spinning for 50ms to use at least 10ms of CPU, got %s", d)
+	}
+}
@@ -0,0 +1,32 @@
+//go:build windows && !tinygo
+
+package clock
+
+import (
+	"syscall"
+	"time"
+)
+
+func processCPUSource() func() time.Duration {
+	process, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return nil
+	}
+
+	return func() time.Duration {
+		var creation, exit, kernel, user syscall.Filetime
+		if err := syscall.GetProcessTimes(process, &creation, &exit, &kernel, &user); err != nil {
+			return 0
+		}
+		return filetimeDuration(kernel) + filetimeDuration(user)
+	}
+}
+
+func threadCPUSource() func() time.Duration {
+	return nil
+}
+
+// filetimeDuration converts a FILETIME holding a duration, in 100ns units.
+func filetimeDuration(ft syscall.Filetime) time.Duration {
+	return time.Duration(int64(ft.HighDateTime)<<32|int64(ft.LowDateTime)) * 100
+}
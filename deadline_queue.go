@@ -0,0 +1,249 @@
+package clock
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// DeadlineQueue is an indexed priority queue of keys ordered by deadline,
+// measured by a Clock.
+//
+// Its channel signals when the earliest deadline is reached,
+// so a single goroutine can manage any number of deadlines:
+//
+//	for range queue.C() {
+//		for _, key := range queue.PopDue() {
+//			...
+//		}
+//	}
+//
+// It is safe for concurrent use.
+type DeadlineQueue[K comparable] struct {
+	clock Clock
+	c     chan struct{}
+
+	mutex   sync.Mutex
+	items   map[K]*deadlineItem[K]
+	heap    deadlineHeap[K]
+	timer   Timer
+	armed   time.Time
+	stopped bool
+}
+
+type deadlineItem[K comparable] struct {
+	key      K
+	deadline time.Time
+	seq      uint64
+	index    int
+}
+
+// NewDeadlineQueue returns an empty DeadlineQueue measuring deadlines by clock.
+func NewDeadlineQueue[K comparable](clock Clock) *DeadlineQueue[K] {
+	return &DeadlineQueue[K]{
+		clock: clock,
+		c:     make(chan struct{}, 1),
+		items: map[K]*deadlineItem[K]{},
+	}
+}
+
+// C returns the channel signaled when the earliest deadline is reached.
+// Signals are coalesced: a single signal may stand for several due keys.
+func (queue *DeadlineQueue[K]) C() <-chan struct{} {
+	return queue.c
+}
+
+// Add sets the deadline of key, adding it to the queue if needed.
+// Keys with equal deadlines are due in the order they were added.
+func (queue *DeadlineQueue[K]) Add(key K, deadline time.Time) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if item, ok := queue.items[key]; ok {
+		item.deadline = deadline
+		heap.Fix(&queue.heap, item.index)
+	} else {
+		item := &deadlineItem[K]{
+			key:      key,
+			deadline: deadline,
+			seq:      queue.heap.seq,
+		}
+		queue.heap.seq++
+		queue.items[key] = item
+		heap.Push(&queue.heap, item)
+	}
+
+	queue.schedule()
+}
+
+// Remove removes key from the queue.
+// It returns false if the key wasn't in the queue.
+func (queue *DeadlineQueue[K]) Remove(key K) bool {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	item, ok := queue.items[key]
+	if !ok {
+		return false
+	}
+
+	delete(queue.items, key)
+	heap.Remove(&queue.heap, item.index)
+	queue.schedule()
+	return true
+}
+
+// Deadline returns the deadline of key.
+func (queue *DeadlineQueue[K]) Deadline(key K) (time.Time, bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	item, ok := queue.items[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	return item.deadline, true
+}
+
+// Peek returns the key with the earliest deadline, without removing it.
+func (queue *DeadlineQueue[K]) Peek() (K, time.Time, bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if len(queue.heap.items) == 0 {
+		var zero K
+		return zero, time.Time{}, false
+	}
+	item := queue.heap.items[0]
+	return item.key, item.deadline, true
+}
+
+// PopDue removes and returns the keys whose deadline has been reached,
+// ordered by deadline.
+func (queue *DeadlineQueue[K]) PopDue() []K {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	now := queue.clock.Now()
+
+	var due []K
+	for len(queue.heap.items) > 0 && !queue.heap.items[0].deadline.After(now) {
+		item := heap.Pop(&queue.heap).(*deadlineItem[K])
+		delete(queue.items, item.key)
+		due = append(due, item.key)
+	}
+
+	queue.schedule()
+	return due
+}
+
+// popDue removes and returns the key with the earliest deadline,
+// if it has been reached.
+func (queue *DeadlineQueue[K]) popDue() (K, bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if len(queue.heap.items) == 0 || queue.heap.items[0].deadline.After(queue.clock.Now()) {
+		var zero K
+		return zero, false
+	}
+
+	item := heap.Pop(&queue.heap).(*deadlineItem[K])
+	delete(queue.items, item.key)
+	queue.schedule()
+	return item.key, true
+}
+
+// Len returns the number of keys in the queue.
+func (queue *DeadlineQueue[K]) Len() int {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	return len(queue.heap.items)
+}
+
+// Stop releases the queue's timer. The channel is no longer signaled.
+func (queue *DeadlineQueue[K]) Stop() {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	queue.stopped = true
+	if queue.timer != nil {
+		queue.timer.Stop()
+	}
+}
+
+// schedule arms the timer for the earliest deadline.
+func (queue *DeadlineQueue[K]) schedule() {
+	if queue.stopped {
+		return
+	}
+
+	if len(queue.heap.items) == 0 {
+		if queue.timer != nil {
+			queue.timer.Stop()
+		}
+		queue.armed = time.Time{}
+		return
+	}
+
+	deadline := queue.heap.items[0].deadline
+	if deadline.Equal(queue.armed) {
+		return
+	}
+	queue.armed = deadline
+
+	d := deadline.Sub(queue.clock.Now())
+	if queue.timer == nil {
+		queue.timer = queue.clock.AfterFunc(d, queue.signal)
+		return
+	}
+	queue.timer.Reset(d)
+}
+
+func (queue *DeadlineQueue[K]) signal() {
+	queue.mutex.Lock()
+	queue.armed = time.Time{}
+	queue.mutex.Unlock()
+
+	select {
+	case queue.c <- struct{}{}:
+	default:
+	}
+}
+
+// deadlineHeap is a min-heap of items ordered by deadline, then insertion order.
+type deadlineHeap[K comparable] struct {
+	items []*deadlineItem[K]
+	seq   uint64
+}
+
+func (h *deadlineHeap[K]) Len() int { return len(h.items) }
+
+func (h *deadlineHeap[K]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if !a.deadline.Equal(b.deadline) {
+		return a.deadline.Before(b.deadline)
+	}
+	return a.seq < b.seq
+}
+
+func (h *deadlineHeap[K]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *deadlineHeap[K]) Push(x interface{}) {
+	item := x.(*deadlineItem[K])
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *deadlineHeap[K]) Pop() interface{} {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items[n-1] = nil
+	h.items = h.items[:n-1]
+	return item
+}
@@ -0,0 +1,104 @@
+package clock_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+)
+
+func TestDeadlineQueue(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	queue := clock.NewDeadlineQueue[string](fake)
+	defer queue.Stop()
+
+	queue.Add("b", start.Add(2*time.Second))
+	queue.Add("a", start.Add(1*time.Second))
+	queue.Add("c", start.Add(2*time.Second))
+
+	if key, deadline, ok := queue.Peek(); !ok || key != "a" || deadline != start.Add(1*time.Second) {
+		t.Errorf("expected a at %s got %s at %s", start.Add(1*time.Second), key, deadline)
+	}
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+	assertSignaled(t, queue.C())
+	assertDue(t, []string{"a"}, queue)
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+	assertSignaled(t, queue.C())
+	assertDue(t, []string{"b", "c"}, queue)
+
+	if n := queue.Len(); n != 0 {
+		t.Errorf("expected %d keys got %d", 0, n)
+	}
+}
+
+func TestDeadlineQueue_Update(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	queue := clock.NewDeadlineQueue[string](fake)
+	defer queue.Stop()
+
+	queue.Add("a", start.Add(1*time.Second))
+	queue.Add("b", start.Add(2*time.Second))
+	queue.Add("a", start.Add(3*time.Second))
+
+	if deadline, ok := queue.Deadline("a"); !ok || deadline != start.Add(3*time.Second) {
+		t.Errorf("expected deadline %s got %s", start.Add(3*time.Second), deadline)
+	}
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+	clocktest.RequireNotClosedFor(t, queue.C(), notSentTimeout)
+
+	fake.Advance(1 * time.Second)
+	assertSignaled(t, queue.C())
+	assertDue(t, []string{"b"}, queue)
+}
+
+func TestDeadlineQueue_Remove(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	queue := clock.NewDeadlineQueue[int](fake)
+	defer queue.Stop()
+
+	queue.Add(1, start.Add(1*time.Second))
+	if !queue.Remove(1) {
+		t.Error("expected remove to return true")
+	}
+	if queue.Remove(1) {
+		t.Error("expected remove to return false")
+	}
+
+	fake.Advance(1 * time.Second)
+	clocktest.RequireNotClosedFor(t, queue.C(), notSentTimeout)
+	if n := len(fake.PendingTimers()); n != 0 {
+		t.Errorf("expected %d pending timers got %d", 0, n)
+	}
+}
+
+func assertSignaled(t *testing.T, c <-chan struct{}) {
+	t.Helper()
+
+	select {
+	case <-c:
+	case <-time.After(sentTimeout):
+		t.Fatalf("timeout: after %s", sentTimeout)
+	}
+}
+
+func assertDue[K comparable](t *testing.T, expected []K, queue *clock.DeadlineQueue[K]) {
+	t.Helper()
+
+	if actual := queue.PopDue(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected due %v got %v", expected, actual)
+	}
+}
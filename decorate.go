@@ -0,0 +1,336 @@
+package clock
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	errNonPositiveFactor  = errors.New("clock: non-positive factor for Scale")
+	errNonPositiveQuantum = errors.New("clock: non-positive quantum for Quantize")
+	errNegativeJitter     = errors.New("clock: negative maximum for Jitter")
+)
+
+// Offset returns a Clock reading d ahead of clock, or behind it if d is
+// negative. Timers and tickers are unaffected.
+func Offset(clock Clock, d time.Duration) Clock {
+	return &mappedClock{
+		Clock: clock,
+		now:   func(t time.Time) time.Time { return t.Add(d) },
+	}
+}
+
+// Scale returns a Clock running factor times as fast as clock, from its
+// current time: after one second of clock, two seconds pass on Scale(clock, 2).
+// Timers and tickers are shortened or lengthened to match.
+// Times delivered on their channels are those of clock.
+// Scale panics if factor isn't positive.
+func Scale(clock Clock, factor float64) Clock {
+	if factor <= 0 {
+		panic(errNonPositiveFactor)
+	}
+
+	anchor := clock.Now()
+	scale := func(d time.Duration) time.Duration {
+		return time.Duration(float64(d) / factor)
+	}
+	return &mappedClock{
+		Clock: clock,
+		now: func(t time.Time) time.Time {
+			return anchor.Add(time.Duration(float64(t.Sub(anchor)) * factor))
+		},
+		timer: scale,
+		ticker: func(d time.Duration) time.Duration {
+			if d = scale(d); d <= 0 {
+				d = 1
+			}
+			return d
+		},
+	}
+}
+
+// Jitter returns a Clock whose timers and sleeps wait up to max longer than
+// asked, by a random amount drawn from r, or from a source seeded with the
+// current time if r is nil. Tickers are unaffected.
+// Jitter panics if max is negative.
+func Jitter(clock Clock, max time.Duration, r *rand.Rand) Clock {
+	if max < 0 {
+		panic(errNegativeJitter)
+	}
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	var mutex sync.Mutex
+	return &mappedClock{
+		Clock: clock,
+		timer: func(d time.Duration) time.Duration {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			return d + time.Duration(r.Int63n(int64(max)+1))
+		},
+	}
+}
+
+// Quantize returns a Clock whose time advances in steps of q: its Now is
+// truncated to a multiple of q since the zero time, and its timers and sleeps
+// wake at the first multiple of q on or after their deadline, unless their
+// duration isn't positive.
+// Ticker periods are rounded up to a multiple of q.
+// Quantize panics if q isn't positive.
+func Quantize(clock Clock, q time.Duration) Clock {
+	if q <= 0 {
+		panic(errNonPositiveQuantum)
+	}
+
+	return &mappedClock{
+		Clock: clock,
+		now:   func(t time.Time) time.Time { return t.Truncate(q) },
+		timer: func(d time.Duration) time.Duration {
+			if d <= 0 {
+				return d
+			}
+
+			now := clock.Now()
+			deadline := now.Add(d)
+			rounded := deadline.Truncate(q)
+			if rounded.Before(deadline) {
+				rounded = rounded.Add(q)
+			}
+			return rounded.Sub(now)
+		},
+		ticker: func(d time.Duration) time.Duration {
+			if r := d % q; r != 0 {
+				d += q - r
+			}
+			return d
+		},
+	}
+}
+
+// Record returns a Clock appending the calls made to it to log,
+// before delegating them to clock.
+func Record(clock Clock, log *CallLog) Clock {
+	return &recordingClock{
+		Clock: clock,
+		log:   log,
+	}
+}
+
+// A Call is a call made to a clock returned by Record.
+type Call struct {
+	// Method is the name of the Clock, Timer or Ticker method called.
+	Method string
+
+	// D is the duration passed to the call, if any.
+	D time.Duration
+
+	// At is the time of the recorded clock when the call was made.
+	At time.Time
+
+	// Clock is the name of the recorded clock (see Name), if it has one.
+	Clock string
+}
+
+// CallLog collects the calls made to clocks returned by Record.
+// It is safe for concurrent use.
+type CallLog struct {
+	mutex sync.Mutex
+	calls []Call
+}
+
+// Calls returns the calls recorded so far, in order.
+func (log *CallLog) Calls() []Call {
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+
+	return append([]Call(nil), log.calls...)
+}
+
+// Reset forgets the calls recorded so far.
+func (log *CallLog) Reset() {
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+
+	log.calls = nil
+}
+
+type recordingClock struct {
+	Clock
+	log *CallLog
+}
+
+func (clock *recordingClock) record(method string, d time.Duration) {
+	call := Call{Method: method, D: d, At: clock.Clock.Now(), Clock: Name(clock.Clock)}
+
+	clock.log.mutex.Lock()
+	defer clock.log.mutex.Unlock()
+
+	clock.log.calls = append(clock.log.calls, call)
+}
+
+func (clock *recordingClock) Now() time.Time {
+	now := clock.Clock.Now()
+	clock.record("Now", 0)
+	return now
+}
+
+func (clock *recordingClock) Since(t time.Time) time.Duration {
+	clock.record("Since", 0)
+	return clock.Clock.Since(t)
+}
+
+func (clock *recordingClock) NewTimer(d time.Duration) Timer {
+	clock.record("NewTimer", d)
+	return &recordingTimer{Timer: clock.Clock.NewTimer(d), clock: clock}
+}
+
+func (clock *recordingClock) Sleep(d time.Duration) {
+	clock.record("Sleep", d)
+	clock.Clock.Sleep(d)
+}
+
+func (clock *recordingClock) After(d time.Duration) <-chan time.Time {
+	clock.record("After", d)
+	return clock.Clock.After(d)
+}
+
+func (clock *recordingClock) AfterFunc(d time.Duration, f func()) Timer {
+	clock.record("AfterFunc", d)
+	return &recordingTimer{Timer: clock.Clock.AfterFunc(d, f), clock: clock}
+}
+
+func (clock *recordingClock) NewTicker(d time.Duration) Ticker {
+	clock.record("NewTicker", d)
+	return &recordingTicker{Ticker: clock.Clock.NewTicker(d), clock: clock}
+}
+
+func (clock *recordingClock) Tick(d time.Duration) func() <-chan time.Time {
+	clock.record("Tick", d)
+	return clock.Clock.Tick(d)
+}
+
+type recordingTimer struct {
+	Timer
+	clock *recordingClock
+}
+
+func (timer *recordingTimer) Stop() bool {
+	timer.clock.record("Timer.Stop", 0)
+	return timer.Timer.Stop()
+}
+
+func (timer *recordingTimer) Reset(d time.Duration) bool {
+	timer.clock.record("Timer.Reset", d)
+	return timer.Timer.Reset(d)
+}
+
+type recordingTicker struct {
+	Ticker
+	clock *recordingClock
+}
+
+func (ticker *recordingTicker) Stop() {
+	ticker.clock.record("Ticker.Stop", 0)
+	ticker.Ticker.Stop()
+}
+
+func (ticker *recordingTicker) Reset(d time.Duration) {
+	ticker.clock.record("Ticker.Reset", d)
+	ticker.Ticker.Reset(d)
+}
+
+// mappedClock is a Clock mapping the time read from another, and the
+// durations waited on it. Nil mappings are the identity.
+type mappedClock struct {
+	Clock
+	now    func(time.Time) time.Time
+	timer  func(time.Duration) time.Duration
+	ticker func(time.Duration) time.Duration
+}
+
+func (clock *mappedClock) Now() time.Time {
+	if clock.now == nil {
+		return clock.Clock.Now()
+	}
+	return clock.now(clock.Clock.Now())
+}
+
+func (clock *mappedClock) Since(t time.Time) time.Duration {
+	return clock.Now().Sub(t)
+}
+
+func (clock *mappedClock) mapTimer(d time.Duration) time.Duration {
+	if clock.timer == nil {
+		return d
+	}
+	return clock.timer(d)
+}
+
+func (clock *mappedClock) mapTicker(d time.Duration) time.Duration {
+	if clock.ticker == nil {
+		return d
+	}
+	return clock.ticker(d)
+}
+
+func (clock *mappedClock) NewTimer(d time.Duration) Timer {
+	return &mappedTimer{
+		Timer: clock.Clock.NewTimer(clock.mapTimer(d)),
+		clock: clock,
+	}
+}
+
+func (clock *mappedClock) Sleep(d time.Duration) {
+	clock.Clock.Sleep(clock.mapTimer(d))
+}
+
+func (clock *mappedClock) After(d time.Duration) <-chan time.Time {
+	return clock.Clock.After(clock.mapTimer(d))
+}
+
+func (clock *mappedClock) AfterFunc(d time.Duration, f func()) Timer {
+	return &mappedTimer{
+		Timer: clock.Clock.AfterFunc(clock.mapTimer(d), f),
+		clock: clock,
+	}
+}
+
+func (clock *mappedClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic(errNonPositiveInterval)
+	}
+
+	return &mappedTicker{
+		Ticker: clock.Clock.NewTicker(clock.mapTicker(d)),
+		clock:  clock,
+	}
+}
+
+func (clock *mappedClock) Tick(d time.Duration) func() <-chan time.Time {
+	if d <= 0 {
+		return clock.Clock.Tick(d)
+	}
+	return clock.Clock.Tick(clock.mapTicker(d))
+}
+
+type mappedTimer struct {
+	Timer
+	clock *mappedClock
+}
+
+func (timer *mappedTimer) Reset(d time.Duration) bool {
+	return timer.Timer.Reset(timer.clock.mapTimer(d))
+}
+
+type mappedTicker struct {
+	Ticker
+	clock *mappedClock
+}
+
+func (ticker *mappedTicker) Reset(d time.Duration) {
+	ticker.Ticker.Reset(ticker.clock.mapTicker(d))
+}
@@ -0,0 +1,119 @@
+package clock_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestOffset(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+	c := clock.Offset(fake, time.Hour)
+
+	if now, expected := c.Now(), start.Add(time.Hour); !now.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, now)
+	}
+	if since := c.Since(start); since != time.Hour {
+		t.Errorf("expected 1h got %s", since)
+	}
+
+	timer := c.NewTimer(time.Second)
+	fake.Advance(time.Second)
+	assertSent(t, start.Add(time.Second), timer.C())
+}
+
+func TestScale(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+	c := clock.Scale(fake, 2)
+
+	timer := c.NewTimer(4 * time.Second)
+	ch := timer.C()
+	fake.Advance(time.Second)
+	if now, expected := c.Now(), start.Add(2*time.Second); !now.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, now)
+	}
+	assertNotSent(t, ch)
+
+	fake.Advance(time.Second)
+	assertSent(t, start.Add(2*time.Second), ch)
+
+	// Reset is scaled too
+	timer.Reset(2 * time.Second)
+	fake.Advance(time.Second)
+	assertSent(t, start.Add(3*time.Second), ch)
+}
+
+func TestScale_NonPositive(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	clock.Scale(clock.NewFakeClock(), 0)
+}
+
+func TestJitter(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+	c := clock.Jitter(fake, time.Second, rand.New(rand.NewSource(1)))
+
+	for i := 0; i < 10; i++ {
+		c.NewTimer(time.Second).C()
+	}
+	for _, timer := range fake.PendingTimers() {
+		if d := timer.Deadline.Sub(start); d < time.Second || d > 2*time.Second {
+			t.Errorf("expected a deadline within [1s, 2s], got %s", d)
+		}
+	}
+}
+
+func TestQuantize(t *testing.T) {
+	start := time.Unix(10, 300*int64(time.Millisecond))
+	fake := clock.NewFakeClockAt(start)
+	c := clock.Quantize(fake, time.Second)
+
+	if now, expected := c.Now(), time.Unix(10, 0); !now.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, now)
+	}
+
+	// wakes on the next whole second after the deadline
+	ch := c.After(time.Second)
+	fake.Advance(time.Second)
+	assertNotSent(t, ch)
+	fake.Advance(700 * time.Millisecond)
+	assertSent(t, time.Unix(12, 0), ch)
+}
+
+func TestRecord(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	var log clock.CallLog
+	c := clock.Record(fake, &log)
+
+	c.Now()
+	timer := c.NewTimer(time.Second)
+	fake.Advance(time.Second)
+	timer.Reset(2 * time.Second)
+	timer.Stop()
+
+	expected := []clock.Call{
+		{Method: "Now", At: start},
+		{Method: "NewTimer", D: time.Second, At: start},
+		{Method: "Timer.Reset", D: 2 * time.Second, At: start.Add(time.Second)},
+		{Method: "Timer.Stop", At: start.Add(time.Second)},
+	}
+	if calls := log.Calls(); !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected %v got %v", expected, calls)
+	}
+
+	log.Reset()
+	if calls := log.Calls(); len(calls) != 0 {
+		t.Errorf("expected no calls, got %v", calls)
+	}
+}
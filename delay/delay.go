@@ -0,0 +1,272 @@
+// Package delay runs tasks later, unless they're canceled, on a pool of
+// workers, with their due times measured by a clock.Clock.
+package delay
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// ErrStopped is returned when submitting a task to a stopped Executor.
+var ErrStopped = errors.New("delay: stopped")
+
+// Executor runs tasks at their due time on a fixed pool of workers.
+// The due tasks wait in a clock.DeadlineQueue, so any number of tasks costs
+// a single timer and a single goroutine dispatching them to the workers.
+// A task due while all the workers are busy runs as soon as one is free.
+type Executor struct {
+	clock clock.Clock
+	queue *clock.DeadlineQueue[*Task]
+	work  chan due
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	stop       chan struct{}
+	dispatched chan struct{}
+	runs       *clock.WaitGroup
+
+	mutex   sync.Mutex
+	stopped bool
+}
+
+// A Task is a function submitted to an Executor, which can be canceled or
+// rescheduled until it starts.
+type Task struct {
+	executor *Executor
+	f        func(ctx context.Context)
+	done     chan struct{}
+
+	mutex sync.Mutex
+	state state
+	gen   int // the number of times the task was rescheduled
+}
+
+type state int
+
+const (
+	pending state = iota
+	running
+	finished
+	canceled
+)
+
+// due is a task popped from the queue for a worker, at a generation.
+type due struct {
+	task *Task
+	gen  int
+}
+
+// New returns an Executor measured by c, running tasks on n workers.
+// It panics if n < 1.
+func New(c clock.Clock, n int) *Executor {
+	if n < 1 {
+		panic("delay: non-positive number of workers for New")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &Executor{
+		clock:      c,
+		queue:      clock.NewDeadlineQueue[*Task](c),
+		work:       make(chan due),
+		ctx:        ctx,
+		cancel:     cancel,
+		stop:       make(chan struct{}),
+		dispatched: make(chan struct{}),
+		runs:       clock.NewWaitGroup(c),
+	}
+
+	go e.dispatch()
+	for i := 0; i < n; i++ {
+		go e.worker()
+	}
+	return e
+}
+
+// After submits f to run d from now.
+func (e *Executor) After(d time.Duration, f func(ctx context.Context)) (*Task, error) {
+	return e.At(e.clock.Now().Add(d), f)
+}
+
+// At submits f to run at t. If t is past, f runs as soon as a worker is free.
+// The context of f is canceled when the Executor stops without waiting for it.
+func (e *Executor) At(t time.Time, f func(ctx context.Context)) (*Task, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.stopped {
+		return nil, ErrStopped
+	}
+
+	task := &Task{
+		executor: e,
+		f:        f,
+		done:     make(chan struct{}),
+	}
+	e.queue.Add(task, t)
+	return task, nil
+}
+
+// Len returns the number of tasks waiting for their due time.
+func (e *Executor) Len() int {
+	return e.queue.Len()
+}
+
+// Stop cancels the tasks that haven't started, and waits for the tasks
+// running to finish. If ctx is done first, Stop cancels the contexts of the
+// tasks running and returns ctx.Err().
+func (e *Executor) Stop(ctx context.Context) error {
+	e.mutex.Lock()
+	if !e.stopped {
+		e.stopped = true
+		close(e.stop)
+		e.queue.Stop()
+	}
+	e.mutex.Unlock()
+
+	<-e.dispatched
+
+	// the tasks left in the queue never run
+	for {
+		task, _, ok := e.queue.Peek()
+		if !ok {
+			break
+		}
+		e.queue.Remove(task)
+		task.Cancel()
+	}
+
+	err := e.runs.WaitContext(ctx)
+	e.cancel()
+	return err
+}
+
+func (e *Executor) dispatch() {
+	defer close(e.dispatched)
+
+	for {
+		select {
+		case <-e.queue.C():
+		case <-e.stop:
+			return
+		}
+
+		tasks := e.queue.PopDue()
+		for i, task := range tasks {
+			task.mutex.Lock()
+			item := due{task: task, gen: task.gen}
+			task.mutex.Unlock()
+
+			select {
+			case e.work <- item:
+			case <-e.stop:
+				// the tasks left were popped, so they're canceled here
+				for _, task := range tasks[i:] {
+					task.Cancel()
+				}
+				return
+			}
+		}
+	}
+}
+
+func (e *Executor) worker() {
+	for {
+		select {
+		case item := <-e.work:
+			if item.task.start(item.gen) {
+				e.run(item.task)
+			}
+		case <-e.stop:
+			return
+		}
+	}
+}
+
+func (e *Executor) run(task *Task) {
+	defer e.runs.Done()
+
+	task.f(e.ctx)
+
+	task.mutex.Lock()
+	task.state = finished
+	task.mutex.Unlock()
+	close(task.done)
+}
+
+// start marks the task running, unless it was canceled, or rescheduled
+// since it was popped at gen. A task handed to a worker as the executor
+// stops is canceled.
+func (task *Task) start(gen int) bool {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+
+	if task.state != pending || task.gen != gen {
+		return false
+	}
+
+	e := task.executor
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	// Stop waits for the runs started before it stopped the executor
+	if e.stopped {
+		task.state = canceled
+		close(task.done)
+		return false
+	}
+	task.state = running
+	e.runs.Add(1)
+	return true
+}
+
+// Cancel cancels the task if it hasn't started, and reports whether it did.
+func (task *Task) Cancel() bool {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+
+	if task.state != pending {
+		return false
+	}
+	task.state = canceled
+	task.executor.queue.Remove(task)
+	close(task.done)
+	return true
+}
+
+// Reschedule moves the due time of the task to t if it hasn't started, and
+// reports whether it did.
+func (task *Task) Reschedule(t time.Time) bool {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+
+	if task.state != pending {
+		return false
+	}
+
+	e := task.executor
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	// Stop cancels the tasks in the queue once it's stopped
+	if e.stopped {
+		return false
+	}
+	// a worker may have the task already, which it mustn't run
+	task.gen++
+	e.queue.Add(task, t)
+	return true
+}
+
+// Delay is shorthand for Reschedule(now + d).
+func (task *Task) Delay(d time.Duration) bool {
+	return task.Reschedule(task.executor.clock.Now().Add(d))
+}
+
+// Done returns a channel closed once the task has run or been canceled.
+func (task *Task) Done() <-chan struct{} {
+	return task.done
+}
@@ -0,0 +1,191 @@
+package delay_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+	"github.com/go-toolbelt/clock/delay"
+)
+
+const timeout = 100 * time.Millisecond
+
+// signalingTask returns a task that sends its name on runs.
+func signalingTask(name string, runs chan<- string) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		runs <- name
+	}
+}
+
+func requireRun(t *testing.T, expected string, runs <-chan string) {
+	t.Helper()
+
+	select {
+	case name := <-runs:
+		if name != expected {
+			t.Fatalf("expected %s to run got %s", expected, name)
+		}
+	case <-time.After(timeout):
+		t.Fatalf("timeout: expected %s to run", expected)
+	}
+}
+
+func requireNoRun(t *testing.T, runs <-chan string) {
+	t.Helper()
+
+	select {
+	case name := <-runs:
+		t.Fatalf("%s ran unexpectedly", name)
+	case <-time.After(timeout / 10):
+	}
+}
+
+func submit(t *testing.T, e *delay.Executor, d time.Duration, f func(ctx context.Context)) *delay.Task {
+	t.Helper()
+
+	task, err := e.After(d, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return task
+}
+
+func TestExecutor(t *testing.T) {
+	fake := clock.NewFakeClock()
+	e := delay.New(fake, 2)
+	defer e.Stop(context.Background())
+
+	runs := make(chan string, 10)
+	a := submit(t, e, 2*time.Second, signalingTask("a", runs))
+	submit(t, e, 1*time.Second, signalingTask("b", runs))
+
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	fake.Advance(1 * time.Second)
+	requireRun(t, "b", runs)
+	requireNoRun(t, runs)
+
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	fake.Advance(1 * time.Second)
+	requireRun(t, "a", runs)
+	clocktest.RequireClosedWithin(t, a.Done(), timeout)
+
+	if a.Cancel() {
+		t.Error("expected a task that ran not to be canceled")
+	}
+}
+
+func TestExecutor_Cancel(t *testing.T) {
+	fake := clock.NewFakeClock()
+	e := delay.New(fake, 1)
+	defer e.Stop(context.Background())
+
+	runs := make(chan string, 10)
+	a := submit(t, e, 1*time.Second, signalingTask("a", runs))
+
+	if !a.Cancel() {
+		t.Error("expected a to be canceled")
+	}
+	if a.Cancel() {
+		t.Error("expected a to be canceled once")
+	}
+	clocktest.RequireClosedWithin(t, a.Done(), timeout)
+
+	if n := e.Len(); n != 0 {
+		t.Errorf("expected %d tasks got %d", 0, n)
+	}
+	fake.Advance(1 * time.Second)
+	requireNoRun(t, runs)
+}
+
+func TestExecutor_Reschedule(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+	e := delay.New(fake, 1)
+	defer e.Stop(context.Background())
+
+	runs := make(chan string, 10)
+	a := submit(t, e, 1*time.Second, signalingTask("a", runs))
+
+	if !a.Reschedule(start.Add(3 * time.Second)) {
+		t.Fatal("expected a to be rescheduled")
+	}
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	fake.Advance(2 * time.Second)
+	requireNoRun(t, runs)
+
+	if !a.Delay(2 * time.Second) {
+		t.Fatal("expected a to be delayed")
+	}
+	fake.Advance(1 * time.Second)
+	requireNoRun(t, runs)
+
+	fake.Advance(1 * time.Second)
+	requireRun(t, "a", runs)
+}
+
+func TestExecutor_Workers(t *testing.T) {
+	fake := clock.NewFakeClock()
+	e := delay.New(fake, 1)
+	defer e.Stop(context.Background())
+
+	runs := make(chan string, 10)
+	release := make(chan struct{})
+	submit(t, e, 1*time.Second, func(ctx context.Context) {
+		runs <- "a"
+		<-release
+	})
+	b := submit(t, e, 1*time.Second, signalingTask("b", runs))
+
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	fake.Advance(1 * time.Second)
+	requireRun(t, "a", runs)
+
+	// b is due, but waits for the only worker, and can still be canceled
+	requireNoRun(t, runs)
+	if !b.Cancel() {
+		t.Error("expected b to be canceled")
+	}
+
+	close(release)
+	requireNoRun(t, runs)
+}
+
+func TestExecutor_Stop(t *testing.T) {
+	fake := clock.NewFakeClock()
+	e := delay.New(fake, 1)
+
+	runs := make(chan string, 10)
+	canceled := make(chan struct{})
+	submit(t, e, 1*time.Second, func(ctx context.Context) {
+		runs <- "a"
+		<-ctx.Done()
+		close(canceled)
+	})
+	b := submit(t, e, 2*time.Second, signalingTask("b", runs))
+
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	fake.Advance(1 * time.Second)
+	requireRun(t, "a", runs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the task running is canceled, the task pending never runs
+	if err := e.Stop(ctx); err != context.Canceled {
+		t.Errorf("expected %v got %v", context.Canceled, err)
+	}
+	clocktest.RequireClosedWithin(t, canceled, timeout)
+	clocktest.RequireClosedWithin(t, b.Done(), timeout)
+
+	fake.Advance(1 * time.Second)
+	requireNoRun(t, runs)
+
+	if _, err := e.After(time.Second, signalingTask("c", runs)); err != delay.ErrStopped {
+		t.Errorf("expected %v got %v", delay.ErrStopped, err)
+	}
+	if b.Delay(time.Second) {
+		t.Error("expected a canceled task not to be delayed")
+	}
+}
@@ -0,0 +1,66 @@
+package clock
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TimeoutError is returned by Do when the time limit is reached.
+type TimeoutError struct {
+	// Limit is the time limit passed to Do.
+	Limit time.Duration
+
+	// Elapsed is the time elapsed between the call to Do and the time limit being detected.
+	Elapsed time.Duration
+}
+
+func (err *TimeoutError) Error() string {
+	return fmt.Sprintf("timeout: after %s (limit %s)", err.Elapsed, err.Limit)
+}
+
+// Timeout reports that the error is a timeout, like net.Error.
+func (err *TimeoutError) Timeout() bool {
+	return true
+}
+
+// Unwrap returns context.DeadlineExceeded,
+// so errors.Is(err, context.DeadlineExceeded) holds for timeout errors.
+func (err *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// Do runs f with a context that expires after d, as measured by clock.
+//
+// It returns the error returned by f, or a *TimeoutError once the time limit is reached,
+// whether f returned context.DeadlineExceeded or is still running.
+// In the latter case, f keeps running in its own goroutine, and its result is discarded.
+// If ctx ends first, Do returns ctx.Err().
+func Do(ctx context.Context, clock Clock, d time.Duration, f func(ctx context.Context) error) error {
+	start := clock.Now()
+
+	ctx, cancel := WithTimeout(ctx, clock, d)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- f(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+		if err == nil || ctx.Err() != context.DeadlineExceeded {
+			return err
+		}
+	case <-ctx.Done():
+		if ctx.Err() != context.DeadlineExceeded {
+			return ctx.Err()
+		}
+	}
+
+	return &TimeoutError{
+		Limit:   d,
+		Elapsed: clock.Since(start),
+	}
+}
@@ -0,0 +1,72 @@
+package clock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestDo(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	errFailed := errors.New("failed")
+	err := clock.Do(context.Background(), fake, 1*time.Second, func(context.Context) error {
+		return errFailed
+	})
+	if err != errFailed {
+		t.Errorf("expected %v got %v", errFailed, err)
+	}
+}
+
+func TestDo_Timeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- clock.Do(context.Background(), fake, 1*time.Second, func(context.Context) error {
+			// ignore the context
+			<-release
+			return nil
+		})
+	}()
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(3 * time.Second)
+
+	select {
+	case err := <-errs:
+		var timeoutErr *clock.TimeoutError
+		if !errors.As(err, &timeoutErr) {
+			t.Fatalf("expected a timeout error got %v", err)
+		}
+		if timeoutErr.Limit != 1*time.Second || timeoutErr.Elapsed != 3*time.Second {
+			t.Errorf("expected timeout %s after %s got %s after %s", 1*time.Second, 3*time.Second, timeoutErr.Limit, timeoutErr.Elapsed)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected %v to be %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(sentTimeout):
+		t.Fatal("timeout: Do did not return")
+	}
+}
+
+func TestDo_Canceled(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := clock.Do(ctx, fake, 1*time.Second, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != context.Canceled {
+		t.Errorf("expected %v got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,193 @@
+// Package drift estimates the offset and drift rate of a clock.Clock
+// relative to a reference clock, such as the system clock against an
+// NTP-disciplined one, or a skewed fake clock against the one it wraps.
+package drift
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// DefaultSize is the number of samples an Estimator keeps by default.
+const DefaultSize = 64
+
+// An Estimate describes a clock relative to a reference clock.
+// Error bounds are the half-widths of 95% confidence intervals.
+type Estimate struct {
+	// Offset is how far the clock was ahead of the reference at the last
+	// sample, negative if it was behind.
+	Offset      time.Duration
+	OffsetError time.Duration
+
+	// Drift is how fast the clock runs ahead of the reference, in parts
+	// per million: the microseconds it gains per second of the reference.
+	Drift      float64
+	DriftError float64
+
+	// Samples is the number of samples the estimate is drawn from.
+	Samples int
+}
+
+type sample struct {
+	// x is the time of the reference since the first sample, and y is the
+	// offset of the clock, both in seconds
+	x, y float64
+}
+
+// Estimator samples a clock and a reference clock, and fits the offsets
+// between them to a line, whose slope is the drift.
+//
+// It is safe for concurrent use.
+type Estimator struct {
+	reference clock.Clock
+	clock     clock.Clock
+	size      int
+
+	mutex   sync.Mutex
+	start   time.Time
+	samples []sample
+}
+
+// NewEstimator returns an Estimator of c relative to reference, keeping
+// the last size samples, or DefaultSize if size isn't positive.
+func NewEstimator(reference, c clock.Clock, size int) *Estimator {
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	return &Estimator{
+		reference: reference,
+		clock:     c,
+		size:      size,
+	}
+}
+
+// Sample reads both clocks and records the offset between them.
+func (e *Estimator) Sample() {
+	e.Add(e.reference.Now(), e.clock.Now())
+}
+
+// Add records a sample taken elsewhere: the time read on the reference,
+// and the time read on the clock at the same moment. Only wall times are
+// compared, so monotonic readings are ignored.
+func (e *Estimator) Add(reference, t time.Time) {
+	reference = reference.Round(0)
+	t = t.Round(0)
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if len(e.samples) == 0 {
+		e.start = reference
+	}
+	if len(e.samples) == e.size {
+		copy(e.samples, e.samples[1:])
+		e.samples = e.samples[:len(e.samples)-1]
+	}
+	e.samples = append(e.samples, sample{
+		x: reference.Sub(e.start).Seconds(),
+		y: t.Sub(reference).Seconds(),
+	})
+}
+
+// Run samples the clocks every interval, measured by the reference clock,
+// until ctx is done, then returns ctx.Err().
+func (e *Estimator) Run(ctx context.Context, interval time.Duration) error {
+	ticker := clock.NewTickerImmediate(e.reference, interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			e.Sample()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Estimate returns the estimate drawn from the samples recorded so far.
+// It reports false until at least three samples, taken at different times
+// of the reference, are recorded, since fewer can't bound the estimate.
+func (e *Estimator) Estimate() (Estimate, bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	n := len(e.samples)
+	if n < 3 {
+		return Estimate{}, false
+	}
+
+	var meanX, meanY float64
+	for _, s := range e.samples {
+		meanX += s.x
+		meanY += s.y
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+
+	var sxx, sxy float64
+	for _, s := range e.samples {
+		sxx += (s.x - meanX) * (s.x - meanX)
+		sxy += (s.x - meanX) * (s.y - meanY)
+	}
+	if sxx == 0 {
+		return Estimate{}, false
+	}
+
+	slope := sxy / sxx
+	intercept := meanY - slope*meanX
+
+	var ssr float64
+	for _, s := range e.samples {
+		r := s.y - intercept - slope*s.x
+		ssr += r * r
+	}
+	variance := ssr / float64(n-2)
+
+	last := e.samples[n-1].x
+	t := quantile(n - 2)
+	offsetError := t * math.Sqrt(variance*(1/float64(n)+(last-meanX)*(last-meanX)/sxx))
+	driftError := t * math.Sqrt(variance/sxx)
+
+	return Estimate{
+		Offset:      seconds(intercept + slope*last),
+		OffsetError: seconds(offsetError),
+		Drift:       slope * 1e6,
+		DriftError:  driftError * 1e6,
+		Samples:     n,
+	}, true
+}
+
+// Reset discards the samples recorded so far.
+func (e *Estimator) Reset() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.samples = e.samples[:0]
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Round(s * float64(time.Second)))
+}
+
+// quantiles are the two-sided 95% quantiles of Student's t-distribution,
+// indexed by degrees of freedom minus one.
+var quantiles = []float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+// quantile returns the two-sided 95% quantile of Student's t-distribution
+// with df degrees of freedom, approximated by the normal one past 30.
+func quantile(df int) float64 {
+	if df <= len(quantiles) {
+		return quantiles[df-1]
+	}
+	return 1.960
+}
@@ -0,0 +1,106 @@
+package drift_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/drift"
+)
+
+func TestEstimator(t *testing.T) {
+	fake := clock.NewFakeClock()
+	skewed := clock.Offset(clock.Scale(fake, 1+50e-6), time.Second)
+
+	e := drift.NewEstimator(fake, skewed, 0)
+	for i := 0; i < 10; i++ {
+		if _, ok := e.Estimate(); ok != (i >= 3) {
+			t.Fatalf("expected an estimate after 3 samples, got one after %d", i)
+		}
+
+		e.Sample()
+		fake.Advance(time.Minute)
+	}
+
+	estimate, ok := e.Estimate()
+	if !ok {
+		t.Fatal("expected an estimate")
+	}
+
+	// 50ppm over 9 minutes is 27ms
+	if d := estimate.Offset - (time.Second + 27*time.Millisecond); d < -time.Microsecond || d > time.Microsecond {
+		t.Errorf("expected an offset of 1.027s got %s", estimate.Offset)
+	}
+	if math.Abs(estimate.Drift-50) > 0.01 {
+		t.Errorf("expected a drift of 50ppm got %f", estimate.Drift)
+	}
+	if estimate.OffsetError > time.Microsecond || estimate.DriftError > 0.01 {
+		t.Errorf("expected tight bounds got %s and %fppm", estimate.OffsetError, estimate.DriftError)
+	}
+	if estimate.Samples != 10 {
+		t.Errorf("expected 10 samples got %d", estimate.Samples)
+	}
+}
+
+func TestEstimator_Noise(t *testing.T) {
+	start := time.Unix(0, 0)
+	e := drift.NewEstimator(nil, nil, 4)
+
+	// the clock drifts by 100ppm, read with a millisecond of noise; the
+	// first sample falls out of the window
+	noise := []time.Duration{time.Hour, time.Millisecond, -time.Millisecond, time.Millisecond, -time.Millisecond}
+	for i, n := range noise {
+		reference := start.Add(time.Duration(i) * 10 * time.Second)
+		e.Add(reference, reference.Add(time.Duration(i)*time.Millisecond+n))
+	}
+
+	estimate, ok := e.Estimate()
+	if !ok {
+		t.Fatal("expected an estimate")
+	}
+	if estimate.Samples != 4 {
+		t.Errorf("expected 4 samples got %d", estimate.Samples)
+	}
+	if estimate.DriftError <= 0 || estimate.OffsetError <= 0 {
+		t.Errorf("expected error bounds got %fppm and %s", estimate.DriftError, estimate.OffsetError)
+	}
+	if math.Abs(estimate.Drift-100) > estimate.DriftError {
+		t.Errorf("expected 100ppm within %f got %f", estimate.DriftError, estimate.Drift)
+	}
+	if d := estimate.Offset - 4*time.Millisecond; d < -estimate.OffsetError || d > estimate.OffsetError {
+		t.Errorf("expected 4ms within %s got %s", estimate.OffsetError, estimate.Offset)
+	}
+
+	e.Reset()
+	if _, ok := e.Estimate(); ok {
+		t.Error("expected no estimate after Reset")
+	}
+}
+
+func TestEstimator_Run(t *testing.T) {
+	fake := clock.NewFakeClock()
+	e := drift.NewEstimator(fake, clock.Scale(fake, 2), 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- e.Run(ctx, time.Second) }()
+
+	// advance until the samples taken allow an estimate
+	for {
+		if _, ok := e.Estimate(); ok {
+			break
+		}
+		fake.BlockUntil(1)
+		fake.Advance(time.Second)
+	}
+	cancel()
+
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected %v got %v", context.Canceled, err)
+	}
+	if estimate, ok := e.Estimate(); !ok || math.Abs(estimate.Drift-1e6) > 1 {
+		t.Errorf("expected a drift of 1e6ppm got %+v", estimate)
+	}
+}
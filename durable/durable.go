@@ -0,0 +1,261 @@
+// Package durable schedules timers persisted in a Store, so delayed work
+// like "send a reminder in 24h" survives process restarts.
+//
+// Timers are measured by a clock.Clock, so they can be tested with the fake
+// clock, and fire at least once: a timer is deleted from the store only once
+// its handler succeeds, so a crash in between fires it again on restart.
+package durable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// A Handler handles timers of a kind when they fire. Its context is canceled
+// when the Timers are stopped. A timer whose handler fails is retried.
+type Handler func(ctx context.Context, record Record) error
+
+var (
+	// ErrStopped is returned when scheduling a timer on stopped Timers.
+	ErrStopped = errors.New("durable: stopped")
+
+	// ErrUnknownKind is reported when a timer fires without a handler for
+	// its kind.
+	ErrUnknownKind = errors.New("durable: no handler for kind")
+)
+
+// Timers are durable timers, persisted in a Store and fired on a clock.
+type Timers struct {
+	clock   clock.Clock
+	store   Store
+	retry   time.Duration
+	onError func(record Record, err error)
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running *clock.WaitGroup
+
+	mutex    sync.Mutex
+	handlers map[string]Handler
+	armed    map[string]*armed
+	stopped  bool
+}
+
+// armed is a timer armed in memory.
+type armed struct {
+	record Record
+	timer  clock.Timer
+}
+
+// New returns Timers persisted in store and measured by c.
+// Register handlers with Handle, then call Start to rearm the timers
+// persisted by a previous process.
+func New(c clock.Clock, store Store, opts ...Option) *Timers {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &Timers{
+		clock:    c,
+		store:    store,
+		retry:    time.Minute,
+		onError:  func(Record, error) {},
+		ctx:      ctx,
+		cancel:   cancel,
+		running:  clock.NewWaitGroup(c),
+		handlers: map[string]Handler{},
+		armed:    map[string]*armed{},
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Handle sets the handler called when timers of kind fire.
+func (t *Timers) Handle(kind string, h Handler) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.handlers[kind] = h
+}
+
+// Start loads the timers persisted in the store and arms them.
+// Timers due while the process was down fire right away.
+func (t *Timers) Start(ctx context.Context) error {
+	records, err := t.store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("durable: loading timers: %w", err)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.stopped {
+		return ErrStopped
+	}
+	for _, record := range records {
+		if _, ok := t.armed[record.ID]; !ok {
+			t.arm(record, record.Due.Sub(t.clock.Now()))
+		}
+	}
+	return nil
+}
+
+// Schedule persists record, then arms it, replacing any timer with the same ID.
+func (t *Timers) Schedule(ctx context.Context, record Record) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.stopped {
+		return ErrStopped
+	}
+	if err := t.store.Save(ctx, record); err != nil {
+		return fmt.Errorf("durable: saving timer %q: %w", record.ID, err)
+	}
+
+	t.disarm(record.ID)
+	t.arm(record, record.Due.Sub(t.clock.Now()))
+	return nil
+}
+
+// After schedules a timer firing after d, as measured by the clock.
+func (t *Timers) After(ctx context.Context, id, kind string, d time.Duration, payload []byte) error {
+	return t.Schedule(ctx, Record{
+		ID:      id,
+		Kind:    kind,
+		Due:     t.clock.Now().Add(d),
+		Payload: payload,
+	})
+}
+
+// Cancel disarms the timer with the given ID and deletes it from the store.
+// A handler already running isn't interrupted.
+func (t *Timers) Cancel(ctx context.Context, id string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.disarm(id)
+	if err := t.store.Delete(ctx, id); err != nil {
+		return fmt.Errorf("durable: deleting timer %q: %w", id, err)
+	}
+	return nil
+}
+
+// Pending returns the timers armed, ordered by due time.
+func (t *Timers) Pending() []Record {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	records := make([]Record, 0, len(t.armed))
+	for _, a := range t.armed {
+		records = append(records, a.record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].Due.Equal(records[j].Due) {
+			return records[i].Due.Before(records[j].Due)
+		}
+		return records[i].ID < records[j].ID
+	})
+	return records
+}
+
+// Stop disarms the timers, leaving them in the store for the next Start,
+// and waits for running handlers to return. If ctx is done first, their
+// context is canceled and ctx's error is returned.
+func (t *Timers) Stop(ctx context.Context) error {
+	t.mutex.Lock()
+	t.stopped = true
+	for id := range t.armed {
+		t.disarm(id)
+	}
+	t.mutex.Unlock()
+
+	defer t.cancel()
+	return t.running.WaitContext(ctx)
+}
+
+// arm arms a timer in memory. The caller holds the mutex.
+func (t *Timers) arm(record Record, d time.Duration) {
+	a := &armed{record: record}
+	t.armed[record.ID] = a
+	a.timer = t.clock.AfterFunc(d, func() { t.fire(a) })
+}
+
+// disarm stops the timer armed with the given ID. The caller holds the mutex.
+func (t *Timers) disarm(id string) {
+	if a, ok := t.armed[id]; ok {
+		a.timer.Stop()
+		delete(t.armed, id)
+	}
+}
+
+func (t *Timers) fire(a *armed) {
+	t.mutex.Lock()
+	// the timer may have been replaced or canceled as it fired
+	if t.armed[a.record.ID] != a {
+		t.mutex.Unlock()
+		return
+	}
+	h, ok := t.handlers[a.record.Kind]
+	t.running.Add(1)
+	t.mutex.Unlock()
+	defer t.running.Done()
+
+	var err error
+	if ok {
+		err = h(t.ctx, a.record)
+	} else {
+		err = fmt.Errorf("%w %q", ErrUnknownKind, a.record.Kind)
+	}
+
+	// the store and the error handler are called without the mutex, so
+	// they may call back into the timers
+	t.mutex.Lock()
+	current := t.armed[a.record.ID] == a
+	t.mutex.Unlock()
+	if !current {
+		return
+	}
+
+	if err == nil {
+		err = t.store.Delete(t.ctx, a.record.ID)
+		if err == nil {
+			t.forget(a)
+			return
+		}
+		err = fmt.Errorf("durable: deleting timer %q: %w", a.record.ID, err)
+	}
+
+	t.onError(a.record, err)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	// the timer may have been replaced or canceled meanwhile
+	if t.armed[a.record.ID] == a && !t.stopped {
+		t.arm(a.record, t.retry)
+	}
+}
+
+// forget forgets a fired timer deleted from the store. If it was replaced
+// while it was being deleted, the replacement is saved again, since the
+// delete may have removed it from the store.
+func (t *Timers) forget(a *armed) {
+	t.mutex.Lock()
+	replaced, ok := t.armed[a.record.ID]
+	if replaced == a {
+		delete(t.armed, a.record.ID)
+	}
+	t.mutex.Unlock()
+
+	if !ok || replaced == a {
+		return
+	}
+	if err := t.store.Save(t.ctx, replaced.record); err != nil {
+		t.onError(replaced.record, fmt.Errorf("durable: saving timer %q: %w", replaced.record.ID, err))
+	}
+}
@@ -0,0 +1,290 @@
+package durable_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+	"github.com/go-toolbelt/clock/durable"
+)
+
+const timeout = 100 * time.Millisecond
+
+func recordingHandler() (durable.Handler, <-chan durable.Record) {
+	fired := make(chan durable.Record, 10)
+	return func(ctx context.Context, record durable.Record) error {
+		fired <- record
+		return nil
+	}, fired
+}
+
+func requireFired(t *testing.T, fired <-chan durable.Record, id string) durable.Record {
+	t.Helper()
+
+	select {
+	case record := <-fired:
+		if record.ID != id {
+			t.Fatalf("expected %q to fire, got %q", id, record.ID)
+		}
+		return record
+	case <-time.After(timeout):
+		t.Fatalf("timeout: %q did not fire within %s", id, timeout)
+		return durable.Record{}
+	}
+}
+
+func requireNotFired(t *testing.T, fired <-chan durable.Record) {
+	t.Helper()
+
+	select {
+	case record := <-fired:
+		t.Fatalf("%q fired unexpectedly", record.ID)
+	case <-time.After(timeout):
+	}
+}
+
+func TestTimers(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFakeClock()
+	store := durable.NewMemoryStore()
+
+	timers := durable.New(fake, store)
+	h, fired := recordingHandler()
+	timers.Handle("remind", h)
+	if err := timers.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := timers.After(ctx, "r1", "remind", 24*time.Hour, []byte("hi")); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Advance(23 * time.Hour)
+	requireNotFired(t, fired)
+
+	fake.Advance(time.Hour)
+	record := requireFired(t, fired, "r1")
+	if string(record.Payload) != "hi" {
+		t.Errorf("expected payload %q got %q", "hi", record.Payload)
+	}
+
+	clocktest.Eventually(t, clock.NewRealClock(), func() bool {
+		records, _ := store.Load(ctx)
+		return len(records) == 0
+	}, timeout, time.Millisecond)
+}
+
+func TestTimers_Restart(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFakeClock()
+	store := durable.NewFileStore(filepath.Join(t.TempDir(), "timers.json"))
+
+	first := durable.New(fake, store)
+	first.After(ctx, "soon", "remind", time.Hour, nil)
+	first.After(ctx, "later", "remind", 3*time.Hour, nil)
+	if err := first.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the process is down while the first timer is due
+	fake.Advance(2 * time.Hour)
+
+	second := durable.New(fake, store)
+	defer second.Stop(ctx)
+	h, fired := recordingHandler()
+	second.Handle("remind", h)
+	if err := second.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	requireFired(t, fired, "soon")
+
+	fake.Advance(time.Hour)
+	requireFired(t, fired, "later")
+}
+
+func TestTimers_Cancel(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFakeClock()
+	store := durable.NewMemoryStore()
+
+	timers := durable.New(fake, store)
+	h, fired := recordingHandler()
+	timers.Handle("remind", h)
+
+	timers.After(ctx, "r1", "remind", time.Hour, nil)
+	if err := timers.Cancel(ctx, "r1"); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Advance(time.Hour)
+	requireNotFired(t, fired)
+	if records, _ := store.Load(ctx); len(records) != 0 {
+		t.Errorf("expected no records, got %v", records)
+	}
+}
+
+func TestTimers_Replace(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFakeClock()
+
+	timers := durable.New(fake, durable.NewMemoryStore())
+	h, fired := recordingHandler()
+	timers.Handle("remind", h)
+
+	timers.After(ctx, "r1", "remind", time.Hour, nil)
+	timers.After(ctx, "r1", "remind", 2*time.Hour, nil)
+
+	fake.Advance(time.Hour)
+	requireNotFired(t, fired)
+	fake.Advance(time.Hour)
+	requireFired(t, fired, "r1")
+}
+
+func TestTimers_Retry(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFakeClock()
+	store := durable.NewMemoryStore()
+
+	errs := make(chan error, 10)
+	timers := durable.New(fake, store,
+		durable.WithRetryDelay(time.Minute),
+		durable.WithErrorHandler(func(record durable.Record, err error) { errs <- err }),
+	)
+
+	attempts := make(chan struct{}, 10)
+	failure := errors.New("unavailable")
+	timers.Handle("remind", func(ctx context.Context, record durable.Record) error {
+		attempts <- struct{}{}
+		if len(attempts) == 1 {
+			return failure
+		}
+		return nil
+	})
+
+	timers.After(ctx, "r1", "remind", time.Hour, nil)
+	fake.Advance(time.Hour)
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, failure) {
+			t.Errorf("expected %v got %v", failure, err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout: no error reported")
+	}
+	if records, _ := store.Load(ctx); len(records) != 1 {
+		t.Errorf("expected the failed timer kept, got %v", records)
+	}
+
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	fake.Advance(time.Minute)
+	clocktest.Eventually(t, clock.NewRealClock(), func() bool {
+		records, _ := store.Load(ctx)
+		return len(records) == 0
+	}, timeout, time.Millisecond)
+}
+
+func TestTimers_ErrorHandlerCancels(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFakeClock()
+	store := durable.NewMemoryStore()
+
+	var timers *durable.Timers
+	canceled := make(chan error, 1)
+	timers = durable.New(fake, store,
+		durable.WithErrorHandler(func(record durable.Record, err error) {
+			canceled <- timers.Cancel(ctx, record.ID)
+		}),
+	)
+	timers.Handle("remind", func(ctx context.Context, record durable.Record) error {
+		return errors.New("unavailable")
+	})
+
+	timers.After(ctx, "r1", "remind", time.Hour, nil)
+	fake.Advance(time.Hour)
+
+	select {
+	case err := <-canceled:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout: the error handler is blocked")
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := timers.Stop(stopCtx); err != nil {
+		t.Fatalf("the handler didn't return: %v", err)
+	}
+	if records, _ := store.Load(ctx); len(records) != 0 {
+		t.Errorf("expected the timer canceled, got %v", records)
+	}
+}
+
+func TestTimers_UnknownKind(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFakeClock()
+
+	errs := make(chan error, 1)
+	timers := durable.New(fake, durable.NewMemoryStore(),
+		durable.WithErrorHandler(func(record durable.Record, err error) { errs <- err }),
+	)
+	timers.After(ctx, "r1", "unknown", 0, nil)
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, durable.ErrUnknownKind) {
+			t.Errorf("expected %v got %v", durable.ErrUnknownKind, err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout: no error reported")
+	}
+}
+
+func TestTimers_Stopped(t *testing.T) {
+	ctx := context.Background()
+	timers := durable.New(clock.NewFakeClock(), durable.NewMemoryStore())
+	timers.Stop(ctx)
+
+	if err := timers.After(ctx, "r1", "remind", time.Hour, nil); !errors.Is(err, durable.ErrStopped) {
+		t.Errorf("expected %v got %v", durable.ErrStopped, err)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "timers.json")
+	store := durable.NewFileStore(path)
+
+	due := time.Unix(100, 0).UTC()
+	records := []durable.Record{
+		{ID: "a", Kind: "remind", Due: due, Payload: []byte("x")},
+		{ID: "b", Kind: "remind", Due: due.Add(time.Hour)},
+	}
+	for _, record := range records {
+		if err := store.Save(ctx, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := durable.NewFileStore(path).Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, records) {
+		t.Errorf("expected %v got %v", records, loaded)
+	}
+
+	if err := store.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, _ := store.Load(ctx); len(loaded) != 1 || loaded[0].ID != "b" {
+		t.Errorf("expected only b left, got %v", loaded)
+	}
+}
@@ -0,0 +1,22 @@
+package durable
+
+import "time"
+
+// An Option configures Timers.
+type Option func(*Timers)
+
+// WithRetryDelay sets how long to wait before firing again a timer whose
+// handler failed. The default is one minute.
+func WithRetryDelay(d time.Duration) Option {
+	return func(t *Timers) {
+		t.retry = d
+	}
+}
+
+// WithErrorHandler sets a function called with the timers whose handler
+// failed, and the error. By default, errors are discarded.
+func WithErrorHandler(f func(record Record, err error)) Option {
+	return func(t *Timers) {
+		t.onError = f
+	}
+}
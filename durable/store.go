@@ -0,0 +1,185 @@
+package durable
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// A Record is a timer persisted in a Store.
+type Record struct {
+	// ID identifies the timer. Scheduling a timer with the ID of another
+	// replaces it.
+	ID string `json:"id"`
+	// Kind names the handler called when the timer fires.
+	Kind string `json:"kind"`
+	// Due is when the timer fires.
+	Due time.Time `json:"due"`
+	// Payload is passed to the handler.
+	Payload []byte `json:"payload,omitempty"`
+}
+
+// A Store persists timers. Implementations backed by files, SQL databases or
+// key-value stores let timers survive process restarts.
+// Its methods may be called concurrently.
+type Store interface {
+	// Save inserts or replaces the record with the same ID.
+	Save(ctx context.Context, record Record) error
+	// Delete removes the record with the given ID, if any.
+	Delete(ctx context.Context, id string) error
+	// Load returns all the records.
+	Load(ctx context.Context) ([]Record, error)
+}
+
+// MemoryStore is a Store keeping records in memory, for tests.
+type MemoryStore struct {
+	mutex   sync.Mutex
+	records map[string]Record
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: map[string]Record{}}
+}
+
+func (store *MemoryStore) Save(ctx context.Context, record Record) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.records[record.ID] = record
+	return nil
+}
+
+func (store *MemoryStore) Delete(ctx context.Context, id string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.records, id)
+	return nil
+}
+
+func (store *MemoryStore) Load(ctx context.Context) ([]Record, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return sortedRecords(store.records), nil
+}
+
+// FileStore is a Store keeping records in a JSON file, rewritten atomically
+// on each change. It suits a modest number of timers owned by one process.
+type FileStore struct {
+	path string
+
+	mutex sync.Mutex
+}
+
+// NewFileStore returns a FileStore keeping records in the file at path.
+// The file is created on the first save.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (store *FileStore) Save(ctx context.Context, record Record) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	records, err := store.read()
+	if err != nil {
+		return err
+	}
+	records[record.ID] = record
+	return store.write(records)
+}
+
+func (store *FileStore) Delete(ctx context.Context, id string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	records, err := store.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := records[id]; !ok {
+		return nil
+	}
+	delete(records, id)
+	return store.write(records)
+}
+
+func (store *FileStore) Load(ctx context.Context) ([]Record, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	records, err := store.read()
+	if err != nil {
+		return nil, err
+	}
+	return sortedRecords(records), nil
+}
+
+func (store *FileStore) read() (map[string]Record, error) {
+	data, err := os.ReadFile(store.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]Record{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Record
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	records := make(map[string]Record, len(list))
+	for _, record := range list {
+		records[record.ID] = record
+	}
+	return records, nil
+}
+
+func (store *FileStore) write(records map[string]Record) error {
+	data, err := json.MarshalIndent(sortedRecords(records), "", "\t")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(store.path), filepath.Base(store.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), store.path)
+}
+
+// sortedRecords returns the records ordered by due time, then ID.
+func sortedRecords(records map[string]Record) []Record {
+	list := make([]Record, 0, len(records))
+	for _, record := range records {
+		list = append(list, record)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].Due.Equal(list[j].Due) {
+			return list[i].Due.Before(list[j].Due)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
@@ -0,0 +1,312 @@
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Day is 24 hours, ignoring daylight saving time transitions.
+	Day = 24 * time.Hour
+	// Week is 7 days.
+	Week = 7 * Day
+)
+
+var errDurationOverflow = errors.New("overflows time.Duration")
+
+// ParseDuration parses a duration like time.ParseDuration, also accepting
+// days ("d") and weeks ("w"), as in "3d12h" or "2w", and ISO 8601 durations,
+// as in "PT15M", "P1DT2H" or "P2W". A day is always 24 hours.
+//
+// ISO 8601 years and months are rejected, as their length varies.
+// Either form may be preceded by a sign.
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	var d time.Duration
+	var err error
+	if strings.HasPrefix(s, "P") {
+		d, err = parseISODuration(s[1:])
+	} else {
+		d, err = parseUnitDuration(s)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("clock: invalid duration %q: %s", orig, err)
+	}
+
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// parseUnitDuration parses an unsigned sequence of decimal numbers with units,
+// leaving the units of time.ParseDuration to it.
+func parseUnitDuration(s string) (time.Duration, error) {
+	if s == "0" {
+		return 0, nil
+	}
+	if s == "" {
+		return 0, errors.New("empty")
+	}
+
+	var total time.Duration
+	var rest strings.Builder
+	for s != "" {
+		number, unit, tail, err := nextComponent(s)
+		if err != nil {
+			return 0, err
+		}
+		s = tail
+
+		var scale time.Duration
+		switch unit {
+		case "d":
+			scale = Day
+		case "w":
+			scale = Week
+		case "":
+			return 0, fmt.Errorf("missing unit after %s", number)
+		default:
+			rest.WriteString(number)
+			rest.WriteString(unit)
+			continue
+		}
+
+		d, err := scaleDecimal(number, scale)
+		if err != nil {
+			return 0, err
+		}
+		if total, err = addDurations(total, d); err != nil {
+			return 0, err
+		}
+	}
+
+	if rest.Len() > 0 {
+		d, err := time.ParseDuration(rest.String())
+		if err != nil {
+			return 0, errors.New(strings.TrimPrefix(err.Error(), "time: "))
+		}
+		return addDurations(total, d)
+	}
+	return total, nil
+}
+
+// parseISODuration parses an ISO 8601 duration without its leading "P".
+func parseISODuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, errors.New("no components")
+	}
+
+	inTime := false
+	last := 0
+	var total time.Duration
+	for s != "" {
+		if s[0] == 'T' {
+			if inTime {
+				return 0, errors.New("repeated T")
+			}
+			inTime = true
+			s = s[1:]
+			if s == "" {
+				return 0, errors.New("no components after T")
+			}
+			continue
+		}
+
+		number, unit, tail, err := nextComponent(strings.Replace(s, ",", ".", 1))
+		if err != nil {
+			return 0, err
+		}
+		s = tail
+
+		// components must come in order, each at most once
+		var rank int
+		var scale time.Duration
+		switch {
+		case !inTime && (unit == "Y" || unit == "M"):
+			return 0, fmt.Errorf("%s%s: years and months have no fixed length", number, unit)
+		case !inTime && unit == "W":
+			rank, scale = 1, Week
+		case !inTime && unit == "D":
+			rank, scale = 2, Day
+		case inTime && unit == "H":
+			rank, scale = 3, time.Hour
+		case inTime && unit == "M":
+			rank, scale = 4, time.Minute
+		case inTime && unit == "S":
+			rank, scale = 5, time.Second
+		default:
+			return 0, fmt.Errorf("unexpected component %s%s", number, unit)
+		}
+		if rank <= last {
+			return 0, fmt.Errorf("component %s%s out of order", number, unit)
+		}
+		last = rank
+
+		d, err := scaleDecimal(number, scale)
+		if err != nil {
+			return 0, err
+		}
+		if total, err = addDurations(total, d); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// nextComponent splits a leading decimal number and its unit from s.
+func nextComponent(s string) (number, unit, rest string, err error) {
+	i := 0
+	for i < len(s) && (s[i] == '.' || '0' <= s[i] && s[i] <= '9') {
+		i++
+	}
+	if i == 0 {
+		return "", "", "", fmt.Errorf("expected a number at %q", s)
+	}
+
+	j := i
+	for j < len(s) && s[j] != '.' && (s[j] < '0' || s[j] > '9') && s[j] != 'T' {
+		j++
+	}
+	return s[:i], s[i:j], s[j:], nil
+}
+
+// scaleDecimal returns number, a decimal string, times scale.
+func scaleDecimal(number string, scale time.Duration) (time.Duration, error) {
+	whole, frac, _ := strings.Cut(number, ".")
+	if whole == "" && frac == "" || strings.Contains(frac, ".") {
+		return 0, fmt.Errorf("invalid number %q", number)
+	}
+
+	var d time.Duration
+	if whole != "" {
+		n, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || n > math.MaxInt64/int64(scale) {
+			return 0, errDurationOverflow
+		}
+		d = time.Duration(n) * scale
+	}
+
+	// add the fraction, one digit at a time
+	unit := float64(scale)
+	var f float64
+	for _, digit := range frac {
+		unit /= 10
+		f += float64(digit-'0') * unit
+	}
+	return addDurations(d, time.Duration(f+0.5))
+}
+
+func addDurations(a, b time.Duration) (time.Duration, error) {
+	if a > math.MaxInt64-b {
+		return 0, errDurationOverflow
+	}
+	return a + b, nil
+}
+
+// durationUnits are the units used by FormatDuration, largest first.
+var durationUnits = []struct {
+	d    time.Duration
+	name string
+	long string
+}{
+	{Day, "d", "day"},
+	{time.Hour, "h", "hour"},
+	{time.Minute, "m", "minute"},
+	{time.Second, "s", "second"},
+	{time.Millisecond, "ms", "millisecond"},
+	{time.Microsecond, "µs", "microsecond"},
+	{time.Nanosecond, "ns", "nanosecond"},
+}
+
+// FormatDuration formats d compactly for people, as its non-zero components
+// from days down to nanoseconds separated by spaces, such as "1h 3m" or
+// "2d 4h 30s". Unlike Duration.String, zero components are left out.
+// A zero duration is formatted as "0s".
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+
+	rest := magnitude(d)
+	var parts []string
+	for _, unit := range durationUnits {
+		if n := rest / uint64(unit.d); n > 0 {
+			rest -= n * uint64(unit.d)
+			parts = append(parts, strconv.FormatUint(n, 10)+unit.name)
+		}
+	}
+
+	s := strings.Join(parts, " ")
+	if d < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// FormatDurationApprox describes the magnitude of d for people, rounded to
+// its largest unit from days down to seconds, such as "about 2 hours",
+// "about a minute" or "3 days" when d is exact. Durations under a second are
+// "less than a second", and 0 is "no time".
+//
+// The sign of d is ignored, so the caller can say "in about 2 hours" or
+// "about 2 hours ago" as fits.
+func FormatDurationApprox(d time.Duration) string {
+	m := magnitude(d)
+	switch {
+	case m == 0:
+		return "no time"
+	case m < uint64(time.Second):
+		return "less than a second"
+	}
+
+	// the largest unit from days down to seconds that m reaches, moving up
+	// one if rounding reaches the next, so 59.5 minutes is about an hour
+	units := durationUnits[:4]
+	i := 0
+	for m < uint64(units[i].d) {
+		i++
+	}
+	size := uint64(units[i].d)
+	n := (m + size/2) / size
+	if i > 0 && n*size >= uint64(units[i-1].d) {
+		i--
+		size = uint64(units[i].d)
+		n = (m + size/2) / size
+	}
+	unit := units[i]
+
+	var phrase string
+	switch {
+	case n > 1:
+		phrase = strconv.FormatUint(n, 10) + " " + unit.long + "s"
+	case unit.d == time.Hour:
+		phrase = "an hour"
+	default:
+		phrase = "a " + unit.long
+	}
+	if n*size != m {
+		phrase = "about " + phrase
+	}
+	return phrase
+}
+
+// magnitude returns the absolute value of d, which doesn't overflow for the
+// minimum duration.
+func magnitude(d time.Duration) uint64 {
+	if d < 0 {
+		return -uint64(d)
+	}
+	return uint64(d)
+}
@@ -0,0 +1,120 @@
+package clock_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestParseDuration(t *testing.T) {
+	for _, test := range []struct {
+		s        string
+		expected time.Duration
+	}{
+		{"0", 0},
+		{"1h30m", 90 * time.Minute},
+		{"1.5s", 1500 * time.Millisecond},
+		{"300ms", 300 * time.Millisecond},
+		{"3d12h", 84 * time.Hour},
+		{"2w", 14 * clock.Day},
+		{"1w2d3h4m5s", clock.Week + 2*clock.Day + 3*time.Hour + 4*time.Minute + 5*time.Second},
+		{"12h3d", 84 * time.Hour},
+		{"1.5d", 36 * time.Hour},
+		{"-1d", -clock.Day},
+		{"+2d", 2 * clock.Day},
+		{"PT15M", 15 * time.Minute},
+		{"P1DT2H", 26 * time.Hour},
+		{"P2W", 2 * clock.Week},
+		{"P1D", clock.Day},
+		{"PT1H30M15S", time.Hour + 30*time.Minute + 15*time.Second},
+		{"PT0.5S", 500 * time.Millisecond},
+		{"PT0,5S", 500 * time.Millisecond},
+		{"PT1.5H", 90 * time.Minute},
+		{"-PT5M", -5 * time.Minute},
+	} {
+		d, err := clock.ParseDuration(test.s)
+		if err != nil {
+			t.Errorf("%q: %s", test.s, err)
+			continue
+		}
+		if d != test.expected {
+			t.Errorf("%q: expected %s got %s", test.s, test.expected, d)
+		}
+	}
+}
+
+func TestParseDuration_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"-",
+		"d",
+		"3",
+		"3x",
+		"1d-2h",
+		"1..5d",
+		"P",
+		"PT",
+		"P1Y",
+		"P1M",
+		"PT1D",
+		"P1H",
+		"PT1M1H",
+		"P1DT2HT3M",
+		"P1D1D",
+		"100000000w",
+	} {
+		if d, err := clock.ParseDuration(s); err == nil {
+			t.Errorf("%q: expected an error, got %s", s, d)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for _, test := range []struct {
+		d        time.Duration
+		expected string
+	}{
+		{0, "0s"},
+		{time.Hour + 3*time.Minute, "1h 3m"},
+		{2*clock.Day + 4*time.Hour, "2d 4h"},
+		{2*clock.Day + 30*time.Second, "2d 30s"},
+		{3 * clock.Week, "21d"},
+		{1500 * time.Millisecond, "1s 500ms"},
+		{time.Microsecond + 5, "1µs 5ns"},
+		{-90 * time.Minute, "-1h 30m"},
+		{math.MinInt64, "-106751d 23h 47m 16s 854ms 775µs 808ns"},
+	} {
+		if actual := clock.FormatDuration(test.d); actual != test.expected {
+			t.Errorf("%d: expected %q got %q", int64(test.d), test.expected, actual)
+		}
+	}
+}
+
+func TestFormatDurationApprox(t *testing.T) {
+	for _, test := range []struct {
+		d        time.Duration
+		expected string
+	}{
+		{0, "no time"},
+		{time.Millisecond, "less than a second"},
+		{time.Second, "a second"},
+		{1400 * time.Millisecond, "about a second"},
+		{45 * time.Second, "45 seconds"},
+		{time.Minute, "a minute"},
+		{100 * time.Second, "about 2 minutes"},
+		{59*time.Minute + 40*time.Second, "about an hour"},
+		{time.Hour, "an hour"},
+		{2*time.Hour + 10*time.Minute, "about 2 hours"},
+		{-2 * time.Hour, "2 hours"},
+		{14 * time.Hour, "14 hours"},
+		{23*time.Hour + 45*time.Minute, "about a day"},
+		{3 * clock.Day, "3 days"},
+		{10*clock.Day + 13*time.Hour, "about 11 days"},
+	} {
+		if actual := clock.FormatDurationApprox(test.d); actual != test.expected {
+			t.Errorf("%s: expected %q got %q", test.d, test.expected, actual)
+		}
+	}
+}
@@ -1,63 +1,209 @@
 package clock
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// TimerKind identifies the call that registered a PendingTimer.
+type TimerKind string
+
+const (
+	KindSleep     TimerKind = "Sleep"
+	KindAfter     TimerKind = "After"
+	KindTimer     TimerKind = "Timer"
+	KindAfterFunc TimerKind = "AfterFunc"
+	KindTicker    TimerKind = "Ticker"
+)
+
+// A PendingTimer describes a sleeper waiting on a fake clock.
+type PendingTimer struct {
+	// Kind is the call that registered the sleeper.
+	Kind TimerKind
+
+	// Duration is the duration requested by the call.
+	Duration time.Duration
+
+	// Deadline is the time at which the sleeper wakes.
+	Deadline time.Time
+
+	// Caller is the file:line of the code that made the call.
+	Caller string
+
+	// Goroutine is the id of the goroutine that made the call, or 0 if the
+	// clock doesn't record goroutines (see WithGoroutines).
+	Goroutine uint64
+
+	// Clock is the name of the clock (see WithName), if it has one.
+	Clock string
+
+	// Priority is the priority of the sleeper (see Prioritized).
+	Priority int
+
+	// Tag is the tag of the sleeper (see Tagged), if it has one.
+	Tag string
+}
+
+func (timer PendingTimer) String() string {
+	s := fmt.Sprintf("%s(%s) until %s from %s", timer.Kind, FormatDuration(timer.Duration), timer.Deadline, timer.Caller)
+	if timer.Goroutine != 0 {
+		s += fmt.Sprintf(" on goroutine %d", timer.Goroutine)
+	}
+	if timer.Tag != "" {
+		s += fmt.Sprintf(" tagged %q", timer.Tag)
+	}
+	if timer.Clock != "" {
+		s = fmt.Sprintf("[%s] %s", timer.Clock, s)
+	}
+	return s
+}
+
 type sleeper struct {
-	i     int
-	until time.Time
-	woke  bool
-	c     chan time.Time
-	f     func()
+	i      int
+	until  time.Time
+	woke   bool
+	c      chan time.Time
+	f      func()
+	kind   TimerKind
+	d      time.Duration
+	caller uintptr
+	goid   uint64
+	pooled bool
+	seq    uint64
+
+	// labels are given by the view of the clock that created the sleeper
+	labels
+
+	// steps is the sum of the clock's steps when until was computed, for
+	// deadlines computed before the sleeper is registered
+	steps time.Duration
+}
+
+// sleeperPool recycles the sleepers of Sleep and After, which are referenced
+// only by the clock, so they can be reused as soon as they wake.
+var sleeperPool = sync.Pool{
+	New: func() interface{} { return new(sleeper) },
 }
 
-func (s *sleeper) wake() {
+// sleepChanPool recycles the channels of Sleep, which never escape the call
+// and are drained before being put back.
+var sleepChanPool = sync.Pool{
+	New: func() interface{} { return make(chan time.Time, 1) },
+}
+
+// wake sends the time on the sleeper's channel, if any, and returns its
+// function, if any, which the clock calls once it releases its mutex.
+func (s *sleeper) wake() func() {
 	if s.woke {
-		return
+		return nil
 	}
 	s.woke = true
 
-	// if c is set, send the current time
+	// the channel is buffered and drained when rearmed, so the send
+	// never blocks while the clock holds its mutex
 	if s.c != nil {
-		s.c <- s.until
+		select {
+		case s.c <- s.until:
+		default:
+		}
 	}
 
-	// if f is set, call it in separate goroutine
-	if s.f != nil {
-		s.f()
-	}
+	return s.f
 }
 
 type blocker struct {
 	n    int
+	tag  string
 	done chan struct{}
 }
 
 type fakeClock struct {
+	// now mirrors at, so Now and Since don't contend with scheduling.
+	// Scheduling itself, from After to NewTimer and Sleep, serializes on
+	// mutex: Advance, BlockUntil and the wake order of equal deadlines
+	// need one consistent view of all the sleepers, so sharding them would
+	// only move the locking of every shard to those calls
+	now atomic.Value
+
 	mutex    sync.RWMutex
 	at       time.Time
 	sleepers []*sleeper
 	blockers []blocker
+	wakeups  []func()
+	seq      uint64
+
+	// fired and stopped count the sleepers woken and stopped, for Stats
+	fired   int
+	stopped int
+
+	// advancer serializes the calls that move the time, in the order they
+	// are made: Advance, SetTime, Suspend, and the steps of the playback
+	advancer chan struct{}
+
+	// origin is the time the monotonic readings of NowBoth count from
+	origin time.Time
+
+	// steps is the sum of the steps made by SetTime and Suspend, which
+	// watchers are notified of
+	steps    time.Duration
+	watchers []*clockWatcher
+
+	// player guards the playback started by Play, at playRate, which
+	// stops once playStop is closed, then closes playDone
+	player   sync.Mutex
+	playRate float64
+	playStop chan struct{}
+	playDone chan struct{}
+
+	boundary   Boundary
+	strict     bool
+	maxAdvance time.Duration
+	coalescing time.Duration
+	onPanic    func(*CallbackPanic)
+	rand       *rand.Rand
+	executor   *executor
+	order      CallbackOrder
+	advancing  bool
+	noCallers  bool
+	goroutines bool
+	label      string
+
+	// maxPending caps the pending sleepers, if positive, and overLimit is
+	// the first registration over it since the clock was last unlocked
+	maxPending int
+	onLimit    func(*PendingLimitError)
+	overLimit  *PendingLimitError
 }
 
-func NewFakeClock() FakeClock {
-	return NewFakeClockAt(time.Unix(1, 0))
+func NewFakeClock(opts ...FakeOption) FakeClock {
+	return NewFakeClockAt(time.Unix(1, 0), opts...)
 }
 
-func NewFakeClockAt(at time.Time) FakeClock {
-	return &fakeClock{
-		at: at,
+func NewFakeClockAt(at time.Time, opts ...FakeOption) FakeClock {
+	clock := &fakeClock{
+		at:       at,
+		origin:   at,
+		advancer: make(chan struct{}, 1),
 	}
+	clock.now.Store(at)
+
+	for _, opt := range opts {
+		opt(clock)
+	}
+
+	return clock
 }
 
 func (clock *fakeClock) Now() time.Time {
-	clock.mutex.RLock()
-	defer clock.mutex.RUnlock()
-
-	return clock.at
+	return clock.now.Load().(time.Time)
 }
 
 func (clock *fakeClock) Since(t time.Time) time.Duration {
@@ -65,36 +211,74 @@ func (clock *fakeClock) Since(t time.Time) time.Duration {
 }
 
 func (clock *fakeClock) Sleep(d time.Duration) {
-	<-clock.After(d)
+	clock.sleep(d, clock.caller(1), labels{})
 }
 
-func (clock *fakeClock) After(d time.Duration) <-chan time.Time {
-	clock.mutex.Lock()
-	defer clock.mutex.Unlock()
-
-	if d < 0 {
-		d = 0
+func (clock *fakeClock) sleep(d time.Duration, caller uintptr, l labels) {
+	// like time.Sleep, a sleep that's already over yields the processor
+	if d <= 0 && clock.boundary == Inclusive {
+		runtime.Gosched()
+		return
 	}
 
+	c := sleepChanPool.Get().(chan time.Time)
+	clock.after(d, c, KindSleep, caller, l)
+	<-c
+	sleepChanPool.Put(c)
+}
+
+func (clock *fakeClock) After(d time.Duration) <-chan time.Time {
 	c := make(chan time.Time, 1)
-	clock.appendSleeper(&sleeper{
-		until: clock.at.Add(d),
-		c:     c,
-	})
+	clock.after(d, c, KindAfter, clock.caller(1), labels{})
 	return c
 }
 
-func (clock *fakeClock) AfterFunc(d time.Duration, f func()) Timer {
+func (clock *fakeClock) after(d time.Duration, c chan time.Time, kind TimerKind, caller uintptr, l labels) {
+	// a sleeper that's already due wakes without being scheduled
+	if d <= 0 && clock.boundary == Inclusive {
+		c <- clock.Now()
+		return
+	}
+
+	s := sleeperPool.Get().(*sleeper)
+	*s = sleeper{
+		c:      c,
+		kind:   kind,
+		d:      d,
+		caller: caller,
+		goid:   clock.goroutine(),
+		labels: l,
+		pooled: true,
+	}
+
 	clock.mutex.Lock()
-	defer clock.mutex.Unlock()
+	defer clock.unlock()
+
+	s.until = clock.at.Add(d)
+	clock.appendSleeper(s)
+}
 
+func (clock *fakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	return clock.afterFunc(d, f, clock.caller(1), labels{})
+}
+
+func (clock *fakeClock) afterFunc(d time.Duration, f func(), pc uintptr, l labels) Timer {
 	timer := &fakeTimer{
 		clock: clock,
 		sleeper: sleeper{
-			until: clock.at.Add(d),
-			f:     func() { go f() },
+			f:      clock.callback(f, pc),
+			kind:   KindAfterFunc,
+			d:      d,
+			caller: pc,
+			goid:   clock.goroutine(),
+			labels: l,
 		},
 	}
+
+	clock.mutex.Lock()
+	defer clock.unlock()
+
+	timer.sleeper.until = clock.at.Add(d)
 	clock.appendSleeper(&timer.sleeper)
 
 	return timer
@@ -107,12 +291,24 @@ type fakeTimer struct {
 }
 
 func (clock *fakeClock) NewTimer(d time.Duration) Timer {
+	return clock.newTimer(d, clock.caller(1), labels{})
+}
+
+func (clock *fakeClock) newTimer(d time.Duration, pc uintptr, l labels) Timer {
+	now, steps := clock.reading()
+
 	return &fakeTimer{
 		clock: clock,
 		sleeper: sleeper{
-			i:     -1,
-			until: clock.Now().Add(d),
-			c:     make(chan time.Time, 1),
+			i:      -1,
+			until:  now.Add(d),
+			c:      make(chan time.Time, 1),
+			kind:   KindTimer,
+			d:      d,
+			caller: pc,
+			goid:   clock.goroutine(),
+			labels: l,
+			steps:  steps,
 		},
 	}
 }
@@ -121,11 +317,12 @@ func (timer *fakeTimer) C() <-chan time.Time {
 	clock := timer.clock
 
 	clock.mutex.Lock()
-	defer clock.mutex.Unlock()
+	defer clock.unlock()
 
 	sleeper := &timer.sleeper
 
-	if sleeper.i < 0 {
+	if !timer.stopped && !sleeper.woke && sleeper.i < 0 {
+		clock.rebase(sleeper)
 		clock.appendSleeper(sleeper)
 	}
 
@@ -136,45 +333,92 @@ func (timer *fakeTimer) Stop() bool {
 	clock := timer.clock
 
 	clock.mutex.Lock()
-	defer clock.mutex.Unlock()
+	defer clock.unlock()
 
-	defer func() { timer.stopped = true }()
-	if timer.stopped {
-		return false
-	}
+	return timer.stop()
+}
 
-	sleeper := &timer.sleeper
+// stop stops the timer. The caller holds the clock's mutex.
+func (timer *fakeTimer) stop() bool {
+	timer.settle()
+	active := timer.active()
 
-	if clock.removeSleeper(sleeper) {
-		return true
+	timer.stopped = true
+	timer.clock.removeSleeper(&timer.sleeper)
+	if active {
+		timer.clock.stopped++
 	}
 
-	return sleeper.until.After(clock.at)
+	return active
 }
 
 func (timer *fakeTimer) Reset(d time.Duration) bool {
 	clock := timer.clock
 
 	clock.mutex.Lock()
-	defer clock.mutex.Unlock()
+	defer clock.unlock()
 
 	sleeper := &timer.sleeper
 
+	active := timer.active()
+	clock.removeSleeper(sleeper)
+
 	if d < 0 {
 		d = 0
 	}
 
-	sleeper.until = timer.clock.at.Add(d)
+	timer.stopped = false
+	sleeper.until = clock.at.Add(d)
+	sleeper.d = d
 	sleeper.woke = false
-	sleeper.c = make(chan time.Time, 1)
-
-	defer func() {
-		if sleeper.f != nil {
-			clock.appendSleeper(&timer.sleeper)
+	sleeper.steps = clock.steps
+
+	// keep the channel, like time.Timer, but drain a value sent before
+	// the reset so it isn't mistaken for the new expiry
+	if sleeper.c != nil {
+		select {
+		case <-sleeper.c:
+		default:
 		}
-	}()
+	}
+
+	// rearm right away, like time.Timer, rather than when C is next called
+	clock.appendSleeper(sleeper)
 
-	return clock.removeSleeper(sleeper)
+	return active
+}
+
+// active reports whether the timer is armed and hasn't fired yet.
+// A timer whose C was never called isn't registered with the clock,
+// so whether it fired depends on its deadline instead.
+func (timer *fakeTimer) active() bool {
+	sleeper := &timer.sleeper
+
+	switch {
+	case timer.stopped || sleeper.woke:
+		return false
+	case sleeper.i >= 0:
+		return true
+	default:
+		timer.clock.rebase(sleeper)
+		return sleeper.until.After(timer.clock.at)
+	}
+}
+
+// settle fires a timer whose C was never called if its deadline passed,
+// so that it counts as fired, with its value waiting on the channel,
+// as if C had been called when it was created.
+func (timer *fakeTimer) settle() {
+	sleeper := &timer.sleeper
+
+	if timer.stopped || sleeper.woke || sleeper.i >= 0 {
+		return
+	}
+
+	timer.clock.rebase(sleeper)
+	if !sleeper.until.After(timer.clock.at) {
+		timer.clock.appendSleeper(sleeper)
+	}
 }
 
 type fakeTicker struct {
@@ -183,22 +427,38 @@ type fakeTicker struct {
 	next     time.Time
 	stopped  bool
 	sleeper  *sleeper
+	caller   uintptr
+	goid     uint64
+	labels   labels
+
+	// steps is the sum of the clock's steps when next was computed
+	steps time.Duration
 }
 
 var errNonPositiveInterval = errors.New("non-positive interval for NewTicker")
 
 func (clock *fakeClock) NewTicker(d time.Duration) Ticker {
+	return clock.newTicker(d, clock.caller(1), labels{})
+}
+
+func (clock *fakeClock) newTicker(d time.Duration, pc uintptr, l labels) Ticker {
 	if d <= 0 {
 		panic(errNonPositiveInterval)
 	}
 
+	now, steps := clock.reading()
+
 	return &fakeTicker{
 		clock:    clock,
 		interval: d,
-		next:     clock.Now().Add(d),
+		next:     now.Add(d),
 		sleeper: &sleeper{
 			i: -1,
 		},
+		caller: pc,
+		goid:   clock.goroutine(),
+		labels: l,
+		steps:  steps,
 	}
 }
 
@@ -206,17 +466,24 @@ func (ticker *fakeTicker) C() <-chan time.Time {
 	clock := ticker.clock
 
 	clock.mutex.Lock()
-	defer clock.mutex.Unlock()
+	defer clock.unlock()
 
 	c := make(chan time.Time, 1)
 	if ticker.stopped {
 		return c
 	}
 
-	ticker.sleeper = &sleeper{
+	ticker.next = ticker.next.Add(clock.steps - ticker.steps)
+	ticker.steps = clock.steps
 
-		until: ticker.next,
-		c:     c,
+	ticker.sleeper = &sleeper{
+		until:  ticker.next,
+		c:      c,
+		kind:   KindTicker,
+		d:      ticker.interval,
+		caller: ticker.caller,
+		goid:   ticker.goid,
+		labels: ticker.labels,
 	}
 	clock.appendSleeper(ticker.sleeper)
 	ticker.next = ticker.next.Add(ticker.interval)
@@ -231,14 +498,34 @@ func (ticker *fakeTicker) Stop() {
 	defer clock.mutex.Unlock()
 
 	ticker.stopped = true
-	clock.removeSleeper(ticker.sleeper)
+	if clock.removeSleeper(ticker.sleeper) {
+		clock.stopped++
+	}
 }
 
 func (ticker *fakeTicker) Reset(d time.Duration) {
-	ticker.Stop()
+	clock := ticker.clock
+
+	clock.mutex.Lock()
+	defer clock.unlock()
+
+	sleeper := ticker.sleeper
+	clock.removeSleeper(sleeper)
+
 	ticker.stopped = false
 	ticker.interval = d
-	ticker.sleeper.until = ticker.clock.Now().Add(d)
+	ticker.next = clock.at.Add(d)
+	ticker.steps = clock.steps
+
+	// rearm the channel last returned by C, like time.Ticker, unless it
+	// already holds a tick, in which case the next call to C picks up
+	// the new schedule
+	if sleeper.c != nil && !sleeper.woke {
+		sleeper.until = ticker.next
+		sleeper.d = d
+		clock.appendSleeper(sleeper)
+		ticker.next = ticker.next.Add(d)
+	}
 }
 
 func (clock *fakeClock) Tick(d time.Duration) func() <-chan time.Time {
@@ -249,31 +536,182 @@ func (clock *fakeClock) Tick(d time.Duration) func() <-chan time.Time {
 	return clock.NewTicker(d).C
 }
 
-func (clock *fakeClock) Advance(d time.Duration) {
+var (
+	// ErrNegativeAdvance is returned by Advance when given a negative duration.
+	ErrNegativeAdvance = errors.New("clock: negative duration for Advance")
+
+	// ErrNothingScheduled is returned by Advance on a strict clock when no
+	// sleeper is pending.
+	ErrNothingScheduled = errors.New("clock: nothing scheduled for Advance")
+
+	// ErrAdvanceTooFar is returned by Advance on a clock built with
+	// WithMaxAdvance when given a duration over the limit.
+	ErrAdvanceTooFar = errors.New("clock: duration over the limit for Advance")
+)
+
+func (clock *fakeClock) Advance(d time.Duration) error {
+	defer clock.turn()()
+
 	clock.mutex.Lock()
-	defer clock.mutex.Unlock()
+	defer clock.unlock()
 
 	// time travel is not allowed
-	if d <= 0 {
-		return
+	if d < 0 {
+		return fmt.Errorf("%w: %s%s", ErrNegativeAdvance, d, clock.on())
+	}
+
+	if clock.maxAdvance > 0 && d > clock.maxAdvance {
+		return fmt.Errorf("%w: %s over %s%s", ErrAdvanceTooFar, d, clock.maxAdvance, clock.on())
+	}
+
+	if clock.strict && len(clock.sleepers) == 0 {
+		return fmt.Errorf("%w: %s%s", ErrNothingScheduled, d, clock.on())
+	}
+
+	clock.advance(d)
+	return nil
+}
+
+// coalesce returns the time a sleeper due at t wakes under WithCoalescing.
+// The grid is counted from the origin of the monotonic readings, which
+// moves with the steps of the clock, as the deadlines do.
+func (clock *fakeClock) coalesce(t time.Time) time.Time {
+	if clock.coalescing <= 0 {
+		return t
+	}
+	if r := t.Sub(clock.origin) % clock.coalescing; r > 0 {
+		t = t.Add(clock.coalescing - r)
+	} else if r < 0 {
+		t = t.Add(-r)
 	}
+	return t
+}
+
+// turn waits until the calls moving the time made before are done, including
+// the wakeups they trigger, and returns a function ending the turn of the
+// caller. Goroutines blocked on a channel are queued in order, so the turns
+// are taken in the order they were asked for.
+func (clock *fakeClock) turn() func() {
+	clock.advancer <- struct{}{}
+	return func() { <-clock.advancer }
+}
 
+// advance moves the clock forward by d, waking the sleepers due.
+// The caller holds the clock's mutex.
+func (clock *fakeClock) advance(d time.Duration) {
 	clock.at = clock.at.Add(d)
+	clock.now.Store(clock.at)
 	clock.checkSleepers()
 }
 
+func (clock *fakeClock) SetTime(t time.Time) {
+	defer clock.turn()()
+
+	clock.mutex.Lock()
+	defer clock.unlock()
+
+	clock.step(t)
+}
+
+func (clock *fakeClock) Suspend(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+
+	defer clock.turn()()
+
+	clock.mutex.Lock()
+	defer clock.unlock()
+
+	clock.step(clock.at.Add(d))
+
+	suspension := Suspension{Resumed: clock.at, Duration: d}
+	for _, watcher := range clock.watchers {
+		if f := watcher.suspend; f != nil {
+			clock.wakeups = append(clock.wakeups, func() { f(suspension) })
+		}
+	}
+}
+
+// step sets the wall time of the clock to t, keeping the time left on
+// sleepers. The caller holds the clock's mutex.
+func (clock *fakeClock) step(t time.Time) {
+	from := clock.at
+	step := t.Sub(from)
+
+	clock.at = t
+	clock.now.Store(t)
+	clock.origin = clock.origin.Add(step)
+	clock.steps += step
+
+	// sleepers wait for durations, so they keep the time they have left
+	for _, sleeper := range clock.sleepers {
+		sleeper.until = sleeper.until.Add(step)
+	}
+
+	for _, watcher := range clock.watchers {
+		if f := watcher.step; f != nil {
+			clock.wakeups = append(clock.wakeups, func() { f(from, t) })
+		}
+	}
+}
+
+// clockWatcher is notified of the steps and suspensions of a fake clock.
+type clockWatcher struct {
+	step    func(from, to time.Time)
+	suspend func(Suspension)
+}
+
+// watchSteps calls f with the times before and after each step of the clock
+// by SetTime or Suspend, once the clock's mutex is released. It returns a
+// function that stops the calls.
+func (clock *fakeClock) watchSteps(f func(from, to time.Time)) func() {
+	return clock.watch(&clockWatcher{step: f})
+}
+
+// watchSuspends calls f with each suspension of the clock by Suspend, once
+// the clock's mutex is released. It returns a function that stops the calls.
+func (clock *fakeClock) watchSuspends(f func(Suspension)) func() {
+	return clock.watch(&clockWatcher{suspend: f})
+}
+
+func (clock *fakeClock) watch(watcher *clockWatcher) func() {
+	clock.mutex.Lock()
+	clock.watchers = append(clock.watchers, watcher)
+	clock.mutex.Unlock()
+
+	return func() {
+		clock.mutex.Lock()
+		defer clock.mutex.Unlock()
+
+		for i, w := range clock.watchers {
+			if w == watcher {
+				clock.watchers = append(clock.watchers[:i], clock.watchers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
 func (clock *fakeClock) Until(n int) <-chan struct{} {
+	return clock.until("", n)
+}
+
+// until returns a channel closed once n sleepers with tag, or any sleepers
+// if tag is empty, are waiting on the clock.
+func (clock *fakeClock) until(tag string, n int) <-chan struct{} {
 	clock.mutex.Lock()
 	defer clock.mutex.Unlock()
 
 	done := make(chan struct{})
-	if len(clock.sleepers) >= n {
+	if clock.waiting(tag) >= n {
 		close(done)
 		return done
 	}
 
 	clock.appendBlocker(blocker{
 		n:    n,
+		tag:  tag,
 		done: done,
 	})
 	return done
@@ -283,15 +721,173 @@ func (clock *fakeClock) BlockUntil(n int) {
 	<-clock.Until(n)
 }
 
+func (clock *fakeClock) PendingTimers() []PendingTimer {
+	clock.mutex.RLock()
+	defer clock.mutex.RUnlock()
+
+	timers := make([]PendingTimer, 0, len(clock.sleepers))
+	for _, sleeper := range clock.sleepers {
+		timers = append(timers, clock.pendingTimer(sleeper))
+	}
+
+	sort.SliceStable(timers, func(i, j int) bool {
+		return timers[i].Deadline.Before(timers[j].Deadline)
+	})
+	return timers
+}
+
+// pendingTimer describes s. The caller holds the clock's mutex.
+func (clock *fakeClock) pendingTimer(s *sleeper) PendingTimer {
+	return PendingTimer{
+		Kind:      s.kind,
+		Duration:  s.d,
+		Deadline:  s.until,
+		Caller:    callerString(s.caller),
+		Goroutine: s.goid,
+		Clock:     clock.label,
+		Priority:  s.priority,
+		Tag:       s.tag,
+	}
+}
+
+func (clock *fakeClock) NextDeadline() (time.Time, bool) {
+	clock.mutex.RLock()
+	defer clock.mutex.RUnlock()
+
+	if len(clock.sleepers) == 0 {
+		return time.Time{}, false
+	}
+
+	next := clock.sleepers[0].until
+	for _, sleeper := range clock.sleepers[1:] {
+		if sleeper.until.Before(next) {
+			next = sleeper.until
+		}
+	}
+	return next, true
+}
+
+// reading returns the time of the clock, and the sum of its steps, which
+// deadlines computed from the time are rebased by once they're registered.
+func (clock *fakeClock) reading() (time.Time, time.Duration) {
+	clock.mutex.RLock()
+	defer clock.mutex.RUnlock()
+
+	return clock.at, clock.steps
+}
+
+// rebase shifts the deadline of a sleeper computed before the clock was
+// stepped by the steps made since. The caller holds the clock's mutex.
+func (clock *fakeClock) rebase(s *sleeper) {
+	if s.steps != clock.steps {
+		s.until = s.until.Add(clock.steps - s.steps)
+		s.steps = clock.steps
+	}
+}
+
+// caller returns the program counter of the caller skip frames above its
+// own caller, or 0 if the clock doesn't record callers.
+func (clock *fakeClock) caller(skip int) uintptr {
+	if clock.noCallers {
+		return 0
+	}
+	return caller(skip + 1)
+}
+
+// caller returns the program counter of the caller skip frames above its
+// own caller. It's resolved to a file:line only when needed, by callerString,
+// since most sleepers are never inspected.
+func caller(skip int) uintptr {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+2, pcs[:]) == 0 {
+		return 0
+	}
+	return pcs[0]
+}
+
+// goroutine returns the id of the calling goroutine, or 0 if the clock
+// doesn't record goroutines.
+func (clock *fakeClock) goroutine() uint64 {
+	if !clock.goroutines {
+		return 0
+	}
+	return goroutineID()
+}
+
+// goroutineID returns the id of the calling goroutine, parsed from the
+// header of its stack trace, "goroutine 42 [running]:", since the runtime
+// doesn't expose it otherwise.
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	if i := bytes.IndexByte(header, ' '); i > 0 {
+		header = header[:i]
+	}
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+	return id
+}
+
+func callerString(pc uintptr) string {
+	if pc == 0 {
+		return "unknown"
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.File == "" {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+}
+
+// unlock releases the mutex, then calls the functions of the sleepers woken
+// while it was held, so they may use the clock without deadlocking.
+func (clock *fakeClock) unlock() {
+	wakeups := clock.wakeups
+	clock.wakeups = nil
+	overLimit := clock.overLimit
+	clock.overLimit = nil
+	clock.mutex.Unlock()
+
+	for _, f := range wakeups {
+		f()
+	}
+	if overLimit != nil {
+		clock.reportLimit(overLimit)
+	}
+}
+
+// due reports whether a sleeper's deadline is reached, according to the
+// clock's Boundary.
+func (clock *fakeClock) due(s *sleeper) bool {
+	if clock.boundary == Exclusive && !clock.advancing {
+		return s.until.Before(clock.at)
+	}
+	return !clock.at.Before(s.until)
+}
+
 func (clock *fakeClock) appendSleeper(s *sleeper) {
-	if !clock.at.Before(s.until) {
+	s.until = clock.coalesce(s.until)
+	if clock.due(s) {
 		s.i = -1
-		s.wake()
+		if !s.woke {
+			clock.fired++
+		}
+		if f := s.wake(); f != nil {
+			clock.wakeups = append(clock.wakeups, f)
+		}
+		if s.pooled {
+			*s = sleeper{}
+			sleeperPool.Put(s)
+		}
 		return
 	}
 
 	s.i = len(clock.sleepers)
+	s.seq = clock.seq
+	clock.seq++
 	clock.sleepers = append(clock.sleepers, s)
+	clock.checkLimit(s)
 	clock.checkBlockers()
 }
 
@@ -317,11 +913,65 @@ func (clock *fakeClock) removeSleeper(s *sleeper) bool {
 }
 
 func (clock *fakeClock) checkSleepers() {
+	clock.advancing = true
+	defer func() { clock.advancing = false }()
+
 	oldSleepers := clock.sleepers
 	clock.sleepers = clock.sleepers[:0]
+
+	var due []*sleeper
 	for _, sleeper := range oldSleepers {
+		if clock.due(sleeper) {
+			due = append(due, sleeper)
+			continue
+		}
+		sleeper.i = len(clock.sleepers)
+		clock.sleepers = append(clock.sleepers, sleeper)
+	}
+	for i := len(clock.sleepers); i < len(oldSleepers); i++ {
+		oldSleepers[i] = nil
+	}
+
+	// wake sleepers in deadline order, then by priority, then in the order
+	// they were scheduled, or in random order if enabled, or in the order
+	// they were scheduled only
+	sort.Slice(due, func(i, j int) bool {
+		switch {
+		case clock.order == RegistrationOrder:
+			return due[i].seq < due[j].seq
+		case !due[i].until.Equal(due[j].until):
+			return due[i].until.Before(due[j].until)
+		case due[i].priority != due[j].priority:
+			return due[i].priority > due[j].priority
+		}
+		return due[i].seq < due[j].seq
+	})
+	if clock.rand != nil && clock.order == DeadlineOrder {
+		clock.shuffleTies(due)
+	}
+
+	for _, sleeper := range due {
 		clock.appendSleeper(sleeper)
 	}
+	clock.checkBlockers()
+}
+
+// shuffleTies shuffles each run of sleepers with equal deadlines and
+// priorities.
+func (clock *fakeClock) shuffleTies(sleepers []*sleeper) {
+	for start := 0; start < len(sleepers); {
+		end := start + 1
+		for end < len(sleepers) && sleepers[end].until.Equal(sleepers[start].until) &&
+			sleepers[end].priority == sleepers[start].priority {
+			end++
+		}
+
+		ties := sleepers[start:end]
+		clock.rand.Shuffle(len(ties), func(i, j int) {
+			ties[i], ties[j] = ties[j], ties[i]
+		})
+		start = end
+	}
 }
 
 func (clock *fakeClock) appendBlocker(b blocker) {
@@ -331,7 +981,7 @@ func (clock *fakeClock) appendBlocker(b blocker) {
 func (clock *fakeClock) checkBlockers() {
 	n := 0
 	for _, blocker := range clock.blockers {
-		if len(clock.sleepers) < blocker.n {
+		if clock.waiting(blocker.tag) < blocker.n {
 			clock.blockers[n] = blocker
 			n++
 		} else {
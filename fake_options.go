@@ -0,0 +1,123 @@
+package clock
+
+import (
+	"math/rand"
+	"time"
+)
+
+// A FakeOption configures a fake clock.
+type FakeOption func(*fakeClock)
+
+// Boundary selects when a fake clock wakes a sleeper whose deadline is
+// exactly the current time.
+type Boundary int
+
+const (
+	// Inclusive wakes a sleeper as soon as its deadline is reached,
+	// even when it's registered with a deadline equal to the current time,
+	// such as After(0): the sleeper wakes before the call returns.
+	Inclusive Boundary = iota
+
+	// Exclusive treats the current time as already processed: a sleeper
+	// registered with a deadline equal to the current time waits until the
+	// clock is next advanced, as a real timer fires only after its call
+	// returns. Advance(0) wakes such sleepers without moving the clock.
+	//
+	// Under Exclusive, Sleep(0) blocks until the clock is advanced.
+	Exclusive
+)
+
+// WithBoundary sets when sleepers due at the current time wake.
+// The default is Inclusive.
+func WithBoundary(boundary Boundary) FakeOption {
+	return func(clock *fakeClock) {
+		clock.boundary = boundary
+	}
+}
+
+// WithStrict makes Advance fail with ErrNothingScheduled, leaving the clock
+// unchanged, when no sleeper is pending. This catches tests that advance the
+// clock before the code under test has armed its timers, instead of waiting
+// with BlockUntil.
+func WithStrict() FakeOption {
+	return func(clock *fakeClock) {
+		clock.strict = true
+	}
+}
+
+// WithMaxAdvance makes Advance fail with ErrAdvanceTooFar, leaving the
+// clock unchanged, when given a duration over max. This catches unit typos,
+// such as Advance(30*time.Minute) where seconds were meant, which would
+// otherwise fast-forward the whole scenario. Play isn't limited.
+func WithMaxAdvance(max time.Duration) FakeOption {
+	return func(clock *fakeClock) {
+		clock.maxAdvance = max
+	}
+}
+
+// WithCoalescing makes the clock wake sleepers at the first multiple of
+// window, counted from its creation, on or after their deadline, like an
+// operating system coalescing timers to save power: sleepers due within the
+// same window wake together, up to window late. The values they send are the
+// times they wake. Code written to tolerate coalescing can then be tested
+// under it, instead of only with timers firing on time.
+func WithCoalescing(window time.Duration) FakeOption {
+	return func(clock *fakeClock) {
+		clock.coalescing = window
+	}
+}
+
+// WithPanicHandler makes AfterFunc callbacks recover from panics, reporting
+// them to handler with the site of the call to AfterFunc, instead of crashing
+// the test binary.
+func WithPanicHandler(handler func(*CallbackPanic)) FakeOption {
+	return func(clock *fakeClock) {
+		clock.onPanic = handler
+	}
+}
+
+// WithRandomOrder makes the clock wake sleepers with equal deadlines in an
+// order drawn from seed, instead of the order they were scheduled in,
+// to shake out tests that depend on that order. Sleepers with different
+// deadlines still wake in deadline order. See clocktest.RandomOrder.
+func WithRandomOrder(seed int64) FakeOption {
+	return func(clock *fakeClock) {
+		clock.rand = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithoutCallers makes the clock skip recording the caller of each sleeper,
+// saving a stack walk per call. PendingTimers and panics reported to
+// WithPanicHandler then give "unknown" callers.
+func WithoutCallers() FakeOption {
+	return func(clock *fakeClock) {
+		clock.noCallers = true
+	}
+}
+
+// WithGoroutines makes the clock record the id of the goroutine that
+// registers each sleeper, alongside its caller, for PendingTimers. It costs
+// a stack trace per call, so it's off by default.
+func WithGoroutines() FakeOption {
+	return func(clock *fakeClock) {
+		clock.goroutines = true
+	}
+}
+
+// WithCapacity makes the clock reserve room for n pending sleepers and
+// n goroutines waiting in Until, so it doesn't allocate to track them until
+// there are more.
+func WithCapacity(n int) FakeOption {
+	return func(clock *fakeClock) {
+		clock.sleepers = make([]*sleeper, 0, n)
+		clock.blockers = make([]blocker, 0, n)
+	}
+}
+
+// NewConstrainedFakeClock returns a fake clock for constrained targets,
+// such as firmware built with TinyGo: it starts at the same time as
+// NewFakeClock, reserves room for capacity pending sleepers, and doesn't
+// record their callers. Further options apply after these.
+func NewConstrainedFakeClock(capacity int, opts ...FakeOption) FakeClock {
+	return NewFakeClock(append([]FakeOption{WithCapacity(capacity), WithoutCallers()}, opts...)...)
+}
@@ -0,0 +1,236 @@
+package clock_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestWithBoundary_Inclusive(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start, clock.WithBoundary(clock.Inclusive))
+
+	assertSent(t, start, fake.After(0))
+
+	timer := fake.NewTimer(0)
+	assertSent(t, start, timer.C())
+}
+
+func TestWithBoundary_Exclusive(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start, clock.WithBoundary(clock.Exclusive))
+
+	// sleepers due now wait for the clock to be advanced
+	after := fake.After(0)
+	timer := fake.NewTimer(0)
+	c := timer.C()
+	assertNotSent(t, after)
+	assertNotSent(t, c)
+
+	fake.Advance(0)
+	assertSent(t, start, after)
+	assertSent(t, start, c)
+
+	// sleepers reached by Advance wake as usual
+	after = fake.After(1 * time.Second)
+	fake.Advance(1 * time.Second)
+	assertSent(t, start.Add(1*time.Second), after)
+
+	woke := make(chan struct{})
+	go func() {
+		defer close(woke)
+		fake.Sleep(0)
+	}()
+
+	assertClockUntil(t, 1, fake)
+	assertNotClosed(t, woke)
+	fake.Advance(0)
+	assertClosed(t, woke)
+}
+
+func TestWithStrict(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start, clock.WithStrict())
+
+	if err := fake.Advance(1 * time.Second); !errors.Is(err, clock.ErrNothingScheduled) {
+		t.Errorf("expected %v got %v", clock.ErrNothingScheduled, err)
+	}
+	assertClockAt(t, start, fake)
+
+	after := fake.After(1 * time.Second)
+	if err := fake.Advance(1 * time.Second); err != nil {
+		t.Errorf("expected nil got %v", err)
+	}
+	assertSent(t, start.Add(1*time.Second), after)
+}
+
+func TestWithMaxAdvance(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start, clock.WithMaxAdvance(time.Minute))
+
+	after := fake.After(30 * time.Second)
+	if err := fake.Advance(30 * time.Minute); !errors.Is(err, clock.ErrAdvanceTooFar) {
+		t.Errorf("expected %v got %v", clock.ErrAdvanceTooFar, err)
+	}
+	assertClockAt(t, start, fake)
+	assertNotSent(t, after)
+
+	if err := fake.Advance(time.Minute); err != nil {
+		t.Errorf("expected nil got %v", err)
+	}
+	assertSent(t, start.Add(30*time.Second), after)
+}
+
+func TestWithCoalescing(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start, clock.WithCoalescing(time.Second))
+
+	first := fake.After(1200 * time.Millisecond)
+	second := fake.NewTimer(1700 * time.Millisecond)
+	c := second.C()
+	third := fake.After(2100 * time.Millisecond)
+
+	if next, _ := fake.NextDeadline(); !next.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("expected the deadlines coalesced, got %s", next)
+	}
+
+	fake.Advance(1900 * time.Millisecond)
+	assertNotSent(t, first)
+	assertNotSent(t, c)
+
+	// the first two wake together, late
+	fake.Advance(100 * time.Millisecond)
+	assertSent(t, start.Add(2*time.Second), first)
+	assertSent(t, start.Add(2*time.Second), c)
+	assertNotSent(t, third)
+
+	fake.Advance(time.Second)
+	assertSent(t, start.Add(3*time.Second), third)
+
+	// deadlines on the grid are on time
+	on := fake.After(time.Second)
+	fake.Advance(time.Second)
+	assertSent(t, start.Add(4*time.Second), on)
+}
+
+// fireOrder returns the order in which callbacks scheduled at the same
+// deadline run on a fake clock with opts.
+func fireOrder(n int, opts ...clock.FakeOption) string {
+	fake := clock.NewFakeClock(opts...)
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	var order []int
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		i := i
+		fake.AfterFunc(time.Second, func() {
+			mutex.Lock()
+			order = append(order, i)
+			mutex.Unlock()
+			wg.Done()
+		})
+	}
+
+	_ = fake.Advance(time.Second)
+	wg.Wait()
+	return fmt.Sprint(order)
+}
+
+func TestWithRandomOrder(t *testing.T) {
+	// with a single P, callback goroutines mostly run in the order the
+	// clock starts them, so the order only varies much across seeds
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+
+	orders := map[string]bool{}
+	for seed := int64(0); seed < 20; seed++ {
+		orders[fireOrder(8, clock.WithRandomOrder(seed))] = true
+	}
+	if len(orders) < 10 {
+		t.Errorf("expected seeds to vary the order, got %d orders: %v", len(orders), orders)
+	}
+}
+
+func TestWithRandomOrder_DeadlineOrder(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithRandomOrder(1))
+
+	// sleepers with different deadlines still wake in deadline order
+	late := fake.NewTimer(2 * time.Second)
+	early := fake.NewTimer(time.Second)
+	lateC, earlyC := late.C(), early.C()
+
+	_ = fake.Advance(2 * time.Second)
+	assertSent(t, time.Unix(1, 0).Add(time.Second), earlyC)
+	assertSent(t, time.Unix(1, 0).Add(2*time.Second), lateC)
+}
+
+func TestWithoutCallers(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithoutCallers())
+	fake.NewTimer(time.Second).C()
+	fake.AfterFunc(time.Second, func() {})
+
+	for _, timer := range fake.PendingTimers() {
+		if timer.Caller != "unknown" {
+			t.Errorf("expected an unknown caller for %s, got %s", timer.Kind, timer.Caller)
+		}
+	}
+}
+
+func TestWithGoroutines(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithGoroutines())
+	fake.NewTimer(time.Second).C()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fake.AfterFunc(2*time.Second, func() {})
+	}()
+	<-done
+
+	timers := fake.PendingTimers()
+	if len(timers) != 2 {
+		t.Fatalf("expected 2 pending timers got %d", len(timers))
+	}
+	if timers[0].Goroutine == 0 || timers[1].Goroutine == 0 || timers[0].Goroutine == timers[1].Goroutine {
+		t.Errorf("expected distinct goroutines got %d and %d", timers[0].Goroutine, timers[1].Goroutine)
+	}
+	if s := timers[0].String(); !strings.HasSuffix(s, fmt.Sprintf(" on goroutine %d", timers[0].Goroutine)) {
+		t.Errorf("expected the goroutine in %q", s)
+	}
+
+	// goroutines aren't recorded by default
+	fake = clock.NewFakeClock()
+	fake.AfterFunc(time.Second, func() {})
+	if goroutine := fake.PendingTimers()[0].Goroutine; goroutine != 0 {
+		t.Errorf("expected no goroutine got %d", goroutine)
+	}
+}
+
+func TestNewConstrainedFakeClock(t *testing.T) {
+	fake := clock.NewConstrainedFakeClock(4, clock.WithStrict())
+	assertClockAt(t, time.Unix(1, 0), fake)
+
+	if err := fake.Advance(time.Second); !errors.Is(err, clock.ErrNothingScheduled) {
+		t.Errorf("expected the options to apply, got %v", err)
+	}
+
+	// more sleepers than the capacity still work
+	var cs []<-chan time.Time
+	for i := 1; i <= 8; i++ {
+		cs = append(cs, fake.After(time.Duration(i)*time.Second))
+	}
+	if timers := fake.PendingTimers(); len(timers) != 8 || timers[0].Caller != "unknown" {
+		t.Errorf("expected 8 sleepers with unknown callers, got %v", timers)
+	}
+
+	_ = fake.Advance(8 * time.Second)
+	for i, c := range cs {
+		assertSent(t, time.Unix(int64(2+i), 0), c)
+	}
+}
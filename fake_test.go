@@ -1,6 +1,10 @@
 package clock_test
 
 import (
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -20,6 +24,96 @@ func TestAdvance(t *testing.T) {
 	assertClockAt(t, start.Add(1*time.Second), clock)
 }
 
+func TestAdvance_Negative(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	err := fake.Advance(-1 * time.Second)
+	if !errors.Is(err, clock.ErrNegativeAdvance) {
+		t.Errorf("expected %v got %v", clock.ErrNegativeAdvance, err)
+	}
+	assertClockAt(t, start, fake)
+
+	if err := fake.Advance(1 * time.Second); err != nil {
+		t.Errorf("expected nil got %v", err)
+	}
+}
+
+func TestAdvance_Zero(t *testing.T) {
+	start := time.Unix(1, 0)
+	clock := clock.NewFakeClockAt(start)
+
+	after := clock.After(1 * time.Second)
+
+	clock.Advance(0)
+	assertClockAt(t, start, clock)
+	assertNotSent(t, after)
+
+	if timers := clock.PendingTimers(); len(timers) != 1 {
+		t.Errorf("expected %d pending timer got %d", 1, len(timers))
+	}
+}
+
+func TestAdvance_Concurrent(t *testing.T) {
+	const goroutines, advances = 8, 100
+
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	cs := make([]<-chan time.Time, goroutines*advances)
+	for i := range cs {
+		cs[i] = fake.After(time.Duration(i+1) * time.Second)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < advances; j++ {
+				fake.Advance(time.Second)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assertClockAt(t, start.Add(goroutines*advances*time.Second), fake)
+	for i, c := range cs {
+		assertSent(t, start.Add(time.Duration(i+1)*time.Second), c)
+	}
+}
+
+func TestAdvance_Serialized(t *testing.T) {
+	fake := clock.NewFakeClock()
+	start := fake.Now()
+
+	// the step watcher blocks SetTime until released
+	release := make(chan struct{})
+	stop := clock.WatchJumps(fake, 0, func(clock.Jump) { <-release })
+	defer stop()
+
+	go fake.SetTime(start.Add(time.Hour))
+	for fake.Now().Equal(start) {
+		runtime.Gosched()
+	}
+
+	advanced := make(chan struct{})
+	go func() {
+		defer close(advanced)
+		fake.Advance(time.Second)
+	}()
+
+	select {
+	case <-advanced:
+		t.Fatal("expected Advance to wait for SetTime to return")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	<-advanced
+	assertClockAt(t, start.Add(time.Hour+time.Second), fake)
+}
+
 func TestSince_Positive(t *testing.T) {
 	start := time.Unix(2, 0)
 	clock := clock.NewFakeClockAt(start)
@@ -59,6 +153,55 @@ func TestNewTimer(t *testing.T) {
 	assertSent(t, start.Add(2*time.Second), c)
 }
 
+func TestNewTimer_Abandoned(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	// timers and tickers whose C was never called aren't registered with the
+	// clock, so abandoning them without Stop doesn't leak them
+	collected := make(chan struct{}, 2)
+	timer := fake.NewTimer(time.Hour)
+	runtime.SetFinalizer(timer, func(clock.Timer) { collected <- struct{}{} })
+	ticker := fake.NewTicker(time.Hour)
+	runtime.SetFinalizer(ticker, func(clock.Ticker) { collected <- struct{}{} })
+	timer, ticker = nil, nil
+
+	if pending := fake.Stats().Pending(); pending != 0 {
+		t.Errorf("expected no pending sleepers got %d", pending)
+	}
+
+	for i := 0; i < 2; i++ {
+		timeout := time.After(time.Second)
+	wait:
+		for {
+			runtime.GC()
+			select {
+			case <-collected:
+				break wait
+			case <-timeout:
+				t.Fatal("timeout: abandoned timer not collected")
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}
+}
+
+func TestNewTimer_ChannelOnly(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	// a goroutine holding only the channel of an armed timer or ticker
+	// still gets its value, even once the handle is garbage collected
+	timer := fake.NewTimer(time.Second).C()
+	ticker := fake.NewTicker(time.Second).C()
+	for i := 0; i < 3; i++ {
+		runtime.GC()
+	}
+
+	fake.Advance(time.Second)
+	assertSent(t, start.Add(time.Second), timer)
+	assertSent(t, start.Add(time.Second), ticker)
+}
+
 func TestNewTimer_CallCTwice(t *testing.T) {
 	start := time.Unix(1, 0)
 	clock := clock.NewFakeClockAt(start)
@@ -158,6 +301,52 @@ func TestNewTimer_Reset_Positive(t *testing.T) {
 	assertSent(t, start.Add(3*time.Second), c)
 }
 
+func TestNewTimer_Reset_Rearms(t *testing.T) {
+	start := time.Unix(1, 0)
+	clock := clock.NewFakeClockAt(start)
+
+	timer := clock.NewTimer(1 * time.Second)
+
+	c := timer.C()
+	assertClockUntil(t, 1, clock)
+	clock.Advance(1 * time.Second)
+	assertSent(t, start.Add(1*time.Second), c)
+
+	// the timer is pending again without calling C
+	timer.Reset(1 * time.Second)
+	assertClockUntil(t, 1, clock)
+
+	c = timer.C()
+	if timers := clock.PendingTimers(); len(timers) != 1 {
+		t.Fatalf("expected %d pending timer got %d", 1, len(timers))
+	}
+
+	clock.Advance(1 * time.Second)
+	assertSent(t, start.Add(2*time.Second), c)
+}
+
+func TestNewTimer_Reset_SameChannel(t *testing.T) {
+	start := time.Unix(1, 0)
+	clock := clock.NewFakeClockAt(start)
+
+	timer := clock.NewTimer(1 * time.Second)
+	c := timer.C()
+
+	assertClockUntil(t, 1, clock)
+	clock.Advance(1 * time.Second)
+
+	// the value that wasn't received is discarded by Reset
+	timer.Reset(1 * time.Second)
+	assertNotSent(t, c)
+
+	if timer.C() != c {
+		t.Error("expected C to return the same channel after Reset")
+	}
+
+	clock.Advance(1 * time.Second)
+	assertSent(t, start.Add(2*time.Second), c)
+}
+
 func TestNewTimer_Reset_Zero(t *testing.T) {
 	start := time.Unix(1, 0)
 	clock := clock.NewFakeClockAt(start)
@@ -366,6 +555,27 @@ func TestAfterFunc(t *testing.T) {
 	assertSent(t, start.Add(2*time.Second), c)
 }
 
+func TestAfterFunc_Reentrant(t *testing.T) {
+	start := time.Unix(1, 0)
+	clock := clock.NewFakeClockAt(start)
+
+	// callbacks may schedule and inspect timers on the clock that fired them
+	c := make(chan time.Time, 1)
+	clock.AfterFunc(1*time.Second, func() {
+		clock.PendingTimers()
+		clock.AfterFunc(1*time.Second, func() {
+			c <- clock.Now()
+		})
+	})
+
+	assertClockUntil(t, 1, clock)
+	clock.Advance(1 * time.Second)
+
+	assertClockUntil(t, 1, clock)
+	clock.Advance(1 * time.Second)
+	assertSent(t, start.Add(2*time.Second), c)
+}
+
 func TestAfterFunc_Stop(t *testing.T) {
 	start := time.Unix(1, 0)
 	clock := clock.NewFakeClockAt(start)
@@ -623,6 +833,38 @@ func TestNewTicker_Stop_NeverCalledC(t *testing.T) {
 	assertNotSent(t, c)
 }
 
+func TestNewTicker_Reset(t *testing.T) {
+	start := time.Unix(1, 0)
+	clock := clock.NewFakeClockAt(start)
+
+	ticker := clock.NewTicker(1 * time.Second)
+
+	// the channel already handed out ticks on the new schedule
+	c := ticker.C()
+	ticker.Reset(3 * time.Second)
+	clock.Advance(2 * time.Second)
+	assertNotSent(t, c)
+	clock.Advance(1 * time.Second)
+	assertSent(t, start.Add(3*time.Second), c)
+
+	c = ticker.C()
+	clock.Advance(3 * time.Second)
+	assertSent(t, start.Add(6*time.Second), c)
+}
+
+func TestNewTicker_Reset_Stopped(t *testing.T) {
+	start := time.Unix(1, 0)
+	clock := clock.NewFakeClockAt(start)
+
+	ticker := clock.NewTicker(1 * time.Second)
+
+	c := ticker.C()
+	ticker.Stop()
+	ticker.Reset(2 * time.Second)
+	clock.Advance(2 * time.Second)
+	assertSent(t, start.Add(2*time.Second), c)
+}
+
 func TestTick_Positive(t *testing.T) {
 	start := time.Unix(1, 0)
 	clock := clock.NewFakeClockAt(start)
@@ -662,6 +904,87 @@ func TestTick_Negative(t *testing.T) {
 	}
 }
 
+func TestPendingTimers(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	fake.AfterFunc(2*time.Second, func() {})
+	fake.After(1 * time.Second)
+
+	timers := fake.PendingTimers()
+	if len(timers) != 2 {
+		t.Fatalf("expected %d pending timers got %d", 2, len(timers))
+	}
+
+	expected := []struct {
+		kind     clock.TimerKind
+		duration time.Duration
+	}{
+		{clock.KindAfter, 1 * time.Second},
+		{clock.KindAfterFunc, 2 * time.Second},
+	}
+	for i, e := range expected {
+		timer := timers[i]
+		if timer.Kind != e.kind || timer.Duration != e.duration || timer.Deadline != start.Add(e.duration) {
+			t.Errorf("expected %s(%s) got %s", e.kind, e.duration, timer)
+		}
+		if !strings.Contains(timer.Caller, "fake_test.go") {
+			t.Errorf("expected caller in fake_test.go got %s", timer.Caller)
+		}
+	}
+}
+
+func TestSetTime(t *testing.T) {
+	fake := clock.NewFakeClock()
+	start := fake.Now()
+
+	timer := fake.NewTimer(time.Minute)
+	ticker := fake.NewTicker(time.Minute)
+	defer ticker.Stop()
+	after := fake.After(time.Minute)
+	_, mono := clock.NowBoth(fake)
+
+	// the timers keep a minute to wait, whether they're registered or not
+	stepped := start.Add(-time.Hour)
+	fake.SetTime(stepped)
+	assertClockAt(t, stepped, fake)
+	if _, actual := clock.NowBoth(fake); actual != mono {
+		t.Errorf("expected the monotonic reading %d got %d", mono, actual)
+	}
+
+	c := timer.C()
+	tick := ticker.C()
+	fake.Advance(time.Minute - time.Nanosecond)
+	assertNotSent(t, c)
+	assertNotSent(t, tick)
+	assertNotSent(t, after)
+
+	fake.Advance(time.Nanosecond)
+	assertSent(t, stepped.Add(time.Minute), c)
+	assertSent(t, stepped.Add(time.Minute), tick)
+	assertSent(t, stepped.Add(time.Minute), after)
+}
+
+func TestNextDeadline(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	if _, ok := fake.NextDeadline(); ok {
+		t.Error("expected no deadline")
+	}
+
+	fake.NewTimer(2 * time.Second).C()
+	timer := fake.NewTimer(time.Second)
+	timer.C()
+	if next, ok := fake.NextDeadline(); !ok || !next.Equal(fake.Now().Add(time.Second)) {
+		t.Errorf("expected the next deadline at %s got %s", fake.Now().Add(time.Second), next)
+	}
+
+	timer.Stop()
+	if next, ok := fake.NextDeadline(); !ok || !next.Equal(fake.Now().Add(2*time.Second)) {
+		t.Errorf("expected the next deadline at %s got %s", fake.Now().Add(2*time.Second), next)
+	}
+}
+
 func assertClockAt(t *testing.T, expected time.Time, clock clock.FakeClock) {
 	if actual := clock.Now(); actual != expected {
 		t.Errorf("expected %s got %s", expected, actual)
@@ -738,3 +1061,56 @@ func assertNotSent(t *testing.T, c <-chan time.Time) {
 	case <-timer.C:
 	}
 }
+
+func BenchmarkFakeClock_Sleep(b *testing.B) {
+	clock := clock.NewFakeClock()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		clock.Sleep(0)
+	}
+}
+
+func BenchmarkFakeClock_After(b *testing.B) {
+	clock := clock.NewFakeClock()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		clock.After(1 * time.Nanosecond)
+		clock.Advance(1 * time.Nanosecond)
+	}
+}
+
+func BenchmarkFakeClock_Now_Parallel(b *testing.B) {
+	clock := clock.NewFakeClock()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			clock.Now()
+		}
+	})
+}
+
+func BenchmarkFakeClock_After_Parallel(b *testing.B) {
+	clock := clock.NewFakeClock()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			clock.After(1 * time.Hour)
+		}
+	})
+}
+
+func BenchmarkFakeClock_NewTimer_Parallel(b *testing.B) {
+	clock := clock.NewFakeClock()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			timer := clock.NewTimer(1 * time.Hour)
+			timer.C()
+			timer.Stop()
+		}
+	})
+}
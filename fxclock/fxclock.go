@@ -0,0 +1,190 @@
+// Package fxclock provides uber/fx modules that supply a clock.Clock.
+//
+// Every ticker and timer created through the supplied Clock is stopped
+// when the fx application shuts down.
+package fxclock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"go.uber.org/fx"
+)
+
+// Module provides a clock.Clock backed by the real clock.
+var Module = fx.Module("clock",
+	fx.Provide(func(lc fx.Lifecycle) clock.Clock {
+		return newLifecycleClock(lc, clock.NewRealClock())
+	}),
+)
+
+// FakeModule provides a clock.FakeClock, and the same clock as a clock.Clock.
+// It is intended for use with fxtest, populating the FakeClock to drive time.
+var FakeModule = fx.Module("clock",
+	fx.Provide(
+		clock.NewFakeClock,
+		func(lc fx.Lifecycle, fake clock.FakeClock) clock.Clock {
+			return newLifecycleClock(lc, fake)
+		},
+	),
+)
+
+// minPrune is the number of tracked timers and tickers from which the
+// timers that fired are pruned.
+const minPrune = 64
+
+type lifecycleClock struct {
+	clock.Clock
+
+	mutex    sync.Mutex
+	stoppers map[interface{}]stopper
+	pruneAt  int
+}
+
+// stopper stops a timer or ticker at shutdown. The deadline of a timer
+// created by NewTimer is set, so it can be forgotten once it has fired.
+type stopper struct {
+	stop     func()
+	deadline time.Time
+}
+
+func newLifecycleClock(lc fx.Lifecycle, c clock.Clock) clock.Clock {
+	clock := &lifecycleClock{
+		Clock:    c,
+		stoppers: map[interface{}]stopper{},
+		pruneAt:  minPrune,
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			clock.stopAll()
+			return nil
+		},
+	})
+
+	return clock
+}
+
+func (clock *lifecycleClock) NewTimer(d time.Duration) clock.Timer {
+	timer := &lifecycleTimer{clock: clock}
+	timer.Timer = clock.Clock.NewTimer(d)
+	timer.channel = true
+	clock.track(timer, timer.stop, clock.Now().Add(d))
+
+	return timer
+}
+
+func (clock *lifecycleClock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	timer := &lifecycleTimer{clock: clock}
+	timer.Timer = clock.Clock.AfterFunc(d, func() {
+		clock.untrack(timer)
+		f()
+	})
+	clock.track(timer, timer.stop, time.Time{})
+
+	return timer
+}
+
+func (clock *lifecycleClock) NewTicker(d time.Duration) clock.Ticker {
+	ticker := &lifecycleTicker{
+		clock:  clock,
+		Ticker: clock.Clock.NewTicker(d),
+	}
+	clock.track(ticker, ticker.Ticker.Stop, time.Time{})
+
+	return ticker
+}
+
+func (clock *lifecycleClock) Tick(d time.Duration) func() <-chan time.Time {
+	if d <= 0 {
+		return func() <-chan time.Time { return nil }
+	}
+
+	return clock.NewTicker(d).C
+}
+
+// track tracks a timer or ticker to stop at shutdown. The timers of
+// NewTimer that fired, which nothing untracks, are pruned as the tracked
+// timers grow, so they don't accumulate.
+func (clock *lifecycleClock) track(s interface{}, stop func(), deadline time.Time) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	clock.stoppers[s] = stopper{stop: stop, deadline: deadline}
+	if len(clock.stoppers) < clock.pruneAt {
+		return
+	}
+
+	now := clock.Now()
+	for s, stopper := range clock.stoppers {
+		if !stopper.deadline.IsZero() && !stopper.deadline.After(now) {
+			delete(clock.stoppers, s)
+		}
+	}
+	clock.pruneAt = 2 * len(clock.stoppers)
+	if clock.pruneAt < minPrune {
+		clock.pruneAt = minPrune
+	}
+}
+
+func (clock *lifecycleClock) untrack(s interface{}) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	delete(clock.stoppers, s)
+}
+
+func (clock *lifecycleClock) stopAll() {
+	clock.mutex.Lock()
+	stoppers := clock.stoppers
+	clock.stoppers = map[interface{}]stopper{}
+	clock.mutex.Unlock()
+
+	for _, stopper := range stoppers {
+		stopper.stop()
+	}
+}
+
+type lifecycleTimer struct {
+	clock.Timer
+	clock *lifecycleClock
+
+	// channel is set for the timers of NewTimer, which deliver on C
+	// rather than call a function
+	channel bool
+}
+
+func (timer *lifecycleTimer) stop() {
+	timer.Timer.Stop()
+}
+
+func (timer *lifecycleTimer) Stop() bool {
+	timer.clock.untrack(timer)
+	return timer.Timer.Stop()
+}
+
+func (timer *lifecycleTimer) Reset(d time.Duration) bool {
+	var deadline time.Time
+	if timer.channel {
+		deadline = timer.clock.Now().Add(d)
+	}
+	timer.clock.track(timer, timer.stop, deadline)
+	return timer.Timer.Reset(d)
+}
+
+type lifecycleTicker struct {
+	clock.Ticker
+	clock *lifecycleClock
+}
+
+func (ticker *lifecycleTicker) Stop() {
+	ticker.clock.untrack(ticker)
+	ticker.Ticker.Stop()
+}
+
+func (ticker *lifecycleTicker) Reset(d time.Duration) {
+	ticker.clock.track(ticker, ticker.Ticker.Stop, time.Time{})
+	ticker.Ticker.Reset(d)
+}
@@ -0,0 +1,87 @@
+package fxclock_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/fxclock"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestModule(t *testing.T) {
+	var c clock.Clock
+	app := fxtest.New(t, fxclock.Module, fx.Populate(&c))
+	app.RequireStart()
+	defer app.RequireStop()
+
+	if c == nil {
+		t.Fatal("expected a clock")
+	}
+}
+
+func TestFakeModule_StopsTickersOnShutdown(t *testing.T) {
+	var (
+		c    clock.Clock
+		fake clock.FakeClock
+	)
+	app := fxtest.New(t, fxclock.FakeModule, fx.Populate(&c, &fake))
+	app.RequireStart()
+
+	ticker := c.NewTicker(1 * time.Second)
+	tc := ticker.C()
+
+	fired := make(chan struct{})
+	c.AfterFunc(1*time.Second, func() { close(fired) })
+
+	fake.BlockUntil(2)
+
+	app.RequireStop()
+
+	fake.Advance(1 * time.Second)
+
+	select {
+	case <-tc:
+		t.Error("ticker fired after shutdown")
+	case <-fired:
+		t.Error("timer fired after shutdown")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestFakeModule_ForgetsFiredTimers(t *testing.T) {
+	var (
+		c    clock.Clock
+		fake clock.FakeClock
+	)
+	app := fxtest.New(t, fxclock.FakeModule, fx.Populate(&c, &fake))
+	app.RequireStart()
+	defer app.RequireStop()
+
+	collected := make(chan struct{}, 1)
+	timer := c.NewTimer(1 * time.Second)
+	runtime.SetFinalizer(timer, func(clock.Timer) { collected <- struct{}{} })
+	tc := timer.C()
+	fake.Advance(1 * time.Second)
+	<-tc
+	timer = nil
+
+	// a timer that fired is forgotten once enough others are tracked
+	for i := 0; i < 100; i++ {
+		c.NewTimer(1 * time.Hour)
+	}
+
+	timeout := time.After(time.Second)
+	for {
+		runtime.GC()
+		select {
+		case <-collected:
+			return
+		case <-timeout:
+			t.Fatal("timeout: fired timer still tracked")
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
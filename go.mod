@@ -1,3 +1,3 @@
 module github.com/go-toolbelt/clock
 
-go 1.16
+go 1.18
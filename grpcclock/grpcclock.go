@@ -0,0 +1,167 @@
+// Package grpcclock provides gRPC interceptors that apply call deadlines
+// and record call durations using a clock.Clock.
+//
+// Deadlines applied by the interceptors are measured by the clock,
+// so slow peers and deadline propagation can be simulated on a fake clock.
+// Client interceptors translate the clock deadline into the remaining budget
+// that gRPC sends to the server; server interceptors translate the received
+// budget back into a deadline measured by the server's clock.
+package grpcclock
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"google.golang.org/grpc"
+)
+
+// UnaryClientInterceptor returns an interceptor that applies the configured
+// deadlines to unary calls and records their duration.
+func UnaryClientInterceptor(clock clock.Clock, opts ...Option) grpc.UnaryClientInterceptor {
+	c := newConfig(opts)
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		ctx, cancel := c.withDeadline(ctx, clock, clientRemaining(ctx, clock))
+		defer cancel()
+
+		start := clock.Now()
+		err := invoker(&wallCtx{Context: ctx, clock: clock}, method, req, reply, cc, callOpts...)
+		c.recordSince(clock, start, method, err)
+
+		return err
+	}
+}
+
+// StreamClientInterceptor returns an interceptor that applies the configured
+// deadlines to streaming calls and records the duration of stream creation.
+// The deadline stays in effect until the stream finishes.
+func StreamClientInterceptor(clock clock.Clock, opts ...Option) grpc.StreamClientInterceptor {
+	c := newConfig(opts)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, cancel := c.withDeadline(ctx, clock, clientRemaining(ctx, clock))
+
+		start := clock.Now()
+		stream, err := streamer(&wallCtx{Context: ctx, clock: clock}, desc, cc, method, callOpts...)
+		c.recordSince(clock, start, method, err)
+
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		go func() {
+			<-stream.Context().Done()
+			cancel()
+		}()
+		return stream, nil
+	}
+}
+
+// UnaryServerInterceptor returns an interceptor that applies the configured
+// deadlines to unary handlers and records their duration.
+func UnaryServerInterceptor(clock clock.Clock, opts ...Option) grpc.UnaryServerInterceptor {
+	c := newConfig(opts)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, cancel := c.withDeadline(ctx, clock, serverRemaining(ctx))
+		defer cancel()
+
+		start := clock.Now()
+		resp, err := handler(ctx, req)
+		c.recordSince(clock, start, info.FullMethod, err)
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor that applies the configured
+// deadlines to streaming handlers and records their duration.
+func StreamServerInterceptor(clock clock.Clock, opts ...Option) grpc.StreamServerInterceptor {
+	c := newConfig(opts)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := c.withDeadline(ss.Context(), clock, serverRemaining(ss.Context()))
+		defer cancel()
+
+		start := clock.Now()
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		c.recordSince(clock, start, info.FullMethod, err)
+
+		return err
+	}
+}
+
+// withDeadline applies the configured timeouts to the remaining budget of ctx.
+// A negative remaining budget means ctx has no deadline.
+func (cfg config) withDeadline(ctx context.Context, c clock.Clock, remaining time.Duration) (context.Context, context.CancelFunc) {
+	timeout, ok := remaining, remaining >= 0
+	if !ok && cfg.timeout > 0 {
+		timeout, ok = cfg.timeout, true
+	}
+	if cfg.maxTimeout > 0 && (!ok || timeout > cfg.maxTimeout) {
+		timeout, ok = cfg.maxTimeout, true
+	}
+
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+	return clock.WithTimeout(ctx, c, timeout)
+}
+
+func (c config) recordSince(clock clock.Clock, start time.Time, method string, err error) {
+	if c.record != nil {
+		c.record(method, clock.Since(start), err)
+	}
+}
+
+// clientRemaining returns the budget left before the deadline of ctx,
+// which is measured by clock on the client side.
+func clientRemaining(ctx context.Context, clock clock.Clock) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return -1
+	}
+	if remaining := deadline.Sub(clock.Now()); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// serverRemaining returns the budget sent by the client,
+// which gRPC exposes as a wall clock deadline.
+func serverRemaining(ctx context.Context) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return -1
+	}
+	if remaining := time.Until(deadline); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// wallCtx reports the clock deadline of its context as a wall clock deadline,
+// which is what gRPC uses to compute the budget sent to the server.
+type wallCtx struct {
+	context.Context
+	clock clock.Clock
+}
+
+func (ctx *wallCtx) Deadline() (time.Time, bool) {
+	deadline, ok := ctx.Context.Deadline()
+	if !ok {
+		return deadline, false
+	}
+	return time.Now().Add(deadline.Sub(ctx.clock.Now())), true
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *serverStream) Context() context.Context {
+	return ss.ctx
+}
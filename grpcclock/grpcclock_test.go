@@ -0,0 +1,170 @@
+package grpcclock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/grpcclock"
+	"google.golang.org/grpc"
+)
+
+const timeout = 100 * time.Millisecond
+
+func TestUnaryClientInterceptor_Timeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	interceptor := grpcclock.UnaryClientInterceptor(fake, grpcclock.WithTimeout(2*time.Second))
+
+	var remaining time.Duration
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Error("expected a deadline")
+		}
+		remaining = time.Until(deadline)
+
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- interceptor(context.Background(), "/test/Method", nil, nil, nil, invoker)
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(2 * time.Second)
+
+	select {
+	case err := <-errs:
+		if err != context.DeadlineExceeded {
+			t.Errorf("expected %v got %v", context.DeadlineExceeded, err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout: call did not return")
+	}
+
+	// the deadline seen by gRPC is translated to wall time
+	if remaining <= 1*time.Second || remaining > 2*time.Second {
+		t.Errorf("expected about %s remaining got %s", 2*time.Second, remaining)
+	}
+}
+
+func TestUnaryClientInterceptor_MaxTimeout(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	interceptor := grpcclock.UnaryClientInterceptor(fake, grpcclock.WithMaxTimeout(1*time.Second))
+
+	ctx, cancel := clock.WithTimeout(context.Background(), fake, 1*time.Minute)
+	defer cancel()
+
+	var remaining time.Duration
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		deadline, _ := ctx.Deadline()
+		remaining = time.Until(deadline)
+		return nil
+	}
+
+	if err := interceptor(ctx, "/test/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+	if remaining > 1*time.Second {
+		t.Errorf("expected at most %s remaining got %s", 1*time.Second, remaining)
+	}
+}
+
+func TestUnaryClientInterceptor_DurationRecorder(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	var (
+		method   string
+		duration time.Duration
+	)
+	interceptor := grpcclock.UnaryClientInterceptor(fake, grpcclock.WithDurationRecorder(func(m string, d time.Duration, err error) {
+		method, duration = m, d
+	}))
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		fake.Advance(3 * time.Second)
+		return nil
+	}
+
+	if err := interceptor(context.Background(), "/test/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+	if method != "/test/Method" || duration != 3*time.Second {
+		t.Errorf("expected %s for %s got %s for %s", 3*time.Second, "/test/Method", duration, method)
+	}
+}
+
+func TestUnaryServerInterceptor_Timeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	interceptor := grpcclock.UnaryServerInterceptor(fake, grpcclock.WithTimeout(1*time.Second))
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test/Method"}, handler)
+		errs <- err
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+
+	select {
+	case err := <-errs:
+		if err != context.DeadlineExceeded {
+			t.Errorf("expected %v got %v", context.DeadlineExceeded, err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout: handler did not return")
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *serverStream) Context() context.Context {
+	return ss.ctx
+}
+
+func TestStreamServerInterceptor_MaxTimeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	interceptor := grpcclock.StreamServerInterceptor(fake, grpcclock.WithMaxTimeout(1*time.Second))
+
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		<-ss.Context().Done()
+		return ss.Context().Err()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- interceptor(nil, &serverStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/test/Stream"}, handler)
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+
+	select {
+	case err := <-errs:
+		if err != context.DeadlineExceeded {
+			t.Errorf("expected %v got %v", context.DeadlineExceeded, err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout: handler did not return")
+	}
+}
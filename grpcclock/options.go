@@ -0,0 +1,43 @@
+package grpcclock
+
+import "time"
+
+// An Option configures the interceptors.
+type Option func(*config)
+
+type config struct {
+	timeout    time.Duration
+	maxTimeout time.Duration
+	record     func(method string, d time.Duration, err error)
+}
+
+func newConfig(opts []Option) config {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithTimeout sets the deadline applied to calls that have none.
+func WithTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.timeout = d
+	}
+}
+
+// WithMaxTimeout caps the deadline of every call,
+// including calls whose caller or peer asked for a later one.
+func WithMaxTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.maxTimeout = d
+	}
+}
+
+// WithDurationRecorder sets a function called with the duration of every call,
+// measured by the clock, and the error the call returned.
+func WithDurationRecorder(record func(method string, d time.Duration, err error)) Option {
+	return func(c *config) {
+		c.record = record
+	}
+}
@@ -0,0 +1,124 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// A Jump is a step of the wall time of a clock relative to its monotonic
+// time, such as an NTP step, a manual change of the system clock, or the
+// restore of a suspended VM.
+type Jump struct {
+	// From is the wall time the clock would have read without the jump.
+	From time.Time
+
+	// To is the wall time the clock read after the jump.
+	To time.Time
+}
+
+// Offset returns how far the wall time jumped, negative if it went back.
+func (jump Jump) Offset() time.Duration {
+	return jump.To.Sub(jump.From)
+}
+
+// stepWatcherClock is implemented by clocks that report their steps.
+type stepWatcherClock interface {
+	watchSteps(f func(from, to time.Time)) func()
+}
+
+// WatchJumps calls f with each jump of the wall time of clock by more than
+// threshold, either way, until the returned function is called.
+//
+// On the fake clock, f is called for each step made by SetTime or Suspend,
+// by the goroutine that made it. On other clocks, the wall time is compared
+// to the monotonic readings of NowBoth every second, and f is called from a
+// goroutine of its own; the function returned waits for that goroutine to
+// return. Clocks whose Now doesn't follow the system's wall clock, such as
+// those built with WithBootTime, never jump.
+func WatchJumps(clock Clock, threshold time.Duration, f func(Jump)) func() {
+	report := func(jump Jump) {
+		if offset := jump.Offset(); offset > threshold || offset < -threshold {
+			f(jump)
+		}
+	}
+
+	if clock, ok := clock.(stepWatcherClock); ok {
+		return clock.watchSteps(func(from, to time.Time) {
+			report(Jump{From: from, To: to})
+		})
+	}
+
+	wall, mono := NowBoth(clock)
+	return poll(clock, func() {
+		nextWall, nextMono := NowBoth(clock)
+		report(Jump{
+			From: wall.Add(time.Duration(nextMono - mono)),
+			To:   nextWall,
+		})
+		wall, mono = nextWall, nextMono
+	})
+}
+
+// poll calls f every wallPoll, measured by clock, from a goroutine of its
+// own, until the returned function is called. The function waits for the
+// goroutine to return.
+func poll(clock Clock, f func()) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		timer := clock.NewTimer(wallPoll)
+		defer timer.Stop()
+
+		c := timer.C()
+		for {
+			select {
+			case <-c:
+			case <-stop:
+				return
+			}
+
+			f()
+			timer.Reset(wallPoll)
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(stop) })
+		<-done
+	}
+}
+
+// JumpWatcher delivers the jumps of the wall time of a clock on a channel.
+type JumpWatcher struct {
+	c    chan Jump
+	stop func()
+}
+
+// NewJumpWatcher returns a JumpWatcher of the jumps of the wall time of clock
+// by more than threshold, as reported by WatchJumps. Like a Ticker, it drops
+// jumps that the consumer isn't ready to receive.
+func NewJumpWatcher(clock Clock, threshold time.Duration) *JumpWatcher {
+	watcher := &JumpWatcher{
+		c: make(chan Jump, 1),
+	}
+	watcher.stop = WatchJumps(clock, threshold, func(jump Jump) {
+		select {
+		case watcher.c <- jump:
+		default:
+		}
+	})
+	return watcher
+}
+
+// C returns the channel on which the jumps are delivered.
+func (watcher *JumpWatcher) C() <-chan Jump {
+	return watcher.c
+}
+
+// Stop turns off the watcher. After Stop, no more jumps will be sent.
+func (watcher *JumpWatcher) Stop() {
+	watcher.stop()
+}
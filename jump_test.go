@@ -0,0 +1,73 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestJumpWatcher(t *testing.T) {
+	fake := clock.NewFakeClock()
+	start := fake.Now()
+
+	watcher := clock.NewJumpWatcher(fake, time.Minute)
+	defer watcher.Stop()
+
+	// advancing the clock isn't a jump, nor is a step within the threshold
+	fake.Advance(time.Hour)
+	fake.SetTime(fake.Now().Add(time.Minute))
+	select {
+	case jump := <-watcher.C():
+		t.Errorf("unexpected jump %+v", jump)
+	default:
+	}
+
+	from := fake.Now()
+	to := start.Add(-time.Hour)
+	fake.SetTime(to)
+	select {
+	case jump := <-watcher.C():
+		if !jump.From.Equal(from) || !jump.To.Equal(to) {
+			t.Errorf("expected a jump from %s to %s got %+v", from, to, jump)
+		}
+		if offset := jump.Offset(); offset != to.Sub(from) {
+			t.Errorf("expected an offset of %s got %s", to.Sub(from), offset)
+		}
+	default:
+		t.Error("expected a jump")
+	}
+}
+
+func TestWatchJumps_Stop(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	jumps := 0
+	stop := clock.WatchJumps(fake, 0, func(clock.Jump) { jumps++ })
+
+	fake.SetTime(fake.Now().Add(time.Hour))
+	stop()
+	fake.SetTime(fake.Now().Add(time.Hour))
+
+	if jumps != 1 {
+		t.Errorf("expected 1 jump got %d", jumps)
+	}
+}
+
+func TestWatchJumps_Real(t *testing.T) {
+	stop := clock.WatchJumps(clock.NewRealClock(), time.Minute, func(jump clock.Jump) {
+		t.Errorf("unexpected jump %+v", jump)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("timeout: stop didn't return")
+	}
+}
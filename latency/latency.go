@@ -0,0 +1,211 @@
+// Package latency records durations measured by a clock.Clock into
+// histograms, for latency and SLO accounting.
+package latency
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// DefaultBuckets are upper bounds suited to the latency of network services.
+var DefaultBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// LinearBuckets returns n upper bounds, starting at start and width apart.
+func LinearBuckets(start, width time.Duration, n int) []time.Duration {
+	buckets := make([]time.Duration, n)
+	for i := range buckets {
+		buckets[i] = start + time.Duration(i)*width
+	}
+	return buckets
+}
+
+// ExponentialBuckets returns n upper bounds, starting at start and each
+// factor times the previous one.
+func ExponentialBuckets(start time.Duration, factor float64, n int) []time.Duration {
+	buckets := make([]time.Duration, n)
+	bound := float64(start)
+	for i := range buckets {
+		buckets[i] = time.Duration(bound)
+		bound *= factor
+	}
+	return buckets
+}
+
+// Recorder aggregates durations into a histogram.
+//
+// It is safe for concurrent use.
+type Recorder struct {
+	clock  clock.Clock
+	bounds []time.Duration
+
+	mutex  sync.Mutex
+	counts []int64
+	count  int64
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+// NewRecorder returns a Recorder measured by clock, whose histogram buckets
+// have the given upper bounds, plus a bucket for longer durations.
+// If no bounds are given, DefaultBuckets are used.
+func NewRecorder(clock clock.Clock, bounds ...time.Duration) *Recorder {
+	if len(bounds) == 0 {
+		bounds = DefaultBuckets
+	}
+
+	bounds = append([]time.Duration(nil), bounds...)
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+
+	return &Recorder{
+		clock:  clock,
+		bounds: bounds,
+		counts: make([]int64, len(bounds)+1),
+	}
+}
+
+// A Timing measures a single duration, from Recorder.Start or Timeout.Start
+// to Stop.
+type Timing struct {
+	clock   clock.Clock
+	observe func(time.Duration)
+	start   time.Time
+}
+
+// Start starts measuring a duration, which is recorded by calling Stop on
+// the returned Timing.
+func (r *Recorder) Start() Timing {
+	return Timing{
+		clock:   r.clock,
+		observe: r.Observe,
+		start:   r.clock.Now(),
+	}
+}
+
+// Stop records and returns the duration since the Timing started.
+func (t Timing) Stop() time.Duration {
+	d := t.clock.Since(t.start)
+	t.observe(d)
+	return d
+}
+
+// Observe records a duration.
+func (r *Recorder) Observe(d time.Duration) {
+	i := sort.Search(len(r.bounds), func(i int) bool { return d <= r.bounds[i] })
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.counts[i]++
+	if r.count == 0 || d < r.min {
+		r.min = d
+	}
+	if r.count == 0 || d > r.max {
+		r.max = d
+	}
+	r.count++
+	r.sum += d
+}
+
+// A Bucket counts the durations greater than the previous bucket's upper
+// bound, up to and including its own.
+type Bucket struct {
+	// UpperBound is the longest duration in the bucket,
+	// or zero for the last bucket, which has no bound.
+	UpperBound time.Duration
+
+	// Count is the number of durations in the bucket.
+	Count int64
+}
+
+// A Snapshot is a copy of a Recorder's histogram at a point in time.
+type Snapshot struct {
+	// At is the time the snapshot was taken.
+	At time.Time
+
+	Buckets []Bucket
+	Count   int64
+	Sum     time.Duration
+	Min     time.Duration
+	Max     time.Duration
+}
+
+// Mean returns the mean duration recorded, or zero if there are none.
+func (s Snapshot) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / time.Duration(s.Count)
+}
+
+// CountAtMost returns the number of durations recorded within a bucket bound,
+// such as the number of requests served within an SLO threshold.
+// Durations in the bucket that contains but doesn't end at d aren't counted.
+func (s Snapshot) CountAtMost(d time.Duration) int64 {
+	var n int64
+	for _, bucket := range s.Buckets {
+		if bucket.UpperBound == 0 || bucket.UpperBound > d {
+			break
+		}
+		n += bucket.Count
+	}
+	return n
+}
+
+// Snapshot returns a copy of the histogram.
+func (r *Recorder) Snapshot() Snapshot {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.snapshot()
+}
+
+// Reset returns a copy of the histogram and clears it.
+func (r *Recorder) Reset() Snapshot {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	s := r.snapshot()
+	for i := range r.counts {
+		r.counts[i] = 0
+	}
+	r.count = 0
+	r.sum = 0
+	r.min = 0
+	r.max = 0
+	return s
+}
+
+func (r *Recorder) snapshot() Snapshot {
+	buckets := make([]Bucket, len(r.counts))
+	for i, count := range r.counts {
+		buckets[i].Count = count
+		if i < len(r.bounds) {
+			buckets[i].UpperBound = r.bounds[i]
+		}
+	}
+
+	return Snapshot{
+		At:      r.clock.Now(),
+		Buckets: buckets,
+		Count:   r.count,
+		Sum:     r.sum,
+		Min:     r.min,
+		Max:     r.max,
+	}
+}
@@ -0,0 +1,66 @@
+package latency_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/latency"
+)
+
+func TestRecorder(t *testing.T) {
+	fake := clock.NewFakeClock()
+	r := latency.NewRecorder(fake, 100*time.Millisecond, 10*time.Millisecond)
+
+	for _, d := range []time.Duration{5 * time.Millisecond, 10 * time.Millisecond, 50 * time.Millisecond, 1 * time.Second} {
+		timing := r.Start()
+		fake.Advance(d)
+		if actual := timing.Stop(); actual != d {
+			t.Errorf("expected %s got %s", d, actual)
+		}
+	}
+
+	s := r.Snapshot()
+
+	expected := []latency.Bucket{
+		{UpperBound: 10 * time.Millisecond, Count: 2},
+		{UpperBound: 100 * time.Millisecond, Count: 1},
+		{Count: 1},
+	}
+	if !reflect.DeepEqual(s.Buckets, expected) {
+		t.Errorf("expected %v got %v", expected, s.Buckets)
+	}
+
+	if s.Count != 4 || s.Min != 5*time.Millisecond || s.Max != 1*time.Second {
+		t.Errorf("expected 4 durations from 5ms to 1s got %d from %s to %s", s.Count, s.Min, s.Max)
+	}
+	if mean := s.Mean(); mean != 266250*time.Microsecond {
+		t.Errorf("expected %s got %s", 266250*time.Microsecond, mean)
+	}
+	if n := s.CountAtMost(100 * time.Millisecond); n != 3 {
+		t.Errorf("expected %d within 100ms got %d", 3, n)
+	}
+	if !s.At.Equal(fake.Now()) {
+		t.Errorf("expected snapshot at %s got %s", fake.Now(), s.At)
+	}
+
+	if reset := r.Reset(); reset.Count != 4 {
+		t.Errorf("expected %d got %d", 4, reset.Count)
+	}
+	if s := r.Snapshot(); s.Count != 0 || s.Buckets[0].Count != 0 {
+		t.Errorf("expected an empty histogram got %+v", s)
+	}
+}
+
+func TestBuckets(t *testing.T) {
+	linear := latency.LinearBuckets(10*time.Millisecond, 5*time.Millisecond, 3)
+	if expected := []time.Duration{10 * time.Millisecond, 15 * time.Millisecond, 20 * time.Millisecond}; !reflect.DeepEqual(linear, expected) {
+		t.Errorf("expected %v got %v", expected, linear)
+	}
+
+	exponential := latency.ExponentialBuckets(1*time.Millisecond, 10, 3)
+	if expected := []time.Duration{1 * time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond}; !reflect.DeepEqual(exponential, expected) {
+		t.Errorf("expected %v got %v", expected, exponential)
+	}
+}
@@ -0,0 +1,176 @@
+package latency
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// A TimeoutOption configures a Timeout.
+type TimeoutOption func(*Timeout)
+
+// WithPercentile sets the percentile of the observed durations that the
+// timeout is derived from, between 0 and 1. The default is 0.99.
+// It panics if p is out of range.
+func WithPercentile(p float64) TimeoutOption {
+	if p <= 0 || p > 1 {
+		panic("latency: percentile out of range for WithPercentile")
+	}
+
+	return func(t *Timeout) {
+		t.percentile = p
+	}
+}
+
+// WithFactor sets the factor the percentile is multiplied by to give the
+// timeout. The default is 2.
+func WithFactor(factor float64) TimeoutOption {
+	return func(t *Timeout) {
+		t.factor = factor
+	}
+}
+
+// WithBounds keeps the timeout between floor and ceiling, so a run of fast
+// operations can't make it too tight, nor a run of slow ones too loose.
+// A zero bound is no bound.
+func WithBounds(floor, ceiling time.Duration) TimeoutOption {
+	return func(t *Timeout) {
+		t.floor = floor
+		t.ceiling = ceiling
+	}
+}
+
+// WithWindow sets how long an observed duration is taken into account.
+// The default is a minute.
+func WithWindow(window time.Duration) TimeoutOption {
+	return func(t *Timeout) {
+		t.window = window
+	}
+}
+
+// WithMinSamples sets the number of durations that must have been observed
+// within the window before the timeout is derived from them, rather than
+// being the initial timeout. The default is 10.
+func WithMinSamples(n int) TimeoutOption {
+	return func(t *Timeout) {
+		t.minSamples = n
+	}
+}
+
+// WithMaxSamples caps the number of durations kept, dropping the oldest.
+// The default is 1000.
+func WithMaxSamples(n int) TimeoutOption {
+	return func(t *Timeout) {
+		t.maxSamples = n
+	}
+}
+
+// Timeout computes a timeout adapted to the durations observed for an
+// operation: a percentile of the durations observed within a window of time
+// measured by a clock.Clock, times a factor, kept within bounds.
+//
+// It is safe for concurrent use.
+type Timeout struct {
+	clock      clock.Clock
+	initial    time.Duration
+	percentile float64
+	factor     float64
+	floor      time.Duration
+	ceiling    time.Duration
+	window     time.Duration
+	minSamples int
+	maxSamples int
+
+	mutex   sync.Mutex
+	samples []sample
+}
+
+type sample struct {
+	at time.Time
+	d  time.Duration
+}
+
+// NewTimeout returns a Timeout measured by clock, which is initial until
+// enough durations have been observed.
+func NewTimeout(clock clock.Clock, initial time.Duration, opts ...TimeoutOption) *Timeout {
+	t := &Timeout{
+		clock:      clock,
+		initial:    initial,
+		percentile: 0.99,
+		factor:     2,
+		window:     time.Minute,
+		minSamples: 10,
+		maxSamples: 1000,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Start starts measuring the duration of an operation, which is observed by
+// calling Stop on the returned Timing.
+func (t *Timeout) Start() Timing {
+	return Timing{
+		clock:   t.clock,
+		observe: t.Observe,
+		start:   t.clock.Now(),
+	}
+}
+
+// Observe records the duration of an operation.
+func (t *Timeout) Observe(d time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.samples = append(t.samples, sample{at: t.clock.Now(), d: d})
+	if len(t.samples) > t.maxSamples {
+		t.samples = append(t.samples[:0], t.samples[len(t.samples)-t.maxSamples:]...)
+	}
+}
+
+// Current returns the timeout to arm a timer with for the next operation.
+func (t *Timeout) Current() time.Duration {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.expire()
+	if len(t.samples) < t.minSamples || len(t.samples) == 0 {
+		return t.clamp(t.initial)
+	}
+
+	durations := make([]time.Duration, len(t.samples))
+	for i, s := range t.samples {
+		durations[i] = s.d
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	// the nearest rank
+	rank := int(math.Ceil(t.percentile*float64(len(durations)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return t.clamp(time.Duration(float64(durations[rank]) * t.factor))
+}
+
+// expire drops the samples observed before the window.
+// The caller holds the mutex.
+func (t *Timeout) expire() {
+	start := t.clock.Now().Add(-t.window)
+
+	i := sort.Search(len(t.samples), func(i int) bool { return t.samples[i].at.After(start) })
+	t.samples = append(t.samples[:0], t.samples[i:]...)
+}
+
+func (t *Timeout) clamp(d time.Duration) time.Duration {
+	if t.floor > 0 && d < t.floor {
+		return t.floor
+	}
+	if t.ceiling > 0 && d > t.ceiling {
+		return t.ceiling
+	}
+	return d
+}
@@ -0,0 +1,77 @@
+package latency_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/latency"
+)
+
+func assertTimeout(t *testing.T, expected time.Duration, timeout *latency.Timeout) {
+	t.Helper()
+
+	if actual := timeout.Current(); actual != expected {
+		t.Errorf("expected %s got %s", expected, actual)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+	timeout := latency.NewTimeout(fake, time.Second,
+		latency.WithPercentile(0.9),
+		latency.WithFactor(2),
+		latency.WithMinSamples(10),
+		latency.WithWindow(time.Minute),
+	)
+
+	// too few samples for a percentile
+	for i := 1; i <= 9; i++ {
+		timing := timeout.Start()
+		fake.Advance(time.Duration(i) * 10 * time.Millisecond)
+		timing.Stop()
+	}
+	assertTimeout(t, time.Second, timeout)
+
+	// the 90th percentile of 10ms to 100ms is 90ms
+	timeout.Observe(100 * time.Millisecond)
+	assertTimeout(t, 180*time.Millisecond, timeout)
+
+	// the samples age out of the window
+	fake.Advance(time.Minute)
+	assertTimeout(t, time.Second, timeout)
+}
+
+func TestTimeout_Bounds(t *testing.T) {
+	fake := clock.NewFakeClock()
+	timeout := latency.NewTimeout(fake, time.Second,
+		latency.WithMinSamples(1),
+		latency.WithBounds(50*time.Millisecond, 500*time.Millisecond),
+	)
+
+	// the initial timeout is bounded too
+	assertTimeout(t, 500*time.Millisecond, timeout)
+
+	timeout.Observe(time.Millisecond)
+	assertTimeout(t, 50*time.Millisecond, timeout)
+
+	timeout.Observe(time.Second)
+	assertTimeout(t, 500*time.Millisecond, timeout)
+}
+
+func TestTimeout_MaxSamples(t *testing.T) {
+	fake := clock.NewFakeClock()
+	timeout := latency.NewTimeout(fake, time.Second,
+		latency.WithPercentile(1),
+		latency.WithFactor(1),
+		latency.WithMinSamples(1),
+		latency.WithMaxSamples(2),
+	)
+
+	timeout.Observe(300 * time.Millisecond)
+	timeout.Observe(100 * time.Millisecond)
+	timeout.Observe(200 * time.Millisecond)
+
+	// the slowest sample was dropped
+	assertTimeout(t, 200*time.Millisecond, timeout)
+}
@@ -0,0 +1,179 @@
+// Package lease keeps a time-limited lease alive by renewing it periodically
+// on a clock.Clock, as used by leader election and distributed locks.
+package lease
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// A RenewFunc renews the lease, returning the TTL granted from now.
+// Its context is canceled when the lease expires or is stopped.
+type RenewFunc func(ctx context.Context) (time.Duration, error)
+
+// An Option configures a Lease.
+type Option func(*Lease)
+
+// WithRenewFraction sets the fraction of the remaining TTL after which the
+// lease is renewed. The default is 0.5.
+func WithRenewFraction(f float64) Option {
+	return func(l *Lease) {
+		l.fraction = f
+	}
+}
+
+// WithJitter renews up to the given fraction of the renewal delay earlier,
+// at random, so that many holders don't renew in lockstep.
+// The default is 0, which renews exactly on schedule.
+func WithJitter(f float64) Option {
+	return func(l *Lease) {
+		l.jitter = f
+	}
+}
+
+// WithRand sets the random source used by the jitter,
+// allowing tests to produce reproducible delays.
+func WithRand(source *rand.Rand) Option {
+	return func(l *Lease) {
+		l.random = source.Float64
+	}
+}
+
+// WithErrorHandler sets a function called with the errors of failed renewals.
+// Failed renewals are retried until the lease expires.
+func WithErrorHandler(f func(err error)) Option {
+	return func(l *Lease) {
+		l.onError = f
+	}
+}
+
+// Lease is a lease held until it expires, which renews itself in the
+// background at a fraction of its TTL.
+type Lease struct {
+	clock    clock.Clock
+	renew    RenewFunc
+	fraction float64
+	jitter   float64
+	random   func() float64
+	onError  func(err error)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	exited chan struct{}
+
+	mutex  sync.Mutex
+	expiry time.Time
+}
+
+// New returns a Lease acquired now for ttl, renewed with renew until it
+// expires or is stopped.
+func New(c clock.Clock, ttl time.Duration, renew RenewFunc, opts ...Option) *Lease {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &Lease{
+		clock:    c,
+		renew:    renew,
+		fraction: 0.5,
+		random:   rand.Float64,
+		onError:  func(error) {},
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		exited:   make(chan struct{}),
+		expiry:   c.Now().Add(ttl),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	go l.run()
+	return l
+}
+
+// Expiry returns the time at which the lease expires unless renewed.
+func (l *Lease) Expiry() time.Time {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.expiry
+}
+
+// Expired reports whether the lease has expired or was stopped.
+func (l *Lease) Expired() bool {
+	select {
+	case <-l.done:
+		return true
+	default:
+		return !l.clock.Now().Before(l.Expiry())
+	}
+}
+
+// Done returns a channel that's closed when the lease expires or is stopped.
+func (l *Lease) Done() <-chan struct{} {
+	return l.done
+}
+
+// Stop stops renewing the lease and closes its Done channel.
+// It waits for a renewal in progress, whose context is canceled.
+func (l *Lease) Stop() {
+	l.cancel()
+	<-l.exited
+}
+
+func (l *Lease) run() {
+	defer close(l.exited)
+	defer close(l.done)
+
+	for {
+		timer := l.clock.NewTimer(l.delay())
+		select {
+		case <-timer.C():
+		case <-l.ctx.Done():
+			timer.Stop()
+			return
+		}
+
+		if l.Expired() {
+			return
+		}
+
+		expiry := l.Expiry()
+		ctx, cancel := clock.WithDeadline(l.ctx, l.clock, expiry)
+		ttl, err := l.renew(ctx)
+		cancel()
+
+		if l.ctx.Err() != nil || l.Expired() {
+			return
+		}
+
+		if err != nil {
+			l.onError(err)
+			continue
+		}
+
+		l.mutex.Lock()
+		l.expiry = l.clock.Now().Add(ttl)
+		l.mutex.Unlock()
+	}
+}
+
+// delay returns the time until the next renewal: a fraction of the time
+// remaining, or all of it if the fraction rounds down to nothing.
+func (l *Lease) delay() time.Duration {
+	remaining := l.Expiry().Sub(l.clock.Now())
+
+	d := time.Duration(float64(remaining) * l.fraction)
+	if l.jitter > 0 {
+		d -= time.Duration(float64(d) * l.jitter * l.random())
+	}
+
+	if d <= 0 {
+		return remaining
+	}
+	return d
+}
@@ -0,0 +1,146 @@
+package lease_test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+	"github.com/go-toolbelt/clock/lease"
+)
+
+const timeout = 100 * time.Millisecond
+
+func requireRenewed(t *testing.T, renewed <-chan struct{}) {
+	t.Helper()
+
+	select {
+	case <-renewed:
+	case <-time.After(timeout):
+		t.Fatal("timeout: lease was not renewed")
+	}
+}
+
+func TestLease_Renew(t *testing.T) {
+	fake := clock.NewFakeClock()
+	start := fake.Now()
+
+	renewed := make(chan struct{}, 1)
+	l := lease.New(fake, 10*time.Second, func(ctx context.Context) (time.Duration, error) {
+		renewed <- struct{}{}
+		return 10 * time.Second, nil
+	})
+	defer l.Stop()
+
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	fake.Advance(5 * time.Second)
+	requireRenewed(t, renewed)
+
+	// the next renewal is scheduled once the expiry is extended
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	if expiry, expected := l.Expiry(), start.Add(15*time.Second); !expiry.Equal(expected) {
+		t.Errorf("expected expiry at %s got %s", expected, expiry)
+	}
+
+	fake.Advance(5 * time.Second)
+	requireRenewed(t, renewed)
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+
+	if l.Expired() {
+		t.Error("expected the lease to be held")
+	}
+	clocktest.RequireNotClosedFor(t, l.Done(), timeout/10)
+}
+
+func TestLease_Lost(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	errs := make(chan error, 10)
+	failure := errors.New("unavailable")
+	l := lease.New(fake, 10*time.Second, func(ctx context.Context) (time.Duration, error) {
+		return 0, failure
+	}, lease.WithErrorHandler(func(err error) {
+		errs <- err
+	}))
+	defer l.Stop()
+
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	fake.Advance(5 * time.Second)
+
+	select {
+	case err := <-errs:
+		if err != failure {
+			t.Errorf("expected %v got %v", failure, err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout: expected an error")
+	}
+
+	// the failed renewal is retried before the lease expires
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	if deadline := fake.PendingTimers()[0].Deadline; !deadline.Before(l.Expiry()) {
+		t.Errorf("expected a retry before %s got %s", l.Expiry(), deadline)
+	}
+
+	fake.Advance(5 * time.Second)
+	clocktest.RequireClosedWithin(t, l.Done(), timeout)
+
+	if !l.Expired() {
+		t.Error("expected the lease to be expired")
+	}
+}
+
+func TestLease_RenewTimeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	l := lease.New(fake, 10*time.Second, func(ctx context.Context) (time.Duration, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	defer l.Stop()
+
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	fake.Advance(5 * time.Second)
+
+	// the renewal's context expires with the lease
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	fake.Advance(5 * time.Second)
+	clocktest.RequireClosedWithin(t, l.Done(), timeout)
+}
+
+func TestLease_Jitter(t *testing.T) {
+	fake := clock.NewFakeClock()
+	start := fake.Now()
+
+	l := lease.New(fake, 10*time.Second, func(ctx context.Context) (time.Duration, error) {
+		return 10 * time.Second, nil
+	}, lease.WithJitter(0.5), lease.WithRand(rand.New(rand.NewSource(1))))
+	defer l.Stop()
+
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+
+	deadline := fake.PendingTimers()[0].Deadline
+	if deadline.Before(start.Add(2500*time.Millisecond)) || !deadline.Before(start.Add(5*time.Second)) {
+		t.Errorf("expected a renewal between 2.5s and 5s got %s", deadline.Sub(start))
+	}
+}
+
+func TestLease_Stop(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	l := lease.New(fake, 10*time.Second, func(ctx context.Context) (time.Duration, error) {
+		return 10 * time.Second, nil
+	})
+
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	l.Stop()
+
+	clocktest.RequireClosedWithin(t, l.Done(), timeout)
+	if !l.Expired() {
+		t.Error("expected a stopped lease to be expired")
+	}
+	clocktest.VerifyNone(t, fake)
+}
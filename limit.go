@@ -0,0 +1,55 @@
+package clock
+
+import "fmt"
+
+// PendingLimitError reports a sleeper registered on a fake clock built with
+// WithMaxPending while more sleepers than the limit were pending.
+type PendingLimitError struct {
+	// Limit is the maximum number of pending sleepers.
+	Limit int
+
+	// Pending is the number of sleepers pending, including the new one.
+	Pending int
+
+	// Timer describes the sleeper whose registration went over the limit.
+	Timer PendingTimer
+}
+
+func (err *PendingLimitError) Error() string {
+	return fmt.Sprintf("clock: %d sleepers pending, over the limit of %d, registering %s", err.Pending, err.Limit, err.Timer)
+}
+
+// WithMaxPending caps the number of sleepers pending on the clock at n, to
+// catch runaway scheduling loops in tests and long-running simulations
+// before they exhaust memory. A call registering sleepers while more than n
+// are pending is reported to handler, by the goroutine that made it, once
+// the clock's lock is released; the sleepers are registered regardless.
+// If handler is nil, that goroutine panics with the *PendingLimitError.
+func WithMaxPending(n int, handler func(*PendingLimitError)) FakeOption {
+	return func(clock *fakeClock) {
+		clock.maxPending = n
+		clock.onLimit = handler
+	}
+}
+
+// checkLimit records s if it went over the clock's limit of pending
+// sleepers, for unlock to report. The caller holds the clock's mutex.
+func (clock *fakeClock) checkLimit(s *sleeper) {
+	if clock.maxPending <= 0 || len(clock.sleepers) <= clock.maxPending || clock.overLimit != nil {
+		return
+	}
+
+	clock.overLimit = &PendingLimitError{
+		Limit:   clock.maxPending,
+		Pending: len(clock.sleepers),
+		Timer:   clock.pendingTimer(s),
+	}
+}
+
+// reportLimit reports err to the clock's handler, or panics with it.
+func (clock *fakeClock) reportLimit(err *PendingLimitError) {
+	if clock.onLimit == nil {
+		panic(err)
+	}
+	clock.onLimit(err)
+}
@@ -0,0 +1,61 @@
+package clock_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestWithMaxPending(t *testing.T) {
+	var reported []*clock.PendingLimitError
+	fake := clock.NewFakeClock(clock.WithMaxPending(2, func(err *clock.PendingLimitError) {
+		reported = append(reported, err)
+	}))
+
+	fake.After(time.Second)
+	fake.AfterFunc(2*time.Second, func() {})
+	if len(reported) != 0 {
+		t.Fatalf("unexpected reports %v", reported)
+	}
+
+	c := fake.After(3 * time.Second)
+	if len(reported) != 1 {
+		t.Fatalf("expected 1 report got %d", len(reported))
+	}
+	if err := reported[0]; err.Limit != 2 || err.Pending != 3 || err.Timer.Kind != clock.KindAfter || err.Timer.Duration != 3*time.Second {
+		t.Errorf("unexpected report %+v", err)
+	}
+
+	// the sleeper is registered regardless
+	fake.Advance(3 * time.Second)
+	assertSent(t, fake.Now(), c)
+
+	fake.After(time.Second)
+	if len(reported) != 1 {
+		t.Errorf("expected no more reports got %d", len(reported))
+	}
+}
+
+func TestWithMaxPending_Panic(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithMaxPending(1, nil))
+	fake.After(time.Second)
+
+	defer func() {
+		var err *clock.PendingLimitError
+		if !errors.As(recover().(error), &err) {
+			t.Fatalf("expected a *PendingLimitError")
+		}
+		if message := err.Error(); !strings.Contains(message, "2 sleepers pending, over the limit of 1") {
+			t.Errorf("unexpected message %q", message)
+		}
+		if pending := len(fake.PendingTimers()); pending != 2 {
+			t.Errorf("expected 2 pending timers got %d", pending)
+		}
+	}()
+
+	fake.AfterFunc(time.Second, func() {})
+	t.Error("expected a panic")
+}
@@ -0,0 +1,157 @@
+// Package meter measures the rate of events with exponentially weighted moving
+// averages, decayed by a clock.Clock.
+package meter
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// TickInterval is the interval at which the moving averages are updated.
+const TickInterval = 5 * time.Second
+
+// Meter measures the rate of events over the last one, five and fifteen
+// minutes, in events per second, like the load averages of Unix systems.
+//
+// Averages are updated lazily, every TickInterval as measured by the clock,
+// when the Meter is marked or read, so a Meter needs no goroutine.
+type Meter struct {
+	clock clock.Clock
+
+	mutex     sync.Mutex
+	start     time.Time
+	lastTick  time.Time
+	count     int64
+	uncounted int64
+	rate      float64
+	rates     [3]ewma
+}
+
+// New returns a Meter measured by clock.
+func New(clock clock.Clock) *Meter {
+	now := clock.Now()
+	return &Meter{
+		clock:    clock,
+		start:    now,
+		lastTick: now,
+		rates: [3]ewma{
+			newEWMA(1 * time.Minute),
+			newEWMA(5 * time.Minute),
+			newEWMA(15 * time.Minute),
+		},
+	}
+}
+
+// Mark records n events at the current time.
+func (m *Meter) Mark(n int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.tick()
+	m.count += n
+	m.uncounted += n
+}
+
+// Count returns the number of events recorded.
+func (m *Meter) Count() int64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.count
+}
+
+// Rate returns the instantaneous rate: the rate over the last completed tick.
+func (m *Meter) Rate() float64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.tick()
+	return m.rate
+}
+
+// Rate1 returns the one-minute moving average rate.
+func (m *Meter) Rate1() float64 {
+	return m.average(0)
+}
+
+// Rate5 returns the five-minute moving average rate.
+func (m *Meter) Rate5() float64 {
+	return m.average(1)
+}
+
+// Rate15 returns the fifteen-minute moving average rate.
+func (m *Meter) Rate15() float64 {
+	return m.average(2)
+}
+
+// RateMean returns the mean rate since the Meter was created.
+func (m *Meter) RateMean() float64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	elapsed := m.clock.Since(m.start)
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(m.count) / elapsed.Seconds()
+}
+
+func (m *Meter) average(i int) float64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.tick()
+	return m.rates[i].rate
+}
+
+// tick updates the averages for every TickInterval elapsed since the last tick.
+func (m *Meter) tick() {
+	ticks := int64(m.clock.Since(m.lastTick) / TickInterval)
+	if ticks <= 0 {
+		return
+	}
+	m.lastTick = m.lastTick.Add(time.Duration(ticks) * TickInterval)
+
+	// the uncounted events fall in the first tick, and the rest are idle
+	m.rate = float64(m.uncounted) / TickInterval.Seconds()
+	for i := range m.rates {
+		m.rates[i].update(m.rate)
+		m.rates[i].decay(ticks - 1)
+	}
+	m.uncounted = 0
+
+	if ticks > 1 {
+		m.rate = 0
+	}
+}
+
+type ewma struct {
+	alpha float64
+	rate  float64
+	init  bool
+}
+
+func newEWMA(window time.Duration) ewma {
+	return ewma{
+		alpha: 1 - math.Exp(-TickInterval.Seconds()/window.Seconds()),
+	}
+}
+
+func (e *ewma) update(rate float64) {
+	if !e.init {
+		e.rate = rate
+		e.init = true
+		return
+	}
+	e.rate += e.alpha * (rate - e.rate)
+}
+
+// decay applies n idle ticks at once.
+func (e *ewma) decay(n int64) {
+	if n > 0 {
+		e.rate *= math.Pow(1-e.alpha, float64(n))
+	}
+}
@@ -0,0 +1,58 @@
+package meter_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/meter"
+)
+
+func assertRate(t *testing.T, name string, expected, actual float64) {
+	t.Helper()
+
+	if math.Abs(expected-actual) > 1e-6 {
+		t.Errorf("%s: expected %f got %f", name, expected, actual)
+	}
+}
+
+func TestMeter(t *testing.T) {
+	fake := clock.NewFakeClock()
+	m := meter.New(fake)
+
+	m.Mark(50)
+	assertRate(t, "Rate1", 0, m.Rate1())
+
+	fake.Advance(meter.TickInterval)
+	assertRate(t, "Rate", 10, m.Rate())
+	assertRate(t, "Rate1", 10, m.Rate1())
+	assertRate(t, "Rate5", 10, m.Rate5())
+	assertRate(t, "Rate15", 10, m.Rate15())
+	assertRate(t, "RateMean", 10, m.RateMean())
+
+	if count := m.Count(); count != 50 {
+		t.Errorf("expected %d got %d", 50, count)
+	}
+
+	// after a minute without events, the one-minute rate decays by 1/e
+	fake.Advance(1 * time.Minute)
+	assertRate(t, "Rate", 0, m.Rate())
+	assertRate(t, "Rate1", 10/math.E, m.Rate1())
+	assertRate(t, "Rate5", 10*math.Exp(-1.0/5), m.Rate5())
+	assertRate(t, "Rate15", 10*math.Exp(-1.0/15), m.Rate15())
+}
+
+func TestMeter_Steady(t *testing.T) {
+	fake := clock.NewFakeClock()
+	m := meter.New(fake)
+
+	for i := 0; i < 12*15; i++ {
+		m.Mark(5)
+		fake.Advance(meter.TickInterval)
+	}
+
+	assertRate(t, "Rate", 1, m.Rate())
+	assertRate(t, "Rate1", 1, m.Rate1())
+	assertRate(t, "Rate15", 1, m.Rate15())
+}
@@ -0,0 +1,158 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Metronome beats at a period on a grid of instants, which it keeps when its
+// period changes, and which can be shifted or aligned to a reference, unlike
+// a Ticker, whose grid restarts from the time of each Reset.
+type Metronome struct {
+	clock Clock
+	c     chan time.Time
+
+	mutex   sync.Mutex
+	period  time.Duration
+	next    time.Time
+	timer   Timer
+	stopped bool
+}
+
+// NewMetronome returns a Metronome driven by clock, beating every period
+// from now. Like a Ticker, it drops beats that the consumer isn't ready to
+// receive. The period must be greater than zero; if not, NewMetronome will
+// panic.
+func NewMetronome(clock Clock, period time.Duration) *Metronome {
+	if period <= 0 {
+		panic(errNonPositiveInterval)
+	}
+
+	m := &Metronome{
+		clock:  clock,
+		c:      make(chan time.Time, 1),
+		period: period,
+		next:   clock.Now().Add(period),
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.timer = clock.AfterFunc(period, m.beat)
+	return m
+}
+
+// C returns the channel on which the beats are delivered. Each beat is the
+// time it was due, on the grid, rather than the time it was delivered.
+func (m *Metronome) C() <-chan time.Time {
+	return m.c
+}
+
+// Next returns the time of the next beat.
+func (m *Metronome) Next() time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.next
+}
+
+// Period returns the time between beats.
+func (m *Metronome) Period() time.Duration {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.period
+}
+
+// SetPeriod changes the time between beats from the last beat on, so the
+// grid keeps its phase: the next beat is due period after the last one, or
+// on the first instant of the new grid that isn't past. The period must be
+// greater than zero; if not, SetPeriod will panic.
+func (m *Metronome) SetPeriod(period time.Duration) {
+	if period <= 0 {
+		panic(errNonPositiveInterval)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	last := m.next.Add(-m.period)
+	m.period = period
+	m.reschedule(last.Add(period))
+}
+
+// Shift moves the grid by d, later if d is positive, earlier if it's
+// negative. Beats shifted into the past are skipped.
+func (m *Metronome) Shift(d time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.reschedule(m.next.Add(d))
+}
+
+// Align moves the grid so that it goes through t, keeping the period, such
+// as to lock the beats to the timestamps of a media stream. The grid moves
+// by at most half a period, to the instants through t nearest to it.
+func (m *Metronome) Align(t time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// the grid point through t closest to the current next beat
+	offset := t.Sub(m.next) % m.period
+	if offset > m.period/2 {
+		offset -= m.period
+	} else if offset < -m.period/2 {
+		offset += m.period
+	}
+	m.reschedule(m.next.Add(offset))
+}
+
+// Stop turns off the metronome. After Stop, no more beats will be sent.
+func (m *Metronome) Stop() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.stopped = true
+	m.timer.Stop()
+}
+
+// reschedule sets the next beat to next, or to the first beat on its grid
+// that isn't past. The caller holds the metronome's mutex.
+func (m *Metronome) reschedule(next time.Time) {
+	now := m.clock.Now()
+	if next.Before(now) {
+		// skip the whole periods missed at once, then step past now
+		next = next.Add(now.Sub(next) / m.period * m.period)
+		if next.Before(now) {
+			next = next.Add(m.period)
+		}
+	}
+	m.next = next
+
+	if !m.stopped {
+		m.timer.Reset(next.Sub(now))
+	}
+}
+
+func (m *Metronome) beat() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.stopped {
+		return
+	}
+
+	// the grid may have moved while the timer fired
+	now := m.clock.Now()
+	if now.Before(m.next) {
+		m.timer.Reset(m.next.Sub(now))
+		return
+	}
+
+	select {
+	case m.c <- m.next:
+	default:
+	}
+
+	m.reschedule(m.next.Add(m.period))
+}
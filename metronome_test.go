@@ -0,0 +1,94 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func assertNext(t *testing.T, expected time.Time, m *clock.Metronome) {
+	t.Helper()
+
+	if next := m.Next(); !next.Equal(expected) {
+		t.Errorf("expected the next beat at %s got %s", expected, next)
+	}
+}
+
+func TestMetronome(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	m := clock.NewMetronome(fake, time.Second)
+	defer m.Stop()
+
+	c := m.C()
+	assertClockUntil(t, 1, fake)
+	fake.Advance(time.Second)
+	assertSent(t, start.Add(time.Second), c)
+
+	// the period changes from the last beat, keeping the grid
+	m.SetPeriod(500 * time.Millisecond)
+	assertNext(t, start.Add(1500*time.Millisecond), m)
+
+	m.Shift(100 * time.Millisecond)
+	assertNext(t, start.Add(1600*time.Millisecond), m)
+
+	// the nearest grid through 10.25s goes through 1.75s
+	m.Align(start.Add(10250 * time.Millisecond))
+	assertNext(t, start.Add(1750*time.Millisecond), m)
+
+	fake.Advance(750*time.Millisecond - time.Nanosecond)
+	assertNotSent(t, c)
+	fake.Advance(time.Nanosecond)
+	assertSent(t, start.Add(1750*time.Millisecond), c)
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(500 * time.Millisecond)
+	assertSent(t, start.Add(2250*time.Millisecond), c)
+}
+
+func TestMetronome_Behind(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	m := clock.NewMetronome(fake, time.Second)
+	defer m.Stop()
+
+	// the beats missed are skipped, keeping the grid
+	assertClockUntil(t, 1, fake)
+	fake.Advance(3500 * time.Millisecond)
+	assertSent(t, start.Add(time.Second), m.C())
+	assertClockUntil(t, 1, fake)
+	assertNext(t, start.Add(4*time.Second), m)
+
+	// a grid shifted into the past skips the beats it missed
+	m.Shift(-2 * time.Second)
+	assertNext(t, start.Add(4*time.Second), m)
+}
+
+func TestMetronome_FarBehind(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	m := clock.NewMetronome(fake, 2*time.Nanosecond)
+	defer m.Stop()
+
+	// catching up doesn't step through each of the beats missed
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1000*time.Hour + time.Nanosecond)
+	assertSent(t, start.Add(2*time.Nanosecond), m.C())
+	assertClockUntil(t, 1, fake)
+	assertNext(t, start.Add(1000*time.Hour+2*time.Nanosecond), m)
+}
+
+func TestMetronome_Stop(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	m := clock.NewMetronome(fake, time.Second)
+	m.Stop()
+	m.Shift(time.Millisecond)
+
+	fake.Advance(time.Hour)
+	assertNotSent(t, m.C())
+}
@@ -0,0 +1,78 @@
+package clock
+
+import "fmt"
+
+// WithName labels the clock with name, which appears in its String, in the
+// errors returned by Advance, in the PendingTimers it reports, and in the
+// calls logged by Record, so the clocks of a simulation with several nodes
+// can be told apart.
+func WithName(name string) FakeOption {
+	return func(clock *fakeClock) {
+		clock.label = name
+	}
+}
+
+// Named returns a Clock labeled with name, as WithName labels a fake clock,
+// delegating to clock. To keep the methods of a FakeClock, use WithName
+// instead.
+func Named(clock Clock, name string) Clock {
+	return &namedClock{
+		Clock: clock,
+		label: name,
+	}
+}
+
+// Name returns the name of clock, given by WithName or Named, or an empty
+// string if it has none. Decorators report the name of the clock they wrap.
+// It suits labeling metrics and log lines by clock.
+func Name(clock Clock) string {
+	if clock, ok := clock.(interface{ name() string }); ok {
+		return clock.name()
+	}
+	return ""
+}
+
+type namedClock struct {
+	Clock
+	label string
+}
+
+func (clock *namedClock) name() string {
+	return clock.label
+}
+
+func (clock *namedClock) String() string {
+	return fmt.Sprintf("clock %q", clock.label)
+}
+
+func (clock *fakeClock) name() string {
+	return clock.label
+}
+
+// String describes the clock, with its name if it has one.
+func (clock *fakeClock) String() string {
+	if clock.label == "" {
+		return "fake clock"
+	}
+	return fmt.Sprintf("fake clock %q", clock.label)
+}
+
+// on returns a suffix naming the clock in messages, if it has a name.
+func (clock *fakeClock) on() string {
+	if clock.label == "" {
+		return ""
+	}
+	return " on " + clock.String()
+}
+
+func (clock *mappedClock) name() string {
+	return Name(clock.Clock)
+}
+
+func (clock *recordingClock) name() string {
+	return Name(clock.Clock)
+}
+
+func (clock *recoverClock) name() string {
+	return Name(clock.Clock)
+}
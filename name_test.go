@@ -0,0 +1,47 @@
+package clock_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestWithName(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithName("node1"))
+
+	if name := clock.Name(fake); name != "node1" {
+		t.Errorf("expected %q got %q", "node1", name)
+	}
+	if s := fake.(interface{ String() string }).String(); s != `fake clock "node1"` {
+		t.Errorf("expected %q got %q", `fake clock "node1"`, s)
+	}
+
+	if err := fake.Advance(-time.Second); err == nil || !strings.Contains(err.Error(), `"node1"`) {
+		t.Errorf("expected an error naming the clock, got %v", err)
+	}
+
+	fake.After(time.Second)
+	timers := fake.PendingTimers()
+	if len(timers) != 1 || timers[0].Clock != "node1" || !strings.HasPrefix(timers[0].String(), "[node1] ") {
+		t.Errorf("expected a timer of node1 got %v", timers)
+	}
+}
+
+func TestNamed(t *testing.T) {
+	if name := clock.Name(clock.NewRealClock()); name != "" {
+		t.Errorf("expected no name got %q", name)
+	}
+
+	var log clock.CallLog
+	c := clock.Record(clock.Offset(clock.Named(clock.NewFakeClock(), "node2"), time.Hour), &log)
+	if name := clock.Name(c); name != "node2" {
+		t.Errorf("expected %q got %q", "node2", name)
+	}
+
+	c.Now()
+	if calls := log.Calls(); len(calls) != 1 || calls[0].Clock != "node2" {
+		t.Errorf("expected a call to node2 got %v", calls)
+	}
+}
@@ -0,0 +1,45 @@
+package clock
+
+import "time"
+
+// monoOrigin is the time the monotonic readings of NowBoth count from on
+// the real clock.
+var monoOrigin = time.Now()
+
+// NowBoth returns the wall time and a monotonic reading of clock, taken
+// together, so code correlating wall timestamps with monotonic measurements
+// doesn't straddle a step of the wall clock between two reads. The wall time
+// has no monotonic reading of its own; the monotonic reading is nanoseconds
+// since an arbitrary origin, fixed for the clock, and only meaningful
+// relative to other readings from it.
+//
+// On the fake clock, the monotonic reading counts the time the clock was
+// advanced by. On other clocks than the real and fake ones, it's derived
+// from Now.
+func NowBoth(clock Clock) (wall time.Time, mono int64) {
+	if clock, ok := clock.(interface {
+		nowBoth() (time.Time, int64)
+	}); ok {
+		return clock.nowBoth()
+	}
+
+	now := clock.Now()
+	return now.Round(0), int64(now.Sub(monoOrigin))
+}
+
+func (realClock) nowBoth() (time.Time, int64) {
+	now := time.Now()
+	return now.Round(0), int64(now.Sub(monoOrigin))
+}
+
+func (clock *fakeClock) nowBoth() (time.Time, int64) {
+	clock.mutex.RLock()
+	defer clock.mutex.RUnlock()
+
+	return clock.at, int64(clock.at.Sub(clock.origin))
+}
+
+func (clock *sourceClock) nowBoth() (time.Time, int64) {
+	elapsed := clock.elapsed()
+	return clock.wall.Add(elapsed), int64(elapsed)
+}
@@ -0,0 +1,71 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestNowBoth_Real(t *testing.T) {
+	c := clock.NewRealClock()
+
+	wall1, mono1 := clock.NowBoth(c)
+	time.Sleep(10 * time.Millisecond)
+	wall2, mono2 := clock.NowBoth(c)
+
+	if wall1 != wall1.Round(0) {
+		t.Errorf("expected the wall time without a monotonic reading, got %s", wall1)
+	}
+	if d := time.Duration(mono2 - mono1); d < 10*time.Millisecond {
+		t.Errorf("expected at least 10ms between monotonic readings, got %s", d)
+	}
+	if d := wall2.Sub(wall1) - time.Duration(mono2-mono1); d < -time.Second || d > time.Second {
+		t.Errorf("expected wall and monotonic readings to advance together, %s apart", d)
+	}
+}
+
+func TestNowBoth_Fake(t *testing.T) {
+	start := time.Unix(100, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	wall, mono := clock.NowBoth(fake)
+	if !wall.Equal(start) || mono != 0 {
+		t.Errorf("expected %s and 0 got %s and %d", start, wall, mono)
+	}
+
+	fake.Advance(time.Minute)
+	wall, mono = clock.NowBoth(fake)
+	if !wall.Equal(start.Add(time.Minute)) || time.Duration(mono) != time.Minute {
+		t.Errorf("expected %s and %d got %s and %d", start.Add(time.Minute), time.Minute, wall, mono)
+	}
+}
+
+func TestNowBoth_Other(t *testing.T) {
+	fake := clock.NewFakeClockAt(time.Unix(100, 0))
+	c := clock.Offset(fake, time.Hour)
+
+	wall1, mono1 := clock.NowBoth(c)
+	fake.Advance(time.Second)
+	wall2, mono2 := clock.NowBoth(c)
+
+	if !wall1.Equal(time.Unix(100, 0).Add(time.Hour)) {
+		t.Errorf("expected the offset time got %s", wall1)
+	}
+	if d := wall2.Sub(wall1); d != time.Second || time.Duration(mono2-mono1) != d {
+		t.Errorf("expected both readings to advance by 1s, got %s and %s", d, time.Duration(mono2-mono1))
+	}
+}
+
+func TestNowBoth_BootTime(t *testing.T) {
+	c := clock.NewRealClockWith(clock.WithBootTime())
+
+	wall1, mono1 := clock.NowBoth(c)
+	time.Sleep(10 * time.Millisecond)
+	wall2, mono2 := clock.NowBoth(c)
+
+	d, m := wall2.Sub(wall1), time.Duration(mono2-mono1)
+	if m < 10*time.Millisecond || d-m > time.Millisecond || m-d > time.Millisecond {
+		t.Errorf("expected both readings to advance together by at least 10ms, got %s and %s", d, m)
+	}
+}
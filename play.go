@@ -0,0 +1,112 @@
+package clock
+
+import (
+	"errors"
+	"time"
+)
+
+var errNonPositiveRate = errors.New("clock: non-positive rate for Play")
+
+// playInterval is how often, in real time, a playing fake clock advances.
+const playInterval = 10 * time.Millisecond
+
+func (clock *fakeClock) Play(rate float64) {
+	if rate <= 0 {
+		panic(errNonPositiveRate)
+	}
+
+	clock.player.Lock()
+	defer clock.player.Unlock()
+
+	clock.pause()
+	clock.playRate = rate
+	clock.resume()
+}
+
+func (clock *fakeClock) Pause() {
+	clock.player.Lock()
+	defer clock.player.Unlock()
+
+	clock.pause()
+}
+
+func (clock *fakeClock) Resume() {
+	clock.player.Lock()
+	defer clock.player.Unlock()
+
+	if clock.playStop == nil {
+		clock.resume()
+	}
+}
+
+// NewHybridClock returns a fake clock that follows the real clock, starting
+// at the current time and playing at the speed of real time, until Pause is
+// called. It's then advanced by hand, like any fake clock, so debugging
+// sessions and step-through tests can freeze the world mid-flight, until
+// Resume makes it run at the speed of real time again, from where it is.
+func NewHybridClock(opts ...FakeOption) FakeClock {
+	clock := NewFakeClockAt(time.Now().Round(0), opts...)
+	clock.Resume()
+	return clock
+}
+
+// resume starts the playback at the clock's rate, or at the speed of real
+// time if it has none. The caller holds the player mutex.
+func (clock *fakeClock) resume() {
+	rate := clock.playRate
+	if rate == 0 {
+		rate = 1
+	}
+
+	clock.playStop = make(chan struct{})
+	clock.playDone = make(chan struct{})
+	go clock.play(rate, clock.playStop, clock.playDone)
+}
+
+// pause stops the playback and waits for it to end.
+// The caller holds the player mutex.
+func (clock *fakeClock) pause() {
+	if clock.playStop == nil {
+		return
+	}
+
+	close(clock.playStop)
+	<-clock.playDone
+	clock.playStop = nil
+}
+
+// play advances the clock every playInterval by rate times the real time
+// elapsed, until stop is closed, then advances it by the time left.
+// The advances bypass WithStrict, since they're not made by the test.
+func (clock *fakeClock) play(rate float64, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(playInterval)
+	defer ticker.Stop()
+
+	// the time played is computed from the start, so rounding errors don't
+	// accumulate
+	start := time.Now()
+	var played time.Duration
+	advance := func() {
+		target := time.Duration(rate * float64(time.Since(start)))
+
+		defer clock.turn()()
+
+		clock.mutex.Lock()
+		defer clock.unlock()
+
+		clock.advance(target - played)
+		played = target
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			advance()
+		case <-stop:
+			advance()
+			return
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestPlay(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithStrict())
+	start := fake.Now()
+
+	// at 1000x, a minute passes in 60ms of real time
+	after := fake.After(time.Minute)
+	fake.Play(1000)
+
+	select {
+	case at := <-after:
+		if !at.Equal(start.Add(time.Minute)) {
+			t.Errorf("expected %s got %s", start.Add(time.Minute), at)
+		}
+	case <-time.After(time.Second):
+		t.Error("timeout: the clock didn't play")
+	}
+
+	fake.Pause()
+	paused := fake.Now()
+	if d := paused.Sub(start); d < time.Minute {
+		t.Errorf("expected the clock to play at least a minute got %s", d)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	assertClockAt(t, paused, fake)
+}
+
+func TestPlay_Rate(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	fake.Play(1)
+	fake.Play(1e6)
+	defer fake.Pause()
+
+	c := fake.NewTimer(time.Hour).C()
+	select {
+	case <-c:
+	case <-time.After(time.Second):
+		t.Error("timeout: the rate didn't change")
+	}
+}
+
+func TestPlay_NonPositive(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Play to panic")
+		}
+	}()
+
+	clock.NewFakeClock().Play(0)
+}
+
+func TestHybridClock(t *testing.T) {
+	hybrid := clock.NewHybridClock()
+	defer hybrid.Pause()
+
+	if d := time.Since(hybrid.Now()); d < -time.Second || d > time.Second {
+		t.Errorf("expected the hybrid clock to follow the real clock, %s apart", d)
+	}
+
+	// once paused, the clock only moves by hand
+	hybrid.Pause()
+	paused := hybrid.Now()
+	time.Sleep(20 * time.Millisecond)
+	assertClockAt(t, paused, hybrid)
+
+	hybrid.Advance(time.Hour)
+	assertClockAt(t, paused.Add(time.Hour), hybrid)
+
+	hybrid.Resume()
+	time.Sleep(20 * time.Millisecond)
+	if d := hybrid.Since(paused.Add(time.Hour)); d <= 0 {
+		t.Errorf("expected the clock to resume, got %s since the pause", d)
+	}
+}
+
+func TestResume_Rate(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	fake.Play(1e6)
+	fake.Pause()
+	fake.Resume()
+	fake.Resume()
+	defer fake.Pause()
+
+	c := fake.NewTimer(time.Hour).C()
+	select {
+	case <-c:
+	case <-time.After(time.Second):
+		t.Error("timeout: the clock didn't resume at its rate")
+	}
+}
@@ -0,0 +1,16 @@
+package clock
+
+// Prioritized returns a view of clock whose sleepers wake before those of
+// lower priority due at the same time, such as deadline checks that must
+// fire before heartbeats at the same instant, instead of in the order they
+// were scheduled. Sleepers created through clock itself have priority 0.
+//
+// Priorities only apply to fake clocks, whose views can't be asserted back
+// to a FakeClock, like ReadOnly. Prioritized returns other clocks as is,
+// since their timers due together fire in no particular order.
+func Prioritized(clock Clock, priority int) Clock {
+	if view, ok := view(clock, func(l *labels) { l.priority = priority }); ok {
+		return view
+	}
+	return clock
+}
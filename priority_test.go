@@ -0,0 +1,53 @@
+package clock_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestPrioritized(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithSerialCallbacks(clock.DeadlineOrder))
+	checks := clock.Prioritized(fake, 1)
+
+	var mutex sync.Mutex
+	var order []string
+	done := make(chan struct{}, 3)
+	record := func(name string) func() {
+		return func() {
+			mutex.Lock()
+			order = append(order, name)
+			mutex.Unlock()
+			done <- struct{}{}
+		}
+	}
+
+	// the deadline check fires before the heartbeat scheduled first at the
+	// same instant, but after anything due earlier
+	fake.AfterFunc(time.Second, record("heartbeat"))
+	checks.AfterFunc(time.Second, record("check"))
+	fake.AfterFunc(time.Second-time.Nanosecond, record("early"))
+
+	if timers := fake.PendingTimers(); timers[1].Priority+timers[2].Priority != 1 {
+		t.Errorf("expected the priorities in the pending timers, got %v", timers)
+	}
+
+	fake.Advance(time.Second)
+	for i := 0; i < 3; i++ {
+		<-done
+	}
+
+	if expected := "[early check heartbeat]"; fmt.Sprint(order) != expected {
+		t.Errorf("expected %s got %v", expected, order)
+	}
+
+	if _, ok := checks.(clock.FakeClock); ok {
+		t.Error("expected the view not to be a FakeClock")
+	}
+	if real := clock.NewRealClock(); clock.Prioritized(real, 1) != real {
+		t.Error("expected other clocks returned as is")
+	}
+}
@@ -0,0 +1,267 @@
+// Package ratelimit limits the rate of events per key, such as per tenant or
+// per client address, with token buckets refilled by a clock.Clock.
+package ratelimit
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// Limiter holds a token bucket per key, each refilled at rate tokens per
+// second up to burst tokens, so a key may have burst events at once, and
+// rate events per second on average.
+//
+// The bucket of a key is evicted once the key has been idle for the
+// limiter's TTL, and its bucket has refilled, so eviction bounds the memory
+// used by keys that come and go without ever changing what a key is allowed.
+// Eviction is driven by a single timer of the clock, armed for the earliest
+// eviction, rather than by a timer or a goroutine per key.
+//
+// It is safe for concurrent use.
+type Limiter[K comparable] struct {
+	clock clock.Clock
+	rate  float64
+	burst float64
+	ttl   time.Duration
+
+	mutex   sync.Mutex
+	buckets map[K]*bucket[K]
+	queue   bucketQueue[K]
+	timer   clock.Timer
+	armed   time.Time
+	stopped bool
+}
+
+type bucket[K comparable] struct {
+	key    K
+	tokens float64
+	last   time.Time // the time tokens was last updated
+	used   time.Time // the time the key was last used
+
+	// evict is the time the bucket was queued for eviction at; it may
+	// have been used since, which is checked when it's due
+	evict time.Time
+	index int
+}
+
+// New returns a Limiter refilled by clock at rate tokens per second, up to
+// burst tokens, which evicts the keys idle for ttl.
+// It panics if rate <= 0 or burst < 1.
+func New[K comparable](clock clock.Clock, rate float64, burst int, ttl time.Duration) *Limiter[K] {
+	if rate <= 0 || burst < 1 {
+		panic("ratelimit: non-positive rate or burst for New")
+	}
+
+	return &Limiter[K]{
+		clock:   clock,
+		rate:    rate,
+		burst:   float64(burst),
+		ttl:     ttl,
+		buckets: map[K]*bucket[K]{},
+	}
+}
+
+// Allow reports whether an event for key may happen now, taking a token
+// from its bucket if so.
+func (l *Limiter[K]) Allow(key K) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	b := l.bucket(key)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Wait waits until an event for key may happen, taking a token from its
+// bucket. It returns the error of ctx if ctx is done first, giving the token
+// back. Waiters are served in the order they called Wait.
+func (l *Limiter[K]) Wait(ctx context.Context, key K) error {
+	l.mutex.Lock()
+	b := l.bucket(key)
+	// the token is taken right away, leaving a debt that the refill pays
+	// before the next waiter's
+	b.tokens--
+	d := time.Duration(math.Ceil(-b.tokens / l.rate * float64(time.Second)))
+	l.mutex.Unlock()
+
+	if d <= 0 {
+		return nil
+	}
+
+	timer := l.clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if b, ok := l.buckets[key]; ok {
+		l.refill(b)
+		b.tokens = math.Min(b.tokens+1, l.burst)
+	}
+	return ctx.Err()
+}
+
+// Tokens returns the number of tokens in the bucket of key, which is
+// negative while Wait callers are waiting on it.
+func (l *Limiter[K]) Tokens(key K) float64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		return l.burst
+	}
+	l.refill(b)
+	return b.tokens
+}
+
+// Len returns the number of keys that have a bucket.
+func (l *Limiter[K]) Len() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return len(l.buckets)
+}
+
+// Stop stops the eviction of idle keys.
+func (l *Limiter[K]) Stop() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.stopped = true
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+}
+
+// bucket returns the refilled bucket of key, creating a full one if needed,
+// and marks it used. The caller holds the mutex.
+func (l *Limiter[K]) bucket(key K) *bucket[K] {
+	now := l.clock.Now()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket[K]{
+			key:    key,
+			tokens: l.burst,
+			last:   now,
+		}
+		l.buckets[key] = b
+		b.used = now
+		b.evict = l.evictAt(b)
+		heap.Push(&l.queue, b)
+		l.schedule()
+		return b
+	}
+
+	l.refill(b)
+	b.used = now
+	return b
+}
+
+// refill adds the tokens earned since the bucket was last updated.
+// The caller holds the mutex.
+func (l *Limiter[K]) refill(b *bucket[K]) {
+	now := l.clock.Now()
+	b.tokens = math.Min(b.tokens+now.Sub(b.last).Seconds()*l.rate, l.burst)
+	b.last = now
+}
+
+// evictAt returns the time the bucket may be evicted: once its key has been
+// idle for the TTL, and the bucket is full.
+// The caller holds the mutex.
+func (l *Limiter[K]) evictAt(b *bucket[K]) time.Time {
+	at := b.used.Add(l.ttl)
+	full := b.last.Add(time.Duration(math.Ceil((l.burst - b.tokens) / l.rate * float64(time.Second))))
+	if full.After(at) {
+		return full
+	}
+	return at
+}
+
+// schedule arms the timer for the earliest eviction.
+// The caller holds the mutex.
+func (l *Limiter[K]) schedule() {
+	if l.stopped || len(l.queue) == 0 {
+		return
+	}
+
+	next := l.queue[0].evict
+	if next.Equal(l.armed) {
+		return
+	}
+	l.armed = next
+
+	d := next.Sub(l.clock.Now())
+	if l.timer == nil {
+		l.timer = l.clock.AfterFunc(d, l.expire)
+		return
+	}
+	l.timer.Reset(d)
+}
+
+func (l *Limiter[K]) expire() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.armed = time.Time{}
+	now := l.clock.Now()
+	for len(l.queue) > 0 && !now.Before(l.queue[0].evict) {
+		b := l.queue[0]
+
+		// the key was used since it was queued, so it's queued again
+		// for its new eviction time rather than removed
+		l.refill(b)
+		if at := l.evictAt(b); now.Before(at) {
+			b.evict = at
+			heap.Fix(&l.queue, 0)
+			continue
+		}
+
+		heap.Pop(&l.queue)
+		delete(l.buckets, b.key)
+	}
+	l.schedule()
+}
+
+// bucketQueue is a min-heap of buckets ordered by eviction time.
+type bucketQueue[K comparable] []*bucket[K]
+
+func (q bucketQueue[K]) Len() int { return len(q) }
+
+func (q bucketQueue[K]) Less(i, j int) bool { return q[i].evict.Before(q[j].evict) }
+
+func (q bucketQueue[K]) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *bucketQueue[K]) Push(x any) {
+	b := x.(*bucket[K])
+	b.index = len(*q)
+	*q = append(*q, b)
+}
+
+func (q *bucketQueue[K]) Pop() any {
+	old := *q
+	n := len(old)
+	b := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return b
+}
@@ -0,0 +1,150 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/ratelimit"
+)
+
+// assertLen waits for the limiter's eviction timer to leave n keys.
+func assertLen(t *testing.T, n int, l *ratelimit.Limiter[string]) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for l.Len() != n && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if actual := l.Len(); actual != n {
+		t.Errorf("expected %d keys got %d", n, actual)
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	l := ratelimit.New[string](fake, 1, 2, time.Minute)
+	defer l.Stop()
+
+	for i, expected := range []bool{true, true, false} {
+		if allowed := l.Allow("a"); allowed != expected {
+			t.Errorf("event %d: expected %t got %t", i, expected, allowed)
+		}
+	}
+
+	// keys have their own buckets
+	if !l.Allow("b") {
+		t.Error("expected b to be allowed")
+	}
+
+	fake.Advance(time.Second)
+	if !l.Allow("a") {
+		t.Error("expected a to be allowed after a refill")
+	}
+	if l.Allow("a") {
+		t.Error("expected a to be limited")
+	}
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	l := ratelimit.New[string](fake, 2, 1, time.Minute)
+	defer l.Stop()
+
+	if err := l.Wait(context.Background(), "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- l.Wait(context.Background(), "a")
+	}()
+
+	fake.BlockUntil(2)
+	fake.Advance(499 * time.Millisecond)
+	select {
+	case err := <-done:
+		t.Fatalf("Wait returned early: %v", err)
+	default:
+	}
+
+	fake.Advance(time.Millisecond)
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLimiter_WaitCanceled(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	l := ratelimit.New[string](fake, 1, 1, time.Minute)
+	defer l.Stop()
+
+	l.Allow("a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- l.Wait(ctx, "a")
+	}()
+
+	fake.BlockUntil(2)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v got %v", context.Canceled, err)
+	}
+
+	// the token taken by the canceled Wait was given back
+	if tokens := l.Tokens("a"); tokens != 0 {
+		t.Errorf("expected %d tokens got %g", 0, tokens)
+	}
+}
+
+func TestLimiter_Eviction(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	l := ratelimit.New[string](fake, 1, 10, time.Minute)
+	defer l.Stop()
+
+	l.Allow("a")
+	l.Allow("b")
+
+	// a stays in use
+	fake.Advance(30 * time.Second)
+	l.Allow("a")
+
+	fake.BlockUntil(1)
+	fake.Advance(30 * time.Second)
+	assertLen(t, 1, l)
+
+	fake.BlockUntil(1)
+	fake.Advance(30 * time.Second)
+	assertLen(t, 0, l)
+}
+
+func TestLimiter_EvictionRefill(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	l := ratelimit.New[string](fake, 0.01, 1, time.Minute)
+	defer l.Stop()
+
+	// a isn't evicted before its bucket refills, which would refill it early
+	l.Allow("a")
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Minute)
+	fake.BlockUntil(1)
+	if n := l.Len(); n != 1 {
+		t.Errorf("expected %d keys got %d", 1, n)
+	}
+	if tokens := l.Tokens("a"); tokens >= 1 {
+		t.Errorf("expected less than a token got %g", tokens)
+	}
+
+	fake.Advance(40 * time.Second)
+	assertLen(t, 0, l)
+}
@@ -0,0 +1,38 @@
+package clock
+
+import "time"
+
+func (clock *fakeClock) ReadOnly() Clock {
+	return &readOnlyClock{
+		Clock: clock,
+		fake:  clock,
+	}
+}
+
+// readOnlyClock exposes the Clock methods of a fake clock only, so code
+// holding it can't assert it back to a FakeClock. It keeps the optional
+// behaviors of the fake clock that don't change its time.
+type readOnlyClock struct {
+	Clock
+	fake *fakeClock
+}
+
+func (clock *readOnlyClock) name() string {
+	return clock.fake.name()
+}
+
+func (clock *readOnlyClock) String() string {
+	return clock.fake.String()
+}
+
+func (clock *readOnlyClock) nowBoth() (time.Time, int64) {
+	return clock.fake.nowBoth()
+}
+
+func (clock *readOnlyClock) watchSteps(f func(from, to time.Time)) func() {
+	return clock.fake.watchSteps(f)
+}
+
+func (clock *readOnlyClock) watchSuspends(f func(Suspension)) func() {
+	return clock.fake.watchSuspends(f)
+}
@@ -0,0 +1,49 @@
+package clock_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestReadOnly(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithName("harness"))
+	view := fake.ReadOnly()
+
+	if _, ok := view.(clock.FakeClock); ok {
+		t.Fatal("expected the view not to be a FakeClock")
+	}
+	if _, ok := view.(interface{ Advance(time.Duration) error }); ok {
+		t.Fatal("expected the view not to Advance")
+	}
+	if name := clock.Name(view); name != "harness" {
+		t.Errorf("expected the name of the clock got %q", name)
+	}
+
+	c := view.After(time.Second)
+	timers := fake.PendingTimers()
+	if len(timers) != 1 || !strings.Contains(timers[0].Caller, "readonly_test.go") {
+		t.Fatalf("expected the sleeper on the fake clock, from the test, got %v", timers)
+	}
+
+	fake.Advance(time.Second)
+	assertSent(t, fake.Now(), c)
+	if now := view.Now(); !now.Equal(fake.Now()) {
+		t.Errorf("expected %s got %s", fake.Now(), now)
+	}
+
+	// steps of the fake clock are seen through the view
+	watcher := clock.NewJumpWatcher(view, time.Minute)
+	defer watcher.Stop()
+	fake.SetTime(fake.Now().Add(time.Hour))
+	select {
+	case jump := <-watcher.C():
+		if jump.Offset() != time.Hour {
+			t.Errorf("expected a jump of 1h got %s", jump.Offset())
+		}
+	default:
+		t.Error("expected a jump")
+	}
+}
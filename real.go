@@ -1,13 +1,14 @@
 package clock
 
 import (
+	"runtime"
 	"time"
 )
 
 type realClock struct{}
 
 func NewRealClock() Clock {
-	return realClock{}
+	return withSlack(realClock{}, loadDefaultSlack())
 }
 
 // Now returns the current local time.
@@ -19,17 +20,43 @@ func (realClock) Since(t time.Time) time.Duration {
 	return time.Since(t)
 }
 
+// Sleep pauses the current goroutine for at least the duration d.
+// A zero or negative duration yields the processor to other goroutines.
 func (realClock) Sleep(d time.Duration) {
+	if d <= 0 {
+		runtime.Gosched()
+		return
+	}
 	time.Sleep(d)
 }
 
+// Tick is like time.Tick, except that its ticker is stopped once the
+// returned function is garbage collected, so keep the function, not just
+// the channel, for as long as ticks are received. Code that stops ticking
+// at a known point should use TickStop instead.
 func (realClock) Tick(d time.Duration) func() <-chan time.Time {
-	// nolint: staticcheck
-	c := time.Tick(d)
+	if d <= 0 {
+		return func() <-chan time.Time { return nil }
+	}
+
+	// the ticker is stopped by a finalizer on a holder only the returned
+	// function references, since a finalizer on the ticker itself would
+	// never run while the runtime holds it
+	holder := &realTick{time.NewTicker(d)}
+	runtime.SetFinalizer(holder, (*realTick).stop)
+	return func() <-chan time.Time { return holder.ticker.C }
+}
+
+type realTick struct {
+	ticker *time.Ticker
+}
 
-	return func() <-chan time.Time { return c }
+func (tick *realTick) stop() {
+	tick.ticker.Stop()
 }
 
+// After returns time.After(d). Its timer isn't released until it fires,
+// so waits that may be abandoned should use AfterStop instead.
 func (realClock) After(d time.Duration) <-chan time.Time {
 	return time.After(d)
 }
@@ -0,0 +1,94 @@
+package clock
+
+import "time"
+
+// A RealOption configures a real clock built by NewRealClockWith.
+type RealOption func(*realOptions)
+
+type realOptions struct {
+	source func() time.Duration
+	poll   time.Duration
+	slack  time.Duration
+}
+
+// defaultPoll is how often the timers of a real clock reading another
+// source than Go's monotonic clock recheck it, by default.
+const defaultPoll = time.Second
+
+// NewRealClockWith returns a real clock configured by opts.
+// Without options, it's the clock returned by NewRealClock.
+func NewRealClockWith(opts ...RealOption) Clock {
+	options := realOptions{
+		poll:  defaultPoll,
+		slack: loadDefaultSlack(),
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.source == nil {
+		return withSlack(realClock{}, options.slack)
+	}
+	return withSlack(newSourceClock(options.source, options.poll), options.slack)
+}
+
+// WithBootTime makes the clock measure time with CLOCK_BOOTTIME on Linux,
+// which keeps counting while the system is suspended, unlike the monotonic
+// clock behind Go's timers. After a resume, Since includes the time spent
+// suspended, and timers due during the suspend fire within the poll interval
+// (see WithPoll), instead of waiting out their remaining time again.
+//
+// Now then returns the wall time when the clock was built plus the boot time
+// elapsed since, so it drifts from time.Now if the wall clock is stepped.
+// Use it to measure and compare times, not to display them.
+//
+// Elsewhere, or on kernels without CLOCK_BOOTTIME, the option is ignored,
+// and the clock behaves across suspends as Go's monotonic clock does on the
+// platform. Of WithBootTime and WithRawMonotonic, the last one given applies.
+func WithBootTime() RealOption {
+	return func(options *realOptions) {
+		if source := bootTimeSource(); source != nil {
+			options.source = source
+		}
+	}
+}
+
+// WithRawMonotonic makes the clock measure time with CLOCK_MONOTONIC_RAW on
+// Linux, which NTP doesn't slew: durations are counted at the rate of the
+// hardware oscillator, even while NTP is correcting the system clock.
+// Benchmarks and drift estimates read it to see the oscillator undistorted;
+// its seconds may be off from true seconds by the oscillator's error.
+//
+// As with WithBootTime, Now then returns the wall time when the clock was
+// built plus the time elapsed since, and timers recheck the source every
+// poll interval. Elsewhere, or on kernels without CLOCK_MONOTONIC_RAW,
+// the option is ignored.
+func WithRawMonotonic() RealOption {
+	return func(options *realOptions) {
+		if source := rawMonotonicSource(); source != nil {
+			options.source = source
+		}
+	}
+}
+
+// WithPoll sets how often the timers of a clock reading another source than
+// Go's monotonic clock, such as WithBootTime, recheck it. The default is one
+// second. A non-positive interval disables polling: timers then wait for
+// their duration on Go's monotonic clock, before checking the source.
+func WithPoll(d time.Duration) RealOption {
+	return func(options *realOptions) {
+		options.poll = d
+	}
+}
+
+// WithSlack lets the timers, sleeps and tickers of the clock wake up to
+// slack late, as kernel timer slack does, so that wakeups due close together
+// are batched: deadlines are rounded up to a grid of multiples of slack
+// shared by all clocks, and ticker periods to a multiple of slack.
+// A non-positive slack makes timers precise. The default is set by
+// SetDefaultSlack.
+func WithSlack(slack time.Duration) RealOption {
+	return func(options *realOptions) {
+		options.slack = slack
+	}
+}
@@ -0,0 +1,166 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestNewRealClockWith(t *testing.T) {
+	for name, c := range map[string]clock.Clock{
+		"default":  clock.NewRealClockWith(),
+		"boottime": clock.NewRealClockWith(clock.WithBootTime()),
+		"no poll":  clock.NewRealClockWith(clock.WithBootTime(), clock.WithPoll(0)),
+		"raw":      clock.NewRealClockWith(clock.WithRawMonotonic()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if d := c.Now().Sub(time.Now()); d < -time.Second || d > time.Second {
+				t.Errorf("expected Now close to time.Now, %s apart", d)
+			}
+
+			start := c.Now()
+			c.Sleep(10 * time.Millisecond)
+			if elapsed := c.Since(start); elapsed < 10*time.Millisecond {
+				t.Errorf("expected at least 10ms to elapse, got %s", elapsed)
+			}
+
+			select {
+			case <-c.After(10 * time.Millisecond):
+			case <-time.After(time.Second):
+				t.Errorf("expected After to fire")
+			}
+		})
+	}
+}
+
+func TestWithBootTime_Timer(t *testing.T) {
+	c := clock.NewRealClockWith(clock.WithBootTime(), clock.WithPoll(5*time.Millisecond))
+
+	timer := c.NewTimer(time.Hour)
+	if !timer.Stop() {
+		t.Errorf("expected Stop to report an active timer")
+	}
+	if timer.Stop() {
+		t.Errorf("expected Stop to report a stopped timer")
+	}
+
+	start := c.Now()
+	if timer.Reset(20 * time.Millisecond) {
+		t.Errorf("expected Reset to report a stopped timer")
+	}
+	select {
+	case at := <-timer.C():
+		if d := at.Sub(start); d < 20*time.Millisecond {
+			t.Errorf("expected the timer to fire after 20ms, fired after %s", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected the timer to fire")
+	}
+	if timer.Stop() {
+		t.Errorf("expected Stop to report a fired timer")
+	}
+}
+
+func TestWithBootTime_AfterFunc(t *testing.T) {
+	c := clock.NewRealClockWith(clock.WithBootTime())
+
+	done := make(chan struct{})
+	c.AfterFunc(10*time.Millisecond, func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected the callback to run")
+	}
+
+	stopped := c.AfterFunc(10*time.Millisecond, func() { t.Errorf("expected the stopped callback not to run") })
+	stopped.Stop()
+	time.Sleep(30 * time.Millisecond)
+}
+
+func TestWithBootTime_Ticker(t *testing.T) {
+	c := clock.NewRealClockWith(clock.WithBootTime())
+
+	ticker := c.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+
+	var last time.Time
+	for i := 0; i < 3; i++ {
+		select {
+		case at := <-ticker.C():
+			if !at.After(last) {
+				t.Errorf("expected tick %d after %s, got %s", i, last, at)
+			}
+			last = at
+		case <-time.After(time.Second):
+			t.Fatalf("expected tick %d", i)
+		}
+	}
+
+	ticker.Reset(time.Hour)
+	select {
+	case <-ticker.C():
+	default:
+	}
+	select {
+	case at := <-ticker.C():
+		t.Errorf("expected no tick after Reset, got %s", at)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if tick := c.Tick(0); tick() != nil {
+		t.Errorf("expected a nil channel for a non-positive Tick")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected NewTicker(0) to panic")
+		}
+	}()
+	c.NewTicker(0)
+}
+
+// assertSlack checks that short timers of c wait for the next multiple of
+// a 100ms slack, together.
+func assertSlack(t *testing.T, c clock.Clock) {
+	t.Helper()
+
+	// waking at a multiple of the slack leaves most of it before the next
+	c.Sleep(time.Millisecond)
+
+	start := time.Now()
+	first, second := c.After(time.Millisecond), c.After(40*time.Millisecond)
+
+	var fired [2]time.Time
+	for i, ch := range []<-chan time.Time{first, second} {
+		select {
+		case fired[i] = <-ch:
+		case <-time.After(time.Second):
+			t.Fatalf("expected timer %d to fire", i)
+		}
+	}
+
+	if d := fired[0].Sub(start); d < 50*time.Millisecond {
+		t.Errorf("expected a 1ms timer to wait for the slack, fired after %s", d)
+	}
+	if d := fired[1].Sub(fired[0]); d > 20*time.Millisecond {
+		t.Errorf("expected the timers to fire together, fired %s apart", d)
+	}
+}
+
+func TestWithSlack(t *testing.T) {
+	assertSlack(t, clock.NewRealClockWith(clock.WithSlack(100*time.Millisecond)))
+}
+
+func TestSetDefaultSlack(t *testing.T) {
+	clock.SetDefaultSlack(100 * time.Millisecond)
+	defer clock.SetDefaultSlack(0)
+
+	assertSlack(t, clock.NewRealClock())
+
+	precise := clock.NewRealClockWith(clock.WithSlack(0))
+	start := time.Now()
+	<-precise.After(time.Millisecond)
+	if d := time.Since(start); d > 50*time.Millisecond {
+		t.Errorf("expected WithSlack(0) to override the default, fired after %s", d)
+	}
+}
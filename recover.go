@@ -0,0 +1,66 @@
+package clock
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// CallbackPanic describes a panic recovered from an AfterFunc callback.
+type CallbackPanic struct {
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Caller is the file:line of the call to AfterFunc that created the timer.
+	Caller string
+
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+func (p *CallbackPanic) Error() string {
+	return fmt.Sprintf("clock: panic in AfterFunc callback from %s: %v", p.Caller, p.Value)
+}
+
+// RecoverCallbacks returns a Clock whose AfterFunc callbacks recover from
+// panics, reporting them to handler instead of crashing the process.
+//
+// For a fake clock, use the WithPanicHandler option instead,
+// which keeps the methods of FakeClock.
+func RecoverCallbacks(clock Clock, handler func(*CallbackPanic)) Clock {
+	return &recoverClock{
+		Clock:   clock,
+		handler: handler,
+	}
+}
+
+type recoverClock struct {
+	Clock
+	handler func(*CallbackPanic)
+}
+
+func (clock *recoverClock) AfterFunc(d time.Duration, f func()) Timer {
+	pc := caller(1)
+	return clock.Clock.AfterFunc(d, func() {
+		callRecover(f, pc, clock.handler)
+	})
+}
+
+// callRecover calls f, reporting a panic to handler if it's set.
+func callRecover(f func(), pc uintptr, handler func(*CallbackPanic)) {
+	if handler == nil {
+		f()
+		return
+	}
+
+	defer func() {
+		if value := recover(); value != nil {
+			handler(&CallbackPanic{
+				Value:  value,
+				Caller: callerString(pc),
+				Stack:  debug.Stack(),
+			})
+		}
+	}()
+	f()
+}
@@ -0,0 +1,51 @@
+package clock_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func assertPanic(t *testing.T, panics <-chan *clock.CallbackPanic) {
+	t.Helper()
+
+	select {
+	case p := <-panics:
+		if p.Value != "boom" {
+			t.Errorf("expected %q got %v", "boom", p.Value)
+		}
+		if !strings.Contains(p.Caller, "recover_test.go:") {
+			t.Errorf("expected the caller in recover_test.go got %s", p.Caller)
+		}
+		if len(p.Stack) == 0 {
+			t.Error("expected a stack trace")
+		}
+	case <-time.After(sentTimeout):
+		t.Fatal("timeout: panic was not reported")
+	}
+}
+
+func TestWithPanicHandler(t *testing.T) {
+	panics := make(chan *clock.CallbackPanic, 1)
+	fake := clock.NewFakeClock(clock.WithPanicHandler(func(p *clock.CallbackPanic) {
+		panics <- p
+	}))
+
+	fake.AfterFunc(1*time.Second, func() { panic("boom") })
+	fake.Advance(1 * time.Second)
+
+	assertPanic(t, panics)
+}
+
+func TestRecoverCallbacks(t *testing.T) {
+	panics := make(chan *clock.CallbackPanic, 1)
+	c := clock.RecoverCallbacks(clock.NewRealClock(), func(p *clock.CallbackPanic) {
+		panics <- p
+	})
+
+	c.AfterFunc(1*time.Millisecond, func() { panic("boom") })
+
+	assertPanic(t, panics)
+}
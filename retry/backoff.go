@@ -0,0 +1,85 @@
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// A Backoff returns the delay before the given retry.
+// The first retry, which follows the first failed attempt, is retry 1.
+type Backoff func(retry int) time.Duration
+
+// Constant returns a Backoff that always waits d.
+func Constant(d time.Duration) Backoff {
+	return func(int) time.Duration {
+		return d
+	}
+}
+
+// Exponential returns a Backoff that waits initial before the first retry,
+// multiplying the delay by multiplier on every following retry, up to max if it is positive.
+func Exponential(initial, max time.Duration, multiplier float64) Backoff {
+	return func(retry int) time.Duration {
+		d := float64(initial) * math.Pow(multiplier, float64(retry-1))
+		if max > 0 && d > float64(max) {
+			return max
+		}
+		if d > math.MaxInt64 {
+			return math.MaxInt64
+		}
+		return time.Duration(d)
+	}
+}
+
+// A Jitter randomizes a delay computed by a Backoff.
+type Jitter func(d time.Duration, rand Rand) time.Duration
+
+// A Rand is a source of random numbers used by a Jitter.
+type Rand interface {
+	// Int63n returns a non-negative pseudo-random number in [0,n).
+	Int63n(n int64) int64
+}
+
+// NoJitter returns delays unchanged.
+func NoJitter(d time.Duration, _ Rand) time.Duration {
+	return d
+}
+
+// FullJitter returns a random delay between zero and d.
+func FullJitter(d time.Duration, rand Rand) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// EqualJitter returns a random delay between d/2 and d.
+func EqualJitter(d time.Duration, rand Rand) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// lockedRand makes a *rand.Rand safe for concurrent use.
+type lockedRand struct {
+	mutex sync.Mutex
+	rand  *rand.Rand
+}
+
+func (r *lockedRand) Int63n(n int64) int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.rand.Int63n(n)
+}
+
+// globalRand uses the goroutine-safe top-level functions of math/rand.
+type globalRand struct{}
+
+func (globalRand) Int63n(n int64) int64 {
+	return rand.Int63n(n)
+}
@@ -0,0 +1,65 @@
+package retry
+
+import (
+	"math/rand"
+	"time"
+)
+
+// An Option configures a Retrier.
+type Option func(*Retrier)
+
+// WithBackoff sets the delay between attempts.
+// The default is Exponential(100*time.Millisecond, 10*time.Second, 2).
+func WithBackoff(backoff Backoff) Option {
+	return func(r *Retrier) {
+		r.backoff = backoff
+	}
+}
+
+// WithJitter sets how delays are randomized. The default is NoJitter.
+func WithJitter(jitter Jitter) Option {
+	return func(r *Retrier) {
+		r.jitter = jitter
+	}
+}
+
+// WithRand sets the random source used by the jitter,
+// allowing tests to produce reproducible delays.
+func WithRand(source *rand.Rand) Option {
+	return func(r *Retrier) {
+		r.rand = &lockedRand{rand: source}
+	}
+}
+
+// WithMaxAttempts limits the number of attempts, including the first one.
+// Zero, the default, means no limit.
+func WithMaxAttempts(n int) Option {
+	return func(r *Retrier) {
+		r.maxAttempts = n
+	}
+}
+
+// WithMaxElapsed stops retrying once a retry would start later than d
+// after the first attempt, as measured by the clock.
+// Zero, the default, means no limit.
+func WithMaxElapsed(d time.Duration) Option {
+	return func(r *Retrier) {
+		r.maxElapsed = d
+	}
+}
+
+// WithRetryIf sets which errors are retried.
+// By default every error is retried, except those wrapped by Permanent.
+func WithRetryIf(retryIf func(error) bool) Option {
+	return func(r *Retrier) {
+		r.retryIf = retryIf
+	}
+}
+
+// WithOnRetry sets a hook called after every failed attempt that will be retried,
+// before waiting for the retry's delay.
+func WithOnRetry(onRetry func(Attempt)) Option {
+	return func(r *Retrier) {
+		r.onRetry = onRetry
+	}
+}
@@ -0,0 +1,169 @@
+// Package retry retries operations with backoff, waiting on a clock.Clock.
+//
+// Every wait goes through the clock, so retry behavior,
+// including elapsed time budgets, can be tested by advancing a fake clock.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// A Retrier retries operations according to its options.
+// It is safe for concurrent use.
+type Retrier struct {
+	clock       clock.Clock
+	backoff     Backoff
+	jitter      Jitter
+	rand        Rand
+	maxAttempts int
+	maxElapsed  time.Duration
+	retryIf     func(error) bool
+	onRetry     func(Attempt)
+}
+
+// New returns a Retrier waiting on clock.
+func New(clock clock.Clock, opts ...Option) *Retrier {
+	r := &Retrier{
+		clock:   clock,
+		backoff: Exponential(100*time.Millisecond, 10*time.Second, 2),
+		jitter:  NoJitter,
+		rand:    globalRand{},
+		retryIf: func(error) bool { return true },
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// An Attempt describes a failed attempt passed to the WithOnRetry hook.
+type Attempt struct {
+	// Number is the attempt's number, starting at 1.
+	Number int
+
+	// Err is the error returned by the attempt.
+	Err error
+
+	// Delay is the wait before the next attempt.
+	Delay time.Duration
+
+	// Elapsed is the time since the first attempt started.
+	Elapsed time.Duration
+}
+
+// Error is returned by Do when it gives up.
+type Error struct {
+	// Attempts is the number of attempts made.
+	Attempts int
+
+	// Elapsed is the time since the first attempt started.
+	Elapsed time.Duration
+
+	// Err is the error of the last attempt,
+	// or the context's error if it ended while waiting to retry.
+	Err error
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("retry: gave up after %d attempts in %s: %v", err.Attempts, err.Elapsed, err.Err)
+}
+
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+type permanentError struct {
+	err error
+}
+
+func (err *permanentError) Error() string {
+	return err.err.Error()
+}
+
+func (err *permanentError) Unwrap() error {
+	return err.err
+}
+
+// Permanent wraps err so that Do returns it without retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Do calls f until it succeeds, returns a permanent error,
+// or the Retrier's limits are reached.
+// It returns nil on success, and an *Error otherwise.
+func (r *Retrier) Do(ctx context.Context, f func(ctx context.Context) error) error {
+	start := r.clock.Now()
+
+	for attempt := 1; ; attempt++ {
+		err := f(ctx)
+		if err == nil {
+			return nil
+		}
+
+		elapsed := r.clock.Since(start)
+		giveUp := func(err error) error {
+			return &Error{
+				Attempts: attempt,
+				Elapsed:  elapsed,
+				Err:      err,
+			}
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return giveUp(err)
+		}
+		if !r.retryIf(err) {
+			return giveUp(err)
+		}
+		if r.maxAttempts > 0 && attempt >= r.maxAttempts {
+			return giveUp(err)
+		}
+
+		delay := r.jitter(r.backoff(attempt), r.rand)
+		if r.maxElapsed > 0 && elapsed+delay > r.maxElapsed {
+			return giveUp(err)
+		}
+
+		if r.onRetry != nil {
+			r.onRetry(Attempt{
+				Number:  attempt,
+				Err:     err,
+				Delay:   delay,
+				Elapsed: elapsed,
+			})
+		}
+
+		if err := r.sleep(ctx, delay); err != nil {
+			return giveUp(err)
+		}
+	}
+}
+
+func (r *Retrier) sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d <= 0 {
+		return nil
+	}
+
+	timer := r.clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
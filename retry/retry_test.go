@@ -0,0 +1,188 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/retry"
+)
+
+const timeout = 100 * time.Millisecond
+
+var errFailed = errors.New("failed")
+
+func TestDo_Backoff(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	var delays []time.Duration
+	r := retry.New(fake,
+		retry.WithBackoff(retry.Exponential(1*time.Second, 3*time.Second, 2)),
+		retry.WithOnRetry(func(attempt retry.Attempt) {
+			delays = append(delays, attempt.Delay)
+		}),
+	)
+
+	attempts := 0
+	errs := make(chan error, 1)
+	go func() {
+		errs <- r.Do(context.Background(), func(context.Context) error {
+			attempts++
+			if attempts < 4 {
+				return errFailed
+			}
+			return nil
+		})
+	}()
+
+	for _, d := range []time.Duration{1 * time.Second, 2 * time.Second, 3 * time.Second} {
+		fake.BlockUntil(1)
+		fake.Advance(d)
+	}
+
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout: Do did not return")
+	}
+
+	expected := []time.Duration{1 * time.Second, 2 * time.Second, 3 * time.Second}
+	if len(delays) != len(expected) {
+		t.Fatalf("expected delays %v got %v", expected, delays)
+	}
+	for i := range expected {
+		if delays[i] != expected[i] {
+			t.Errorf("expected delays %v got %v", expected, delays)
+		}
+	}
+}
+
+func TestDo_MaxAttempts(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	r := retry.New(fake, retry.WithBackoff(retry.Constant(0)), retry.WithMaxAttempts(3))
+
+	attempts := 0
+	err := r.Do(context.Background(), func(context.Context) error {
+		attempts++
+		return errFailed
+	})
+
+	var retryErr *retry.Error
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 3 {
+		t.Errorf("expected a retry error after %d attempts got %v", 3, err)
+	}
+	if !errors.Is(err, errFailed) {
+		t.Errorf("expected %v got %v", errFailed, err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected %d attempts got %d", 3, attempts)
+	}
+}
+
+func TestDo_MaxElapsed(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	r := retry.New(fake, retry.WithBackoff(retry.Constant(1*time.Second)), retry.WithMaxElapsed(2*time.Second))
+
+	attempts := 0
+	errs := make(chan error, 1)
+	go func() {
+		errs <- r.Do(context.Background(), func(context.Context) error {
+			attempts++
+			return errFailed
+		})
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, errFailed) {
+			t.Errorf("expected %v got %v", errFailed, err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout: Do did not return")
+	}
+
+	if attempts != 3 {
+		t.Errorf("expected %d attempts got %d", 3, attempts)
+	}
+}
+
+func TestDo_Permanent(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	r := retry.New(fake)
+
+	attempts := 0
+	err := r.Do(context.Background(), func(context.Context) error {
+		attempts++
+		return retry.Permanent(errFailed)
+	})
+
+	if !errors.Is(err, errFailed) {
+		t.Errorf("expected %v got %v", errFailed, err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected %d attempts got %d", 1, attempts)
+	}
+}
+
+func TestDo_ContextCanceled(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	r := retry.New(fake)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- r.Do(ctx, func(context.Context) error {
+			return errFailed
+		})
+	}()
+
+	fake.BlockUntil(1)
+	cancel()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected %v got %v", context.Canceled, err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout: Do did not return")
+	}
+}
+
+func TestJitter(t *testing.T) {
+	source := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 100; i++ {
+		if d := retry.FullJitter(1*time.Second, source); d < 0 || d > 1*time.Second {
+			t.Errorf("full jitter out of range: %s", d)
+		}
+		if d := retry.EqualJitter(1*time.Second, source); d < 500*time.Millisecond || d > 1*time.Second {
+			t.Errorf("equal jitter out of range: %s", d)
+		}
+	}
+}
+
+func TestExponential(t *testing.T) {
+	backoff := retry.Exponential(1*time.Second, 5*time.Second, 2)
+
+	for retry, expected := range []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		if actual := backoff(retry + 1); actual != expected {
+			t.Errorf("retry %d: expected %s got %s", retry+1, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package scheduler
+
+import "time"
+
+// An Option configures a Scheduler.
+type Option func(*Scheduler)
+
+// WithErrorHandler sets a function called with the name of a job and the
+// error returned by one of its runs. By default, errors are discarded.
+func WithErrorHandler(f func(name string, err error)) Option {
+	return func(s *Scheduler) {
+		s.onError = f
+	}
+}
+
+// WithStore persists the schedule of each job in store, so that a job
+// scheduled again under the same name and interval, by a Scheduler created
+// with the same store after a restart, resumes its schedule: it's next due
+// when it would have been, or right away, once, if it was due while no
+// process was running it. Errors persisting a schedule after a run are
+// reported to the error handler.
+func WithStore(store Store) Option {
+	return func(s *Scheduler) {
+		s.store = store
+	}
+}
+
+// A JobOption configures a job.
+type JobOption func(*job)
+
+// WithOverlap sets what happens when a job is due while a previous run is
+// still in progress. The default is Skip.
+func WithOverlap(overlap Overlap) JobOption {
+	return func(j *job) {
+		j.overlap = overlap
+	}
+}
+
+// WithMaxConcurrent limits the number of concurrent runs of a job with the
+// Concurrent overlap policy. Zero, the default, means no limit.
+func WithMaxConcurrent(n int) JobOption {
+	return func(j *job) {
+		j.maxConcurrent = n
+	}
+}
+
+// WithTimeout bounds each run of a job by a timeout measured by the
+// scheduler's clock. Zero, the default, means no timeout.
+func WithTimeout(d time.Duration) JobOption {
+	return func(j *job) {
+		j.timeout = d
+	}
+}
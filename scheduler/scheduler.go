@@ -0,0 +1,353 @@
+// Package scheduler runs periodic jobs on a clock.Clock, with policies for
+// runs that overlap, per-run timeouts, and clean shutdown.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// A Job is a unit of periodic work. Its context is canceled when the run
+// times out or when the scheduler stops without waiting for it.
+type Job func(ctx context.Context) error
+
+// Overlap is the policy for a job that is due while it is still running.
+type Overlap int
+
+const (
+	// Skip skips runs that are due while the previous run is in progress.
+	Skip Overlap = iota
+
+	// Queue defers runs that are due while the previous run is in progress,
+	// running them one after the other.
+	Queue
+
+	// Concurrent starts runs that are due while others are in progress,
+	// up to the limit set by WithMaxConcurrent.
+	Concurrent
+)
+
+var (
+	// ErrStopped is returned when scheduling a job on a stopped Scheduler.
+	ErrStopped = errors.New("scheduler: stopped")
+
+	// ErrDuplicate is returned when scheduling a job under a name in use.
+	ErrDuplicate = errors.New("scheduler: duplicate job name")
+)
+
+// Scheduler runs jobs periodically on a clock.
+type Scheduler struct {
+	clock   clock.Clock
+	onError func(name string, err error)
+	store   Store
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	loops  sync.WaitGroup
+	runs   *clock.WaitGroup
+
+	mutex     sync.Mutex
+	jobs      map[string]*job
+	persisted map[string]Entry // the entries left to resume, once listed
+	stopped   bool
+}
+
+type job struct {
+	name          string
+	interval      time.Duration
+	f             Job
+	overlap       Overlap
+	maxConcurrent int
+	timeout       time.Duration
+	removed       chan struct{}
+	exited        chan struct{}
+
+	mutex   sync.Mutex
+	running int
+	queued  int
+	skipped int64
+}
+
+// New returns a Scheduler measured by c.
+func New(c clock.Clock, opts ...Option) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		clock:   c,
+		onError: func(string, error) {},
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		runs:    clock.NewWaitGroup(c),
+		jobs:    make(map[string]*job),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Every schedules f to run every interval under name, starting one interval
+// from now, or resuming the schedule persisted under name if the Scheduler
+// has a Store. It panics if interval <= 0.
+func (s *Scheduler) Every(name string, interval time.Duration, f Job, opts ...JobOption) error {
+	if interval <= 0 {
+		panic("scheduler: non-positive interval for Every")
+	}
+
+	j := &job{
+		name:     name,
+		interval: interval,
+		f:        f,
+		removed:  make(chan struct{}),
+		exited:   make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stopped {
+		return ErrStopped
+	}
+	if _, ok := s.jobs[name]; ok {
+		return ErrDuplicate
+	}
+
+	first := interval
+	if s.store != nil {
+		var err error
+		if first, err = s.resume(j); err != nil {
+			return err
+		}
+	}
+	s.jobs[name] = j
+
+	// a resumed job waits for its first run on a timer, then ticks
+	var timer clock.Timer
+	var ticker clock.Ticker
+	if first == interval {
+		ticker = s.clock.NewTicker(interval)
+	} else {
+		timer = s.clock.NewTimer(first)
+	}
+	s.loops.Add(1)
+	go s.loop(j, timer, ticker)
+
+	return nil
+}
+
+// Remove stops scheduling runs of the named job, and deletes its schedule
+// from the Scheduler's Store. No run starts once Remove returns, but the
+// runs in progress aren't waited for.
+func (s *Scheduler) Remove(name string) error {
+	s.mutex.Lock()
+	j, ok := s.jobs[name]
+	delete(s.jobs, name)
+	s.mutex.Unlock()
+
+	if !ok {
+		return nil
+	}
+	close(j.removed)
+	<-j.exited
+
+	if s.store == nil {
+		return nil
+	}
+	return s.store.Del(s.ctx, name)
+}
+
+// resume returns the time until the first run of j, from the schedule
+// persisted for it, and persists its schedule if it has none.
+// The caller holds the scheduler's mutex.
+func (s *Scheduler) resume(j *job) (time.Duration, error) {
+	if s.persisted == nil {
+		entries, err := s.store.List(s.ctx)
+		if err != nil {
+			return 0, err
+		}
+		s.persisted = make(map[string]Entry, len(entries))
+		for _, entry := range entries {
+			s.persisted[entry.Name] = entry
+		}
+	}
+
+	now := s.clock.Now()
+	entry, ok := s.persisted[j.name]
+	delete(s.persisted, j.name)
+
+	// a job scheduled at another interval starts over
+	if !ok || entry.Interval != j.interval {
+		entry := Entry{
+			Name:     j.name,
+			Interval: j.interval,
+			Next:     now.Add(j.interval),
+		}
+		return j.interval, s.store.Put(s.ctx, entry)
+	}
+
+	first := entry.Next.Sub(now)
+	if first < 0 {
+		first = 0
+	}
+	return first, nil
+}
+
+// persist saves next as the time j is next due, reporting errors to the
+// error handler.
+func (s *Scheduler) persist(j *job, next time.Time) {
+	if s.store == nil {
+		return
+	}
+
+	entry := Entry{
+		Name:     j.name,
+		Interval: j.interval,
+		Next:     next,
+	}
+	if err := s.store.Put(s.ctx, entry); err != nil {
+		s.onError(j.name, err)
+	}
+}
+
+// Skipped returns the number of runs of the named job that were skipped
+// because of its overlap policy.
+func (s *Scheduler) Skipped(name string) int64 {
+	s.mutex.Lock()
+	j, ok := s.jobs[name]
+	s.mutex.Unlock()
+
+	if !ok {
+		return 0
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return j.skipped
+}
+
+// Stop stops scheduling runs and waits for the runs in progress to finish.
+// If ctx is done first, Stop cancels the contexts of the runs in progress
+// and returns ctx.Err().
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mutex.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.done)
+	}
+	s.mutex.Unlock()
+
+	s.loops.Wait()
+
+	err := s.runs.WaitContext(ctx)
+	s.cancel()
+	return err
+}
+
+func (s *Scheduler) loop(j *job, timer clock.Timer, ticker clock.Ticker) {
+	defer s.loops.Done()
+	defer close(j.exited)
+
+	if timer != nil {
+		select {
+		case <-s.done:
+			timer.Stop()
+			return
+		case <-j.removed:
+			timer.Stop()
+			return
+		case at := <-timer.C():
+			s.dispatch(j)
+			s.persist(j, at.Add(j.interval))
+		}
+		ticker = s.clock.NewTicker(j.interval)
+	}
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-j.removed:
+			return
+		case at := <-ticker.C():
+			s.dispatch(j)
+			s.persist(j, at.Add(j.interval))
+		}
+	}
+}
+
+func (s *Scheduler) dispatch(j *job) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if j.running > 0 {
+		switch j.overlap {
+		case Skip:
+			j.skipped++
+			return
+		case Queue:
+			j.queued++
+			return
+		case Concurrent:
+			if j.maxConcurrent > 0 && j.running >= j.maxConcurrent {
+				j.skipped++
+				return
+			}
+		}
+	}
+
+	j.running++
+	s.runs.Add(1)
+	go s.exec(j)
+}
+
+func (s *Scheduler) exec(j *job) {
+	defer s.runs.Done()
+
+	for {
+		s.run(j)
+
+		j.mutex.Lock()
+		if j.queued > 0 && !s.isStopped() {
+			j.queued--
+			j.mutex.Unlock()
+			continue
+		}
+		j.running--
+		j.mutex.Unlock()
+		return
+	}
+}
+
+func (s *Scheduler) run(j *job) {
+	ctx := s.ctx
+	if j.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithTimeout(ctx, s.clock, j.timeout)
+		defer cancel()
+	}
+
+	if err := j.f(ctx); err != nil {
+		s.onError(j.name, err)
+	}
+}
+
+func (s *Scheduler) isStopped() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,203 @@
+package scheduler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+	"github.com/go-toolbelt/clock/scheduler"
+)
+
+const timeout = 100 * time.Millisecond
+
+// blockingJob returns a job that signals starts and blocks until released.
+func blockingJob() (scheduler.Job, <-chan struct{}, chan<- struct{}) {
+	starts := make(chan struct{}, 10)
+	release := make(chan struct{})
+	return func(ctx context.Context) error {
+		starts <- struct{}{}
+		<-release
+		return nil
+	}, starts, release
+}
+
+func tick(t *testing.T, fake clock.FakeClock, waiters int) {
+	t.Helper()
+
+	clocktest.RequireBlockedWaiters(t, fake, waiters, timeout)
+	fake.Advance(1 * time.Second)
+}
+
+func requireStarts(t *testing.T, starts <-chan struct{}, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		select {
+		case <-starts:
+		case <-time.After(timeout):
+			t.Fatalf("timeout: expected %d starts got %d", n, i)
+		}
+	}
+
+	select {
+	case <-starts:
+		t.Fatalf("expected %d starts got more", n)
+	case <-time.After(timeout / 10):
+	}
+}
+
+func TestScheduler_Skip(t *testing.T) {
+	fake := clock.NewFakeClock()
+	s := scheduler.New(fake)
+
+	job, starts, release := blockingJob()
+	if err := s.Every("job", 1*time.Second, job); err != nil {
+		t.Fatal(err)
+	}
+
+	tick(t, fake, 1)
+	requireStarts(t, starts, 1)
+
+	tick(t, fake, 1)
+	tick(t, fake, 1)
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+
+	if skipped := s.Skipped("job"); skipped != 2 {
+		t.Errorf("expected %d skipped got %d", 2, skipped)
+	}
+
+	close(release)
+	requireStarts(t, starts, 0)
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScheduler_Queue(t *testing.T) {
+	fake := clock.NewFakeClock()
+	s := scheduler.New(fake)
+
+	job, starts, release := blockingJob()
+	if err := s.Every("job", 1*time.Second, job, scheduler.WithOverlap(scheduler.Queue)); err != nil {
+		t.Fatal(err)
+	}
+
+	tick(t, fake, 1)
+	tick(t, fake, 1)
+	tick(t, fake, 1)
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	requireStarts(t, starts, 1)
+
+	// queued runs follow one another
+	release <- struct{}{}
+	requireStarts(t, starts, 1)
+	release <- struct{}{}
+	requireStarts(t, starts, 1)
+	release <- struct{}{}
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Error(err)
+	}
+	requireStarts(t, starts, 0)
+}
+
+func TestScheduler_Concurrent(t *testing.T) {
+	fake := clock.NewFakeClock()
+	s := scheduler.New(fake)
+
+	job, starts, release := blockingJob()
+	err := s.Every("job", 1*time.Second, job,
+		scheduler.WithOverlap(scheduler.Concurrent),
+		scheduler.WithMaxConcurrent(2),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tick(t, fake, 1)
+	tick(t, fake, 1)
+	tick(t, fake, 1)
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	requireStarts(t, starts, 2)
+
+	if skipped := s.Skipped("job"); skipped != 1 {
+		t.Errorf("expected %d skipped got %d", 1, skipped)
+	}
+
+	close(release)
+	if err := s.Stop(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScheduler_Timeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	errs := make(chan error, 1)
+	s := scheduler.New(fake, scheduler.WithErrorHandler(func(name string, err error) {
+		errs <- err
+	}))
+
+	job := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if err := s.Every("job", 1*time.Second, job, scheduler.WithTimeout(500*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+
+	tick(t, fake, 1)
+
+	// the ticker and the run's timeout
+	clocktest.RequireBlockedWaiters(t, fake, 2, timeout)
+	fake.Advance(500 * time.Millisecond)
+
+	select {
+	case err := <-errs:
+		if err != context.DeadlineExceeded {
+			t.Errorf("expected %v got %v", context.DeadlineExceeded, err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timeout: expected an error")
+	}
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScheduler_Stop(t *testing.T) {
+	fake := clock.NewFakeClock()
+	s := scheduler.New(fake)
+
+	canceled := make(chan struct{})
+	job := func(ctx context.Context) error {
+		<-ctx.Done()
+		close(canceled)
+		return nil
+	}
+	if err := s.Every("job", 1*time.Second, job); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Every("job", 1*time.Second, job); err != scheduler.ErrDuplicate {
+		t.Errorf("expected %v got %v", scheduler.ErrDuplicate, err)
+	}
+
+	tick(t, fake, 1)
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := s.Stop(ctx); err != context.Canceled {
+		t.Errorf("expected %v got %v", context.Canceled, err)
+	}
+	clocktest.RequireClosedWithin(t, canceled, timeout)
+
+	if err := s.Every("other", 1*time.Second, job); err != scheduler.ErrStopped {
+		t.Errorf("expected %v got %v", scheduler.ErrStopped, err)
+	}
+}
@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// An Entry is the schedule of a job persisted in a Store.
+type Entry struct {
+	// Name is the name the job was scheduled under.
+	Name string `json:"name"`
+	// Interval is the time between runs of the job.
+	Interval time.Duration `json:"interval"`
+	// Next is when the job is next due.
+	Next time.Time `json:"next"`
+}
+
+// A Store persists the schedules of jobs, so that a Scheduler created with
+// WithStore resumes them where a previous process left off. Implementations
+// backed by files, SQL databases or key-value stores let schedules survive
+// process restarts. Its methods may be called concurrently.
+type Store interface {
+	// Put inserts or replaces the entry with the same name.
+	Put(ctx context.Context, entry Entry) error
+	// Del removes the entry with the given name, if any.
+	Del(ctx context.Context, name string) error
+	// List returns all the entries.
+	List(ctx context.Context) ([]Entry, error)
+}
+
+// MemoryStore is a Store keeping entries in memory, for tests.
+type MemoryStore struct {
+	mutex   sync.Mutex
+	entries map[string]Entry
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]Entry{}}
+}
+
+func (store *MemoryStore) Put(ctx context.Context, entry Entry) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.entries[entry.Name] = entry
+	return nil
+}
+
+func (store *MemoryStore) Del(ctx context.Context, name string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.entries, name)
+	return nil
+}
+
+// List returns the entries ordered by name.
+func (store *MemoryStore) List(ctx context.Context) ([]Entry, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	entries := make([]Entry, 0, len(store.entries))
+	for _, entry := range store.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
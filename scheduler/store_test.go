@@ -0,0 +1,152 @@
+package scheduler_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+	"github.com/go-toolbelt/clock/scheduler"
+)
+
+// countingJob returns a job that signals each run.
+func countingJob() (scheduler.Job, <-chan struct{}) {
+	starts := make(chan struct{}, 10)
+	return func(ctx context.Context) error {
+		starts <- struct{}{}
+		return nil
+	}, starts
+}
+
+func requireEntries(t *testing.T, store scheduler.Store, expected []scheduler.Entry) {
+	t.Helper()
+
+	// the schedule is persisted after a run is dispatched
+	deadline := time.Now().Add(timeout)
+	for {
+		entries, err := store.List(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reflect.DeepEqual(entries, expected) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected entries %v got %v", expected, entries)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestScheduler_Store(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+	store := scheduler.NewMemoryStore()
+
+	s := scheduler.New(fake, scheduler.WithStore(store))
+	job, starts := countingJob()
+	if err := s.Every("job", 1*time.Second, job); err != nil {
+		t.Fatal(err)
+	}
+	requireEntries(t, store, []scheduler.Entry{{Name: "job", Interval: time.Second, Next: start.Add(1 * time.Second)}})
+
+	tick(t, fake, 1)
+	requireStarts(t, starts, 1)
+	requireEntries(t, store, []scheduler.Entry{{Name: "job", Interval: time.Second, Next: start.Add(2 * time.Second)}})
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the process restarts halfway to the next run
+	fake.Advance(500 * time.Millisecond)
+
+	s = scheduler.New(fake, scheduler.WithStore(store))
+	defer s.Stop(context.Background())
+
+	job, starts = countingJob()
+	if err := s.Every("job", 1*time.Second, job); err != nil {
+		t.Fatal(err)
+	}
+
+	clocktest.RequireBlockedWaiters(t, fake, 1, timeout)
+	fake.Advance(499 * time.Millisecond)
+	requireStarts(t, starts, 0)
+	fake.Advance(1 * time.Millisecond)
+	requireStarts(t, starts, 1)
+	requireEntries(t, store, []scheduler.Entry{{Name: "job", Interval: time.Second, Next: start.Add(3 * time.Second)}})
+
+	// the schedule carries on from there
+	tick(t, fake, 1)
+	requireStarts(t, starts, 1)
+	requireEntries(t, store, []scheduler.Entry{{Name: "job", Interval: time.Second, Next: start.Add(4 * time.Second)}})
+}
+
+func TestScheduler_StoreMissed(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+	store := scheduler.NewMemoryStore()
+	store.Put(context.Background(), scheduler.Entry{Name: "job", Interval: time.Second, Next: start.Add(-10 * time.Second)})
+
+	s := scheduler.New(fake, scheduler.WithStore(store))
+	defer s.Stop(context.Background())
+
+	// the runs missed while the process was down run once, right away
+	job, starts := countingJob()
+	if err := s.Every("job", 1*time.Second, job); err != nil {
+		t.Fatal(err)
+	}
+	requireStarts(t, starts, 1)
+	requireEntries(t, store, []scheduler.Entry{{Name: "job", Interval: time.Second, Next: start.Add(1 * time.Second)}})
+
+	tick(t, fake, 1)
+	requireStarts(t, starts, 1)
+}
+
+func TestScheduler_StoreInterval(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+	store := scheduler.NewMemoryStore()
+	store.Put(context.Background(), scheduler.Entry{Name: "job", Interval: time.Minute, Next: start.Add(30 * time.Second)})
+
+	s := scheduler.New(fake, scheduler.WithStore(store))
+	defer s.Stop(context.Background())
+
+	// a job scheduled at another interval starts over
+	job, starts := countingJob()
+	if err := s.Every("job", 1*time.Second, job); err != nil {
+		t.Fatal(err)
+	}
+	requireEntries(t, store, []scheduler.Entry{{Name: "job", Interval: time.Second, Next: start.Add(1 * time.Second)}})
+
+	tick(t, fake, 1)
+	requireStarts(t, starts, 1)
+}
+
+func TestScheduler_Remove(t *testing.T) {
+	fake := clock.NewFakeClock()
+	store := scheduler.NewMemoryStore()
+
+	s := scheduler.New(fake, scheduler.WithStore(store))
+	defer s.Stop(context.Background())
+
+	job, starts := countingJob()
+	if err := s.Every("job", 1*time.Second, job); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Remove("job"); err != nil {
+		t.Fatal(err)
+	}
+	requireEntries(t, store, []scheduler.Entry{})
+
+	clocktest.RequireBlockedWaiters(t, fake, 0, timeout)
+	fake.Advance(1 * time.Second)
+	requireStarts(t, starts, 0)
+
+	// the name can be used again
+	if err := s.Every("job", 1*time.Second, job); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,136 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Tick is a tick delivered by a SeqTicker.
+type Tick struct {
+	// Seq is the number of the tick, counting the intervals since the ticker
+	// was created, from 1.
+	Seq int64
+
+	// Skipped is the number of ticks since the previous tick delivered that
+	// were not delivered, because the consumer fell behind or the ticker
+	// woke up late.
+	Skipped int
+
+	// At is the time the tick was due.
+	At time.Time
+}
+
+// SeqTicker is a ticker whose ticks carry their sequence number, so that its
+// consumer can tell the intervals it missed without comparing timestamps.
+//
+// It's built on the timers of any Clock, so its behavior is the same on the
+// real and fake clocks. Its ticks fall on a grid of instants d apart from the
+// time it was created or last reset; like a Ticker, it drops the ticks the
+// consumer isn't ready to receive, and counts them in the Skipped of the next
+// tick delivered.
+type SeqTicker struct {
+	clock Clock
+	c     chan Tick
+
+	mutex   sync.Mutex
+	d       time.Duration
+	start   time.Time
+	base    int64 // the number of the tick before start
+	next    int64 // the number of the next tick due
+	sent    int64 // the number of the last tick delivered
+	timer   Timer
+	stopped bool
+}
+
+// NewSeqTicker returns a SeqTicker driven by clock, ticking every d.
+// The duration d must be greater than zero; if not, NewSeqTicker will panic.
+func NewSeqTicker(clock Clock, d time.Duration) *SeqTicker {
+	if d <= 0 {
+		panic(errNonPositiveInterval)
+	}
+
+	ticker := &SeqTicker{
+		clock: clock,
+		c:     make(chan Tick, 1),
+		d:     d,
+		start: clock.Now(),
+		next:  1,
+	}
+
+	ticker.mutex.Lock()
+	defer ticker.mutex.Unlock()
+
+	ticker.timer = clock.AfterFunc(d, ticker.tick)
+	return ticker
+}
+
+// C returns the channel on which the ticks are delivered.
+func (ticker *SeqTicker) C() <-chan Tick {
+	return ticker.c
+}
+
+// Stop turns off the ticker. After Stop, no more ticks will be sent.
+func (ticker *SeqTicker) Stop() {
+	ticker.mutex.Lock()
+	defer ticker.mutex.Unlock()
+
+	ticker.stopped = true
+	ticker.timer.Stop()
+}
+
+// Reset stops the ticker and resets its period to d, restarting the grid
+// from now. The numbering of the ticks carries on: the next tick, d from now,
+// follows the last tick that was due.
+func (ticker *SeqTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic(errNonPositiveInterval)
+	}
+
+	ticker.mutex.Lock()
+	defer ticker.mutex.Unlock()
+
+	ticker.d = d
+	ticker.start = ticker.clock.Now()
+	ticker.base = ticker.next - 1
+	ticker.stopped = false
+	ticker.timer.Reset(d)
+}
+
+// due returns the time of the tick numbered seq.
+// The caller holds the ticker's mutex.
+func (ticker *SeqTicker) due(seq int64) time.Time {
+	return ticker.start.Add(time.Duration(seq-ticker.base) * ticker.d)
+}
+
+func (ticker *SeqTicker) tick() {
+	ticker.mutex.Lock()
+	defer ticker.mutex.Unlock()
+
+	if ticker.stopped {
+		return
+	}
+
+	// the grid may have been reset while the timer fired
+	now := ticker.clock.Now()
+	if now.Before(ticker.due(ticker.next)) {
+		ticker.timer.Reset(ticker.due(ticker.next).Sub(now))
+		return
+	}
+
+	// the last tick due, if the timer woke up more than a period late
+	seq := ticker.base + int64(now.Sub(ticker.start)/ticker.d)
+	tick := Tick{
+		Seq:     seq,
+		Skipped: int(seq - ticker.sent - 1),
+		At:      ticker.due(seq),
+	}
+
+	select {
+	case ticker.c <- tick:
+		ticker.sent = seq
+	default:
+	}
+
+	ticker.next = seq + 1
+	ticker.timer.Reset(ticker.due(ticker.next).Sub(now))
+}
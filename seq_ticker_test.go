@@ -0,0 +1,91 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func assertTick(t *testing.T, expected clock.Tick, c <-chan clock.Tick) {
+	t.Helper()
+
+	timer := time.NewTimer(sentTimeout)
+	defer timer.Stop()
+
+	select {
+	case actual := <-c:
+		if actual != expected {
+			t.Errorf("expected %+v got %+v", expected, actual)
+		}
+	case <-timer.C:
+		t.Errorf("timeout: after %s", sentTimeout)
+	}
+}
+
+func TestSeqTicker(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := clock.NewSeqTicker(fake, time.Second)
+	defer ticker.Stop()
+
+	c := ticker.C()
+	assertClockUntil(t, 1, fake)
+	fake.Advance(time.Second)
+	assertTick(t, clock.Tick{Seq: 1, At: start.Add(time.Second)}, c)
+
+	// the consumer falls behind: tick 3 is dropped
+	for i := 0; i < 2; i++ {
+		assertClockUntil(t, 1, fake)
+		fake.Advance(time.Second)
+	}
+	assertClockUntil(t, 1, fake)
+	assertTick(t, clock.Tick{Seq: 2, At: start.Add(2 * time.Second)}, c)
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(time.Second)
+	assertTick(t, clock.Tick{Seq: 4, Skipped: 1, At: start.Add(4 * time.Second)}, c)
+}
+
+func TestSeqTicker_Reset(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := clock.NewSeqTicker(fake, time.Second)
+	defer ticker.Stop()
+
+	c := ticker.C()
+	assertClockUntil(t, 1, fake)
+	fake.Advance(time.Second)
+	assertTick(t, clock.Tick{Seq: 1, At: start.Add(time.Second)}, c)
+
+	// the grid restarts from now, the numbering carries on
+	fake.Advance(500 * time.Millisecond)
+	ticker.Reset(2 * time.Second)
+	assertClockUntil(t, 1, fake)
+	fake.Advance(2 * time.Second)
+	assertTick(t, clock.Tick{Seq: 2, At: start.Add(3500 * time.Millisecond)}, c)
+
+	ticker.Stop()
+	fake.Advance(2 * time.Second)
+	select {
+	case tick := <-c:
+		t.Errorf("tick sent after Stop: %+v", tick)
+	default:
+	}
+}
+
+func TestSeqTicker_Real(t *testing.T) {
+	ticker := clock.NewSeqTicker(clock.NewRealClock(), 10*time.Millisecond)
+	defer ticker.Stop()
+
+	var last int64
+	for i := 0; i < 3; i++ {
+		tick := <-ticker.C()
+		if tick.Seq != last+int64(tick.Skipped)+1 {
+			t.Errorf("expected tick %d after %d skipped following tick %d", tick.Seq, tick.Skipped, last)
+		}
+		last = tick.Seq
+	}
+}
@@ -0,0 +1,79 @@
+package clock
+
+import "sync"
+
+// CallbackOrder selects the order in which a fake clock built with
+// WithSerialCallbacks runs the AfterFunc callbacks due together.
+type CallbackOrder int
+
+const (
+	// DeadlineOrder runs callbacks by deadline, then in the order their
+	// timers were scheduled.
+	DeadlineOrder CallbackOrder = iota
+
+	// RegistrationOrder runs callbacks in the order their timers were
+	// scheduled, whatever their deadlines.
+	RegistrationOrder
+)
+
+// WithSerialCallbacks makes the clock run AfterFunc callbacks one at a time,
+// on a single goroutine, in order, instead of each in its own goroutine, so
+// tests can assert the order of their side effects. Callbacks due together,
+// such as by one call to Advance, run in the given order; later ones run
+// after them. A callback that blocks delays those after it.
+//
+// Under RegistrationOrder, sleepers woken together wake in the order they
+// were scheduled, and WithRandomOrder doesn't apply.
+func WithSerialCallbacks(order CallbackOrder) FakeOption {
+	return func(clock *fakeClock) {
+		clock.executor = &executor{}
+		clock.order = order
+	}
+}
+
+// callback returns the function an AfterFunc sleeper calls when it wakes,
+// running f in its own goroutine, or on the clock's executor if it has one.
+func (clock *fakeClock) callback(f func(), pc uintptr) func() {
+	if e := clock.executor; e != nil {
+		return func() {
+			e.submit(func() { callRecover(f, pc, clock.onPanic) })
+		}
+	}
+	return func() { go callRecover(f, pc, clock.onPanic) }
+}
+
+// executor runs functions one at a time, in the order they're submitted,
+// on a goroutine that runs while there are functions queued.
+type executor struct {
+	mutex   sync.Mutex
+	queue   []func()
+	running bool
+}
+
+func (e *executor) submit(f func()) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.queue = append(e.queue, f)
+	if !e.running {
+		e.running = true
+		go e.run()
+	}
+}
+
+func (e *executor) run() {
+	for {
+		e.mutex.Lock()
+		if len(e.queue) == 0 {
+			e.running = false
+			e.mutex.Unlock()
+			return
+		}
+		f := e.queue[0]
+		e.queue[0] = nil
+		e.queue = e.queue[1:]
+		e.mutex.Unlock()
+
+		f()
+	}
+}
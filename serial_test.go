@@ -0,0 +1,74 @@
+package clock_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// runCallbacks schedules callbacks appending their index to a slice, at the
+// given delays, advances the clock past all of them, and returns the order
+// they ran in.
+func runCallbacks(t *testing.T, fake clock.FakeClock, delays ...time.Duration) []int {
+	t.Helper()
+
+	ran := make(chan int, len(delays))
+	var max time.Duration
+	for i, d := range delays {
+		i := i
+		fake.AfterFunc(d, func() { ran <- i })
+		if d > max {
+			max = d
+		}
+	}
+	fake.Advance(max)
+
+	order := make([]int, 0, len(delays))
+	for range delays {
+		select {
+		case i := <-ran:
+			order = append(order, i)
+		case <-time.After(time.Second):
+			t.Fatalf("timeout: %d callbacks ran out of %d", len(order), len(delays))
+		}
+	}
+	return order
+}
+
+func TestWithSerialCallbacks_Deadline(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithSerialCallbacks(clock.DeadlineOrder))
+
+	delays := []time.Duration{3 * time.Second, time.Second, 2 * time.Second, time.Second, 0}
+	for i := 0; i < 10; i++ {
+		if order, expected := runCallbacks(t, fake, delays...), []int{4, 1, 3, 2, 0}; !reflect.DeepEqual(order, expected) {
+			t.Fatalf("expected %v got %v", expected, order)
+		}
+	}
+}
+
+func TestWithSerialCallbacks_Registration(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithSerialCallbacks(clock.RegistrationOrder))
+
+	delays := []time.Duration{3 * time.Second, time.Second, 2 * time.Second, time.Second}
+	for i := 0; i < 10; i++ {
+		if order, expected := runCallbacks(t, fake, delays...), []int{0, 1, 2, 3}; !reflect.DeepEqual(order, expected) {
+			t.Fatalf("expected %v got %v", expected, order)
+		}
+	}
+}
+
+func TestWithSerialCallbacks_OneAtATime(t *testing.T) {
+	fake := clock.NewFakeClock(clock.WithSerialCallbacks(clock.DeadlineOrder))
+
+	release := make(chan struct{})
+	second := make(chan struct{})
+	fake.AfterFunc(time.Second, func() { <-release })
+	fake.AfterFunc(time.Second, func() { close(second) })
+	fake.Advance(time.Second)
+
+	assertNotClosed(t, second)
+	close(release)
+	assertClosed(t, second)
+}
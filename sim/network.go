@@ -0,0 +1,188 @@
+package sim
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// ErrUnknownNode is returned when sending a message to a node that isn't on
+// the network.
+var ErrUnknownNode = errors.New("sim: unknown node")
+
+// A Link models the delivery of messages from a node to another.
+type Link struct {
+	// Latency is the minimum delivery delay.
+	Latency time.Duration
+	// Jitter is the maximum delay added to Latency, drawn uniformly.
+	// Messages with jitter can be delivered out of order.
+	Jitter time.Duration
+	// Loss is the probability, from 0 to 1, that a message is dropped.
+	Loss float64
+}
+
+// A Message is a payload sent from a node to another.
+type Message struct {
+	From    string
+	To      string
+	Payload interface{}
+	// Sent and Delivered are on the simulation's timeline.
+	Sent      time.Time
+	Delivered time.Time
+}
+
+// NetworkStats are statistics about the messages sent on a network.
+type NetworkStats struct {
+	Sent      int
+	Delivered int
+	Dropped   int
+}
+
+// Network is a model of nodes exchanging messages, delivered as events of a
+// simulation after a delay, or dropped, according to the link between them.
+type Network struct {
+	sim *Sim
+
+	mutex sync.Mutex
+	rand  *rand.Rand
+	link  Link
+	links map[[2]string]Link
+	nodes map[string]*Node
+	stats NetworkStats
+}
+
+// A Node is a participant of a network, with its own skewed clock.
+type Node struct {
+	name    string
+	network *Network
+	clock   clock.Clock
+	handler func(Message)
+}
+
+// NewNetwork returns a network delivering messages as events of s.
+// Links default to no latency and no loss; see WithLink.
+func NewNetwork(s *Sim, opts ...NetworkOption) *Network {
+	network := &Network{
+		sim:   s,
+		rand:  rand.New(rand.NewSource(1)),
+		links: map[[2]string]Link{},
+		nodes: map[string]*Node{},
+	}
+	for _, opt := range opts {
+		opt(network)
+	}
+	return network
+}
+
+// AddNode adds a node to the network, whose clock reads skew ahead of the
+// simulation's clock, and which receives its messages with handler.
+// Handlers run as events of the simulation.
+func (network *Network) AddNode(name string, skew time.Duration, handler func(Message)) *Node {
+	node := &Node{
+		name:    name,
+		network: network,
+		clock:   skewedClock{Clock: network.sim.Clock(), skew: skew},
+		handler: handler,
+	}
+
+	network.mutex.Lock()
+	defer network.mutex.Unlock()
+
+	network.nodes[name] = node
+	return node
+}
+
+// SetLink sets the link from a node to another, replacing the default.
+// Links are directional: set both directions for a symmetric link.
+func (network *Network) SetLink(from, to string, link Link) {
+	network.mutex.Lock()
+	defer network.mutex.Unlock()
+
+	network.links[[2]string{from, to}] = link
+}
+
+// Stats returns statistics about the messages sent so far.
+func (network *Network) Stats() NetworkStats {
+	network.mutex.Lock()
+	defer network.mutex.Unlock()
+
+	return network.stats
+}
+
+// Name returns the name of the node.
+func (node *Node) Name() string {
+	return node.name
+}
+
+// Clock returns the node's clock. Its Now and Since are skewed; timers are
+// measured in durations, so they are unaffected, and the times they deliver
+// are on the simulation's timeline.
+func (node *Node) Clock() clock.Clock {
+	return node.clock
+}
+
+// Send sends payload to the node named to, to be delivered after the delay
+// of the link between them, unless the link drops it.
+// It returns ErrUnknownNode if there is no such node.
+func (node *Node) Send(to string, payload interface{}) error {
+	network := node.network
+
+	network.mutex.Lock()
+	dest, ok := network.nodes[to]
+	if !ok {
+		network.mutex.Unlock()
+		return ErrUnknownNode
+	}
+
+	link, ok := network.links[[2]string{node.name, to}]
+	if !ok {
+		link = network.link
+	}
+
+	network.stats.Sent++
+	if link.Loss > 0 && network.rand.Float64() < link.Loss {
+		network.stats.Dropped++
+		network.mutex.Unlock()
+		return nil
+	}
+
+	delay := link.Latency
+	if link.Jitter > 0 {
+		delay += time.Duration(network.rand.Int63n(int64(link.Jitter) + 1))
+	}
+	network.mutex.Unlock()
+
+	msg := Message{
+		From:    node.name,
+		To:      to,
+		Payload: payload,
+		Sent:    network.sim.Now(),
+	}
+	network.sim.Schedule(delay, func() {
+		msg.Delivered = network.sim.Now()
+
+		network.mutex.Lock()
+		network.stats.Delivered++
+		network.mutex.Unlock()
+
+		dest.handler(msg)
+	}, WithName("deliver"))
+	return nil
+}
+
+// skewedClock is a clock reading skew ahead of another.
+type skewedClock struct {
+	clock.Clock
+	skew time.Duration
+}
+
+func (c skewedClock) Now() time.Time {
+	return c.Clock.Now().Add(c.skew)
+}
+
+func (c skewedClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
@@ -0,0 +1,159 @@
+package sim_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/sim"
+)
+
+func TestNetwork_Latency(t *testing.T) {
+	s := sim.New(clock.NewFakeClock())
+	network := sim.NewNetwork(s, sim.WithLink(sim.Link{Latency: 100 * time.Millisecond}))
+
+	var received []sim.Message
+	a := network.AddNode("a", 0, func(sim.Message) {})
+	network.AddNode("b", 0, func(msg sim.Message) { received = append(received, msg) })
+
+	if err := a.Send("b", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	if len(received) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(received))
+	}
+	msg := received[0]
+	if msg.From != "a" || msg.To != "b" || msg.Payload != "hello" {
+		t.Errorf("unexpected message %+v", msg)
+	}
+	if d := msg.Delivered.Sub(msg.Sent); d != 100*time.Millisecond {
+		t.Errorf("expected a delay of 100ms, got %s", d)
+	}
+}
+
+func TestNetwork_Jitter(t *testing.T) {
+	delays := func(seed int64) []time.Duration {
+		s := sim.New(clock.NewFakeClock())
+		network := sim.NewNetwork(s, sim.WithSeed(seed))
+
+		var delays []time.Duration
+		a := network.AddNode("a", 0, func(sim.Message) {})
+		network.AddNode("b", 0, func(msg sim.Message) { delays = append(delays, msg.Delivered.Sub(msg.Sent)) })
+		network.SetLink("a", "b", sim.Link{Latency: 10 * time.Millisecond, Jitter: 5 * time.Millisecond})
+
+		for i := 0; i < 20; i++ {
+			a.Send("b", i)
+		}
+		s.Run()
+		return delays
+	}
+
+	first := delays(7)
+	for _, d := range first {
+		if d < 10*time.Millisecond || d > 15*time.Millisecond {
+			t.Errorf("expected a delay within [10ms, 15ms], got %s", d)
+		}
+	}
+
+	again := delays(7)
+	for i := range first {
+		if first[i] != again[i] {
+			t.Fatalf("expected the same delays for the same seed, got %v and %v", first, again)
+		}
+	}
+}
+
+func TestNetwork_Loss(t *testing.T) {
+	s := sim.New(clock.NewFakeClock())
+	network := sim.NewNetwork(s)
+
+	delivered := 0
+	a := network.AddNode("a", 0, func(sim.Message) {})
+	network.AddNode("b", 0, func(sim.Message) { delivered++ })
+	network.SetLink("a", "b", sim.Link{Loss: 0.5})
+
+	for i := 0; i < 1000; i++ {
+		a.Send("b", i)
+	}
+	s.Run()
+
+	stats := network.Stats()
+	if stats.Sent != 1000 || stats.Delivered != delivered || stats.Delivered+stats.Dropped != 1000 {
+		t.Errorf("unexpected stats %+v with %d delivered", stats, delivered)
+	}
+	if stats.Dropped < 400 || stats.Dropped > 600 {
+		t.Errorf("expected about half the messages dropped, got %d", stats.Dropped)
+	}
+}
+
+func TestNetwork_UnknownNode(t *testing.T) {
+	s := sim.New(clock.NewFakeClock())
+	network := sim.NewNetwork(s)
+
+	a := network.AddNode("a", 0, func(sim.Message) {})
+	if err := a.Send("b", nil); !errors.Is(err, sim.ErrUnknownNode) {
+		t.Errorf("expected %v got %v", sim.ErrUnknownNode, err)
+	}
+}
+
+func TestNode_Clock(t *testing.T) {
+	start := time.Unix(1, 0)
+	s := sim.New(clock.NewFakeClockAt(start))
+	network := sim.NewNetwork(s)
+
+	a := network.AddNode("a", 3*time.Second, func(sim.Message) {})
+	if now, expected := a.Clock().Now(), start.Add(3*time.Second); !now.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, now)
+	}
+	if since := a.Clock().Since(start); since != 3*time.Second {
+		t.Errorf("expected 3s got %s", since)
+	}
+}
+
+// TestNetwork_Retry simulates a client retrying a request whose response
+// arrives after its timeout.
+func TestNetwork_Retry(t *testing.T) {
+	s := sim.New(clock.NewFakeClock())
+	network := sim.NewNetwork(s, sim.WithLink(sim.Link{Latency: 300 * time.Millisecond}))
+
+	const timeout = 500 * time.Millisecond
+	attempts, responses := 0, 0
+	var pending *sim.Event
+
+	var client *sim.Node
+	var send func()
+	send = func() {
+		attempts++
+		client.Send("server", attempts)
+		pending = s.Schedule(timeout, func() {
+			if attempts < 3 {
+				send()
+			}
+		})
+	}
+
+	client = network.AddNode("client", 0, func(msg sim.Message) {
+		responses++
+		if msg.Payload == attempts {
+			pending.Cancel()
+		}
+	})
+	var server *sim.Node
+	server = network.AddNode("server", 0, func(msg sim.Message) {
+		server.Send("client", msg.Payload)
+	})
+
+	// the first round trip takes 600ms, past the timeout,
+	// then requests get faster
+	s.Schedule(0, send)
+	s.RunFor(400 * time.Millisecond)
+	network.SetLink("client", "server", sim.Link{Latency: 100 * time.Millisecond})
+	s.Run()
+
+	if attempts != 2 || responses != 2 {
+		t.Errorf("expected 2 attempts and responses, got %d and %d", attempts, responses)
+	}
+}
@@ -0,0 +1,56 @@
+package sim
+
+import "math/rand"
+
+// An Option configures a Sim.
+type Option func(*Sim)
+
+// WithTrace records the named events run, to be exported with Export.
+func WithTrace() Option {
+	return func(s *Sim) {
+		s.trace = true
+	}
+}
+
+// An EventOption configures an event.
+type EventOption func(*Event)
+
+// WithPriority sets the priority of an event. Among events at the same
+// simulated time, events with a higher priority run first. The default is 0.
+func WithPriority(priority int) EventOption {
+	return func(e *Event) {
+		e.priority = priority
+	}
+}
+
+// WithName names an event, to count the events run by name in Stats.
+func WithName(name string) EventOption {
+	return func(e *Event) {
+		e.name = name
+	}
+}
+
+// A NetworkOption configures a Network.
+type NetworkOption func(*Network)
+
+// WithLink sets the link used between nodes without one set by SetLink.
+func WithLink(link Link) NetworkOption {
+	return func(network *Network) {
+		network.link = link
+	}
+}
+
+// WithSeed seeds the random source drawing jitter and losses, so runs are
+// reproducible. The default seed is 1.
+func WithSeed(seed int64) NetworkOption {
+	return func(network *Network) {
+		network.rand = rand.New(rand.NewSource(seed))
+	}
+}
+
+// withData attaches the data of a scenario event to an event, to be exported.
+func withData(data []byte) EventOption {
+	return func(e *Event) {
+		e.data = data
+	}
+}
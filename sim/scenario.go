@@ -0,0 +1,172 @@
+package sim
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrUnknownEvent is returned when replaying a scenario with an event
+// without a handler.
+var ErrUnknownEvent = errors.New("sim: no handler for event")
+
+// A Scenario is a declarative list of events, and of the steps the clock
+// advances by, which can be authored as JSON, replayed on a simulation, and
+// exported from one:
+//
+//	{
+//		"events": [
+//			{"at": "1s", "name": "request"},
+//			{"at": "1.5s", "name": "timeout", "priority": 1},
+//			{"at": "2s", "name": "response", "data": {"status": 200}}
+//		],
+//		"advances": ["1s", "500ms", "30s"],
+//		"until": "1m"
+//	}
+//
+// Scenarios are JSON only; reading YAML would add a dependency to the module.
+type Scenario struct {
+	// Events are the events to run, at offsets from the time the scenario
+	// is replayed at.
+	Events []ScenarioEvent `json:"events"`
+	// Advances are the steps the clock advances by in turn, from the start
+	// of the scenario, running the events due in each. If empty, the clock
+	// advances from one event to the next.
+	Advances []Duration `json:"advances,omitempty"`
+	// Until is how long the scenario runs for, if longer than the advances.
+	// If zero, it runs until no event is pending, or, if there are advances,
+	// until the end of the last one.
+	Until Duration `json:"until,omitempty"`
+}
+
+// A ScenarioEvent is an event of a Scenario.
+type ScenarioEvent struct {
+	// At is the offset of the event from the start of the scenario.
+	At Duration `json:"at"`
+	// Name selects the handler of the event.
+	Name string `json:"name"`
+	// Priority orders events at the same time; see WithPriority.
+	Priority int `json:"priority,omitempty"`
+	// Data is passed to the handler.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Duration is a time.Duration encoded in JSON as a string,
+// such as "1m30s", as parsed by time.ParseDuration.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("sim: duration must be a string: %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("sim: %w", err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// ReadScenario decodes a scenario from JSON, rejecting unknown fields,
+// events without a name, and negative offsets and advances.
+func ReadScenario(r io.Reader) (Scenario, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var scenario Scenario
+	if err := decoder.Decode(&scenario); err != nil {
+		return Scenario{}, fmt.Errorf("sim: decoding scenario: %w", err)
+	}
+
+	for i, e := range scenario.Events {
+		switch {
+		case e.Name == "":
+			return Scenario{}, fmt.Errorf("sim: event %d: missing name", i)
+		case e.At < 0:
+			return Scenario{}, fmt.Errorf("sim: event %d %q: negative offset %s", i, e.Name, time.Duration(e.At))
+		}
+
+		// compact the data, so it reads the same however it was indented
+		if len(e.Data) > 0 {
+			var buf bytes.Buffer
+			if err := json.Compact(&buf, e.Data); err != nil {
+				return Scenario{}, fmt.Errorf("sim: event %d %q: %w", i, e.Name, err)
+			}
+			scenario.Events[i].Data = buf.Bytes()
+		}
+	}
+	for i, step := range scenario.Advances {
+		if step < 0 {
+			return Scenario{}, fmt.Errorf("sim: advance %d: negative step %s", i, time.Duration(step))
+		}
+	}
+	if scenario.Until < 0 {
+		return Scenario{}, fmt.Errorf("sim: negative until %s", time.Duration(scenario.Until))
+	}
+	return scenario, nil
+}
+
+// Write encodes the scenario as indented JSON.
+func (scenario Scenario) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(scenario)
+}
+
+// Replay schedules the events of the scenario on s, from its current time,
+// calling the handler named by each event, then runs s through each of the
+// Advances in turn, then for Until, or, if neither is set, until no event is
+// pending. It returns an error wrapping ErrUnknownEvent, without
+// running anything, if an event has no handler, and the error of s (see
+// Sim.Err) if the clock refused to advance.
+// It returns the number of events run.
+func (scenario Scenario) Replay(s *Sim, handlers map[string]func(ScenarioEvent)) (int, error) {
+	for i, e := range scenario.Events {
+		if _, ok := handlers[e.Name]; !ok {
+			return 0, fmt.Errorf("%w: event %d %q", ErrUnknownEvent, i, e.Name)
+		}
+	}
+
+	start := s.Now()
+	for _, e := range scenario.Events {
+		e := e
+		h := handlers[e.Name]
+		s.ScheduleAt(start.Add(time.Duration(e.At)), func() { h(e) },
+			WithName(e.Name), WithPriority(e.Priority), withData(e.Data))
+	}
+
+	var n int
+	end := start
+	for _, step := range scenario.Advances {
+		end = end.Add(time.Duration(step))
+		n += s.RunUntil(end)
+	}
+	switch {
+	case scenario.Until > 0:
+		n += s.RunUntil(start.Add(time.Duration(scenario.Until)))
+	case len(scenario.Advances) == 0:
+		n = s.Run()
+	}
+	return n, s.Err()
+}
+
+// Export returns the named events run so far as a scenario, with offsets
+// from the start of the simulation, if it was created with WithTrace.
+// Replaying it on a new simulation runs the same events at the same times.
+func (s *Sim) Export() Scenario {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return Scenario{
+		Events: append([]ScenarioEvent{}, s.traced...),
+		Until:  Duration(s.clock.Now().Sub(s.start)),
+	}
+}
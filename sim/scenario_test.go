@@ -0,0 +1,185 @@
+package sim_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/sim"
+)
+
+const scenarioJSON = `{
+	"events": [
+		{"at": "2s", "name": "response", "data": {"status": 200}},
+		{"at": "1s", "name": "request"},
+		{"at": "2s", "name": "timeout", "priority": 1}
+	],
+	"until": "1m"
+}`
+
+func TestScenario_Replay(t *testing.T) {
+	scenario, err := sim.ReadScenario(strings.NewReader(scenarioJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Unix(1, 0)
+	s := sim.New(clock.NewFakeClockAt(start), sim.WithTrace())
+
+	var order []string
+	record := func(e sim.ScenarioEvent) {
+		order = append(order, e.Name+"@"+s.Now().Sub(start).String())
+	}
+	handlers := map[string]func(sim.ScenarioEvent){
+		"request":  record,
+		"response": record,
+		"timeout":  record,
+	}
+
+	n, err := scenario.Replay(s, handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 events run, got %d", n)
+	}
+	if expected := []string{"request@1s", "timeout@2s", "response@2s"}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %v got %v", expected, order)
+	}
+	if now, expected := s.Now(), start.Add(time.Minute); !now.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, now)
+	}
+}
+
+func TestScenario_Export(t *testing.T) {
+	scenario, err := sim.ReadScenario(strings.NewReader(scenarioJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handlers := map[string]func(sim.ScenarioEvent){
+		"request":  func(sim.ScenarioEvent) {},
+		"response": func(sim.ScenarioEvent) {},
+		"timeout":  func(sim.ScenarioEvent) {},
+	}
+
+	first := sim.New(clock.NewFakeClock(), sim.WithTrace())
+	scenario.Replay(first, handlers)
+
+	var buf bytes.Buffer
+	if err := first.Export().Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	exported, err := sim.ReadScenario(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(exported.Events) != 3 || exported.Events[0].Name != "request" {
+		t.Fatalf("unexpected export %+v", exported)
+	}
+	if data := string(exported.Events[2].Data); data != `{"status":200}` {
+		t.Errorf("expected the data exported, got %s", data)
+	}
+
+	// replaying the export runs the same events at the same times
+	second := sim.New(clock.NewFakeClock(), sim.WithTrace())
+	if _, err := exported.Replay(second, handlers); err != nil {
+		t.Fatal(err)
+	}
+	if a, b := first.Export(), second.Export(); !reflect.DeepEqual(a.Events, b.Events) || a.Until != b.Until {
+		t.Errorf("expected the same schedule, got %+v and %+v", a, b)
+	}
+}
+
+func TestScenario_Advances(t *testing.T) {
+	scenario, err := sim.ReadScenario(strings.NewReader(`{
+		"events": [
+			{"at": "1s", "name": "request"},
+			{"at": "2s", "name": "response"},
+			{"at": "1h", "name": "retry"}
+		],
+		"advances": ["1500ms", "1s"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Unix(1, 0)
+	s := sim.New(clock.NewFakeClockAt(start))
+
+	var order []string
+	record := func(e sim.ScenarioEvent) {
+		order = append(order, e.Name+"@"+s.Now().Sub(start).String())
+	}
+	handlers := map[string]func(sim.ScenarioEvent){
+		"request":  record,
+		"response": record,
+		"retry":    record,
+	}
+
+	// the clock stops at the end of the last advance, leaving the retry pending
+	n, err := scenario.Replay(s, handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 events run, got %d", n)
+	}
+	if expected := []string{"request@1s", "response@2s"}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %v got %v", expected, order)
+	}
+	if now, expected := s.Now(), start.Add(2500*time.Millisecond); !now.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, now)
+	}
+	if pending := s.Stats().Pending; pending != 1 {
+		t.Errorf("expected the retry pending, got %d events", pending)
+	}
+}
+
+func TestScenario_AdvancesTooFar(t *testing.T) {
+	scenario := sim.Scenario{
+		Events:   []sim.ScenarioEvent{{At: sim.Duration(time.Hour), Name: "late"}},
+		Advances: []sim.Duration{sim.Duration(time.Second), sim.Duration(time.Hour)},
+	}
+
+	s := sim.New(clock.NewFakeClock(clock.WithMaxAdvance(time.Minute)))
+	n, err := scenario.Replay(s, map[string]func(sim.ScenarioEvent){"late": func(sim.ScenarioEvent) {}})
+	if !errors.Is(err, clock.ErrAdvanceTooFar) {
+		t.Errorf("expected %v got %v", clock.ErrAdvanceTooFar, err)
+	}
+	if n != 0 {
+		t.Errorf("expected no event run, got %d", n)
+	}
+}
+
+func TestScenario_UnknownEvent(t *testing.T) {
+	scenario, _ := sim.ReadScenario(strings.NewReader(scenarioJSON))
+
+	s := sim.New(clock.NewFakeClock())
+	_, err := scenario.Replay(s, map[string]func(sim.ScenarioEvent){"request": func(sim.ScenarioEvent) {}})
+	if !errors.Is(err, sim.ErrUnknownEvent) {
+		t.Errorf("expected %v got %v", sim.ErrUnknownEvent, err)
+	}
+	if pending := s.Stats().Pending; pending != 0 {
+		t.Errorf("expected nothing scheduled, got %d events", pending)
+	}
+}
+
+func TestReadScenario_Invalid(t *testing.T) {
+	for name, input := range map[string]string{
+		"unknown field":   `{"events": [], "speed": 2}`,
+		"missing name":    `{"events": [{"at": "1s"}]}`,
+		"negative offset": `{"events": [{"at": "-1s", "name": "a"}]}`,
+		"negative step":   `{"events": [], "advances": ["1s", "-1s"]}`,
+		"bad duration":    `{"events": [{"at": "soon", "name": "a"}]}`,
+		"number duration": `{"events": [{"at": 1000, "name": "a"}]}`,
+	} {
+		if _, err := sim.ReadScenario(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
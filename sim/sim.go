@@ -0,0 +1,317 @@
+// Package sim runs discrete-event simulations on a clock.FakeClock.
+//
+// Entities schedule events, callbacks due at an instant of simulated time,
+// and the runner executes them one at a time in timestamp order, advancing
+// the clock to each event's time first, so code reading the clock and timers
+// registered on it see the simulated time.
+package sim
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// Sim is a discrete-event simulation driven by a fake clock.
+//
+// Events can be scheduled and canceled from any goroutine, including from
+// events. The Run, RunUntil, RunFor and Step methods must be called from a
+// single goroutine.
+type Sim struct {
+	clock clock.FakeClock
+	start time.Time
+
+	mutex  sync.Mutex
+	events eventHeap
+	seq    uint64
+	stats  Stats
+	trace  bool
+	traced []ScenarioEvent
+	err    error
+}
+
+// An Event is a callback scheduled at an instant of simulated time.
+type Event struct {
+	sim      *Sim
+	at       time.Time
+	name     string
+	priority int
+	f        func()
+	data     []byte
+	seq      uint64
+	index    int
+}
+
+// Stats are statistics about a simulation.
+type Stats struct {
+	// Executed is the number of events run.
+	Executed int
+	// Canceled is the number of events canceled before running.
+	Canceled int
+	// Pending is the number of events waiting to run.
+	Pending int
+	// MaxPending is the largest number of events ever waiting to run.
+	MaxPending int
+	// Elapsed is the simulated time since the simulation was created.
+	Elapsed time.Duration
+	// ByName is the number of events run by name, for named events.
+	ByName map[string]int
+}
+
+// New returns a simulation driven by clock, starting at its current time.
+// The clock should not be strict (see clock.WithStrict), as the simulation
+// advances it between events even when nothing is waiting on it; if it
+// refuses to advance, the simulation stops, and Err returns the error.
+func New(clock clock.FakeClock, opts ...Option) *Sim {
+	s := &Sim{
+		clock: clock,
+		start: clock.Now(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Clock returns the clock driven by the simulation.
+func (s *Sim) Clock() clock.FakeClock {
+	return s.clock
+}
+
+// Now returns the current simulated time.
+func (s *Sim) Now() time.Time {
+	return s.clock.Now()
+}
+
+// Schedule schedules f to run after d of simulated time.
+func (s *Sim) Schedule(d time.Duration, f func(), opts ...EventOption) *Event {
+	return s.ScheduleAt(s.clock.Now().Add(d), f, opts...)
+}
+
+// ScheduleAt schedules f to run at simulated time at, or right away if at
+// has passed. Events at the same time run by decreasing priority, then in
+// the order they were scheduled.
+func (s *Sim) ScheduleAt(at time.Time, f func(), opts ...EventOption) *Event {
+	if now := s.clock.Now(); at.Before(now) {
+		at = now
+	}
+
+	e := &Event{
+		sim: s,
+		at:  at,
+		f:   f,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	e.seq = s.seq
+	s.seq++
+	heap.Push(&s.events, e)
+	if n := len(s.events); n > s.stats.MaxPending {
+		s.stats.MaxPending = n
+	}
+	return e
+}
+
+// At returns the simulated time the event is scheduled at.
+func (e *Event) At() time.Time {
+	return e.at
+}
+
+// Name returns the name of the event, set with WithName.
+func (e *Event) Name() string {
+	return e.name
+}
+
+// Cancel removes the event from the simulation. It returns false if the
+// event already ran or was canceled.
+func (e *Event) Cancel() bool {
+	s := e.sim
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if e.index < 0 {
+		return false
+	}
+	heap.Remove(&s.events, e.index)
+	s.stats.Canceled++
+	return true
+}
+
+// Step runs the next event, advancing the clock to its time.
+// It returns false if no event is pending, or if the clock can't be
+// advanced to the time of the event, which is left pending; see Err.
+func (s *Sim) Step() bool {
+	s.mutex.Lock()
+	if len(s.events) == 0 || s.err != nil {
+		s.mutex.Unlock()
+		return false
+	}
+	e := heap.Pop(&s.events).(*Event)
+	s.mutex.Unlock()
+
+	if !s.advanceTo(e.at) {
+		s.mutex.Lock()
+		heap.Push(&s.events, e)
+		s.mutex.Unlock()
+		return false
+	}
+
+	e.f()
+
+	s.mutex.Lock()
+	s.stats.Executed++
+	if s.trace && e.name != "" {
+		s.traced = append(s.traced, ScenarioEvent{
+			At:       Duration(e.at.Sub(s.start)),
+			Name:     e.name,
+			Priority: e.priority,
+			Data:     e.data,
+		})
+	}
+	if e.name != "" {
+		if s.stats.ByName == nil {
+			s.stats.ByName = map[string]int{}
+		}
+		s.stats.ByName[e.name]++
+	}
+	s.mutex.Unlock()
+	return true
+}
+
+// Run runs events until none is pending, including the events they
+// schedule, and returns the number of events run.
+func (s *Sim) Run() int {
+	n := 0
+	for s.Step() {
+		n++
+	}
+	return n
+}
+
+// RunUntil runs the events due up to and including simulated time t,
+// then advances the clock to t. It returns the number of events run.
+func (s *Sim) RunUntil(t time.Time) int {
+	n := 0
+	for {
+		next, ok := s.Next()
+		if !ok || next.After(t) {
+			break
+		}
+		if !s.Step() {
+			break
+		}
+		n++
+	}
+
+	if s.Err() == nil {
+		s.advanceTo(t)
+	}
+	return n
+}
+
+// RunFor runs the events due within d of simulated time, then advances the
+// clock by d. It returns the number of events run.
+func (s *Sim) RunFor(d time.Duration) int {
+	return s.RunUntil(s.clock.Now().Add(d))
+}
+
+// Next returns the simulated time of the next pending event.
+func (s *Sim) Next() (time.Time, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.events) == 0 {
+		return time.Time{}, false
+	}
+	return s.events[0].at, true
+}
+
+// Stats returns statistics about the simulation so far.
+func (s *Sim) Stats() Stats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats := s.stats
+	stats.Pending = len(s.events)
+	stats.Elapsed = s.clock.Now().Sub(s.start)
+	if s.stats.ByName != nil {
+		stats.ByName = make(map[string]int, len(s.stats.ByName))
+		for name, n := range s.stats.ByName {
+			stats.ByName[name] = n
+		}
+	}
+	return stats
+}
+
+// Err returns the error of the clock that stopped the simulation, such as
+// clock.ErrAdvanceTooFar, or nil. Once the clock failed to advance, Step
+// returns false, and Run, RunUntil and RunFor return right away.
+func (s *Sim) Err() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.err
+}
+
+// advanceTo advances the clock to t, recording the error if the clock
+// refuses to, in which case it returns false.
+func (s *Sim) advanceTo(t time.Time) bool {
+	d := t.Sub(s.clock.Now())
+	if d <= 0 {
+		return true
+	}
+	if err := s.clock.Advance(d); err != nil {
+		s.mutex.Lock()
+		s.err = err
+		s.mutex.Unlock()
+		return false
+	}
+	return true
+}
+
+// eventHeap is a min-heap of events ordered by time, then by decreasing
+// priority, then scheduling order.
+type eventHeap []*Event
+
+func (h eventHeap) Len() int { return len(h) }
+
+func (h eventHeap) Less(i, j int) bool {
+	a, b := h[i], h[j]
+	if !a.at.Equal(b.at) {
+		return a.at.Before(b.at)
+	}
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	return a.seq < b.seq
+}
+
+func (h eventHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *eventHeap) Push(x interface{}) {
+	e := x.(*Event)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *eventHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*h = old[:n-1]
+	return e
+}
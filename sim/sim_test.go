@@ -0,0 +1,173 @@
+package sim_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/sim"
+)
+
+func TestSim_Order(t *testing.T) {
+	start := time.Unix(1, 0)
+	s := sim.New(clock.NewFakeClockAt(start))
+
+	var order []string
+	record := func(name string) func() {
+		return func() {
+			order = append(order, name)
+			if expected, now := start.Add(2*time.Second), s.Now(); name == "c" && !now.Equal(expected) {
+				t.Errorf("expected %s got %s", expected, now)
+			}
+		}
+	}
+
+	s.Schedule(2*time.Second, record("c"))
+	s.Schedule(time.Second, record("b"))
+	s.Schedule(time.Second, record("a"), sim.WithPriority(1))
+	s.Schedule(2*time.Second, record("d"))
+
+	if n := s.Run(); n != 4 {
+		t.Errorf("expected 4 events run, got %d", n)
+	}
+	if expected := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %v got %v", expected, order)
+	}
+}
+
+func TestSim_AdvanceError(t *testing.T) {
+	start := time.Unix(1, 0)
+	s := sim.New(clock.NewFakeClockAt(start, clock.WithMaxAdvance(time.Second)))
+
+	ran := 0
+	s.Schedule(time.Second, func() { ran++ })
+	s.Schedule(3*time.Second, func() { ran++ })
+
+	if n := s.Run(); n != 1 || ran != 1 {
+		t.Errorf("expected 1 event run, got %d (%d ran)", n, ran)
+	}
+	if err := s.Err(); !errors.Is(err, clock.ErrAdvanceTooFar) {
+		t.Errorf("expected %v got %v", clock.ErrAdvanceTooFar, err)
+	}
+	if pending := s.Stats().Pending; pending != 1 {
+		t.Errorf("expected the event left pending, got %d pending", pending)
+	}
+	if now, expected := s.Now(), start.Add(time.Second); !now.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, now)
+	}
+	if s.Step() {
+		t.Error("expected Step to fail once the clock failed to advance")
+	}
+}
+
+func TestSim_ScheduleFromEvent(t *testing.T) {
+	start := time.Unix(1, 0)
+	s := sim.New(clock.NewFakeClockAt(start))
+
+	var at []time.Time
+	var tick func()
+	tick = func() {
+		at = append(at, s.Now())
+		if len(at) < 3 {
+			s.Schedule(time.Second, tick)
+		}
+	}
+	s.Schedule(time.Second, tick)
+
+	s.Run()
+
+	expected := []time.Time{start.Add(time.Second), start.Add(2 * time.Second), start.Add(3 * time.Second)}
+	if !reflect.DeepEqual(at, expected) {
+		t.Errorf("expected %v got %v", expected, at)
+	}
+}
+
+func TestSim_RunUntil(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+	s := sim.New(fake)
+
+	ran := 0
+	for i := 1; i <= 5; i++ {
+		s.Schedule(time.Duration(i)*time.Second, func() { ran++ })
+	}
+
+	if n := s.RunFor(3 * time.Second); n != 3 || ran != 3 {
+		t.Errorf("expected 3 events run, got %d", n)
+	}
+	if now, expected := fake.Now(), start.Add(3*time.Second); !now.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, now)
+	}
+
+	if n := s.RunUntil(start.Add(10 * time.Second)); n != 2 {
+		t.Errorf("expected 2 events run, got %d", n)
+	}
+	if now, expected := fake.Now(), start.Add(10*time.Second); !now.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, now)
+	}
+}
+
+func TestSim_ClockTimers(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+	s := sim.New(fake)
+
+	timer := fake.NewTimer(time.Second)
+	c := timer.C()
+
+	s.Schedule(2*time.Second, func() {
+		select {
+		case at := <-c:
+			if expected := start.Add(time.Second); !at.Equal(expected) {
+				t.Errorf("expected %s got %s", expected, at)
+			}
+		default:
+			t.Error("expected the timer to fire before the event")
+		}
+	})
+	s.Run()
+}
+
+func TestEvent_Cancel(t *testing.T) {
+	s := sim.New(clock.NewFakeClock())
+
+	ran := false
+	e := s.Schedule(time.Second, func() { ran = true })
+	if !e.Cancel() {
+		t.Error("expected the first cancel to succeed")
+	}
+	if e.Cancel() {
+		t.Error("expected the second cancel to fail")
+	}
+
+	s.Run()
+	if ran {
+		t.Error("canceled event ran")
+	}
+}
+
+func TestSim_Stats(t *testing.T) {
+	s := sim.New(clock.NewFakeClock())
+
+	s.Schedule(time.Second, func() {}, sim.WithName("ping"))
+	s.Schedule(2*time.Second, func() {}, sim.WithName("ping"))
+	s.Schedule(3*time.Second, func() {}).Cancel()
+	s.Schedule(4*time.Second, func() {})
+
+	s.RunFor(2 * time.Second)
+
+	stats := s.Stats()
+	expected := sim.Stats{
+		Executed:   2,
+		Canceled:   1,
+		Pending:    1,
+		MaxPending: 3,
+		Elapsed:    2 * time.Second,
+		ByName:     map[string]int{"ping": 2},
+	}
+	if !reflect.DeepEqual(stats, expected) {
+		t.Errorf("expected %+v got %+v", expected, stats)
+	}
+}
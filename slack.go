@@ -0,0 +1,55 @@
+package clock
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// defaultSlack is the slack of the real clocks built without WithSlack,
+// in nanoseconds.
+var defaultSlack int64
+
+// slackEpoch anchors the grid slack rounds deadlines to, so that the timers
+// of every clock with the same slack wake together.
+var slackEpoch = time.Now()
+
+// SetDefaultSlack sets the slack of the real clocks built afterwards by
+// NewRealClock, and by NewRealClockWith without WithSlack. A process that
+// prefers fewer wakeups to punctual timers, such as an agent running on
+// battery, can call it once at startup. The default is 0: timers are precise.
+func SetDefaultSlack(d time.Duration) {
+	atomic.StoreInt64(&defaultSlack, int64(d))
+}
+
+func loadDefaultSlack() time.Duration {
+	return time.Duration(atomic.LoadInt64(&defaultSlack))
+}
+
+// withSlack returns a Clock whose timers and sleeps wake at the first
+// multiple of slack since slackEpoch on or after their deadline, and whose
+// ticker periods are rounded up to a multiple of slack. It returns clock
+// itself if slack isn't positive.
+func withSlack(clock Clock, slack time.Duration) Clock {
+	if slack <= 0 {
+		return clock
+	}
+
+	return &mappedClock{
+		Clock: clock,
+		timer: func(d time.Duration) time.Duration {
+			if d <= 0 {
+				return d
+			}
+			if r := time.Since(slackEpoch.Add(-d)) % slack; r != 0 {
+				d += slack - r
+			}
+			return d
+		},
+		ticker: func(d time.Duration) time.Duration {
+			if r := d % slack; r != 0 {
+				d += slack - r
+			}
+			return d
+		},
+	}
+}
@@ -0,0 +1,32 @@
+package clock_test
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestSleep_Zero_Yields(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+
+	clocks := map[string]clock.Clock{
+		"fake": clock.NewFakeClock(),
+		"real": clock.NewRealClock(),
+	}
+
+	for name, c := range clocks {
+		var ran int32
+		go atomic.StoreInt32(&ran, 1)
+
+		// with a single processor, the goroutine only runs if Sleep yields
+		for i := 0; i < 10 && atomic.LoadInt32(&ran) == 0; i++ {
+			c.Sleep(0)
+		}
+
+		if atomic.LoadInt32(&ran) == 0 {
+			t.Errorf("%s: expected Sleep(0) to yield", name)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package clock
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// sourceClock is a real clock measuring time with a source other than Go's
+// monotonic clock, read as the time elapsed since some origin.
+// Its timers run on Go timers, which follow the monotonic clock, and recheck
+// the source at least every poll, so they fire at most poll late when the
+// source runs ahead of it, as CLOCK_BOOTTIME does through a suspend.
+type sourceClock struct {
+	read func() time.Duration
+	poll time.Duration
+
+	// the wall time when the clock was created, without its monotonic
+	// reading, and the reading of the source at the same time
+	wall   time.Time
+	origin time.Duration
+}
+
+func newSourceClock(read func() time.Duration, poll time.Duration) *sourceClock {
+	return &sourceClock{
+		read:   read,
+		poll:   poll,
+		wall:   time.Now().Round(0),
+		origin: read(),
+	}
+}
+
+// elapsed returns the time elapsed on the source since the clock was created.
+func (clock *sourceClock) elapsed() time.Duration {
+	return clock.read() - clock.origin
+}
+
+// Now returns the wall time the clock was created at, plus the time elapsed
+// on the source since. The time has no monotonic reading, so it differs from
+// time.Now once the wall clock is stepped, or the source runs apart from it.
+func (clock *sourceClock) Now() time.Time {
+	return clock.wall.Add(clock.elapsed())
+}
+
+func (clock *sourceClock) Since(t time.Time) time.Duration {
+	return clock.Now().Sub(t)
+}
+
+func (clock *sourceClock) NewTimer(d time.Duration) Timer {
+	timer := &sourceTimer{
+		clock: clock,
+		c:     make(chan time.Time, 1),
+	}
+	timer.mutex.Lock()
+	defer timer.mutex.Unlock()
+
+	timer.arm(d)
+	return timer
+}
+
+// Sleep pauses the current goroutine for at least the duration d.
+// A zero or negative duration yields the processor to other goroutines.
+func (clock *sourceClock) Sleep(d time.Duration) {
+	if d <= 0 {
+		runtime.Gosched()
+		return
+	}
+	<-clock.NewTimer(d).C()
+}
+
+func (clock *sourceClock) After(d time.Duration) <-chan time.Time {
+	return clock.NewTimer(d).C()
+}
+
+func (clock *sourceClock) AfterFunc(d time.Duration, f func()) Timer {
+	timer := &sourceTimer{
+		clock: clock,
+		f:     f,
+	}
+	timer.mutex.Lock()
+	defer timer.mutex.Unlock()
+
+	timer.arm(d)
+	return timer
+}
+
+func (clock *sourceClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic(errNonPositiveInterval)
+	}
+
+	timer := &sourceTimer{
+		clock:  clock,
+		c:      make(chan time.Time, 1),
+		period: d,
+	}
+	timer.mutex.Lock()
+	defer timer.mutex.Unlock()
+
+	timer.arm(d)
+	return sourceTicker{timer}
+}
+
+func (clock *sourceClock) Tick(d time.Duration) func() <-chan time.Time {
+	if d <= 0 {
+		return func() <-chan time.Time { return nil }
+	}
+
+	c := clock.NewTicker(d).C()
+	return func() <-chan time.Time { return c }
+}
+
+// sourceTimer is a timer, ticker or callback of a sourceClock.
+type sourceTimer struct {
+	clock *sourceClock
+	c     chan time.Time
+	f     func()
+
+	mutex    sync.Mutex
+	period   time.Duration
+	deadline time.Duration
+	active   bool
+	timer    *time.Timer
+	// gen counts the times the timer was armed or stopped, so checks
+	// scheduled before are ignored
+	gen uint64
+}
+
+func (timer *sourceTimer) C() <-chan time.Time {
+	return timer.c
+}
+
+// arm schedules the timer d from now. It must be called with the mutex held.
+func (timer *sourceTimer) arm(d time.Duration) {
+	timer.disarm()
+	timer.deadline = timer.clock.elapsed() + d
+	timer.active = true
+	timer.schedule(d)
+}
+
+// disarm cancels the pending check. It must be called with the mutex held.
+func (timer *sourceTimer) disarm() {
+	timer.gen++
+	if timer.timer != nil {
+		timer.timer.Stop()
+	}
+}
+
+// schedule checks the timer again after remaining, or after the poll
+// interval of the clock if it's shorter. It must be called with the mutex held.
+func (timer *sourceTimer) schedule(remaining time.Duration) {
+	if poll := timer.clock.poll; poll > 0 && remaining > poll {
+		remaining = poll
+	}
+
+	gen := timer.gen
+	timer.timer = time.AfterFunc(remaining, func() { timer.check(gen) })
+}
+
+func (timer *sourceTimer) check(gen uint64) {
+	timer.mutex.Lock()
+	if gen != timer.gen || !timer.active {
+		timer.mutex.Unlock()
+		return
+	}
+
+	now := timer.clock.elapsed()
+	if remaining := timer.deadline - now; remaining > 0 {
+		timer.schedule(remaining)
+		timer.mutex.Unlock()
+		return
+	}
+
+	if timer.period > 0 {
+		// skip the ticks missed, as time.Ticker drops them
+		missed := (now - timer.deadline) / timer.period
+		timer.deadline += (missed + 1) * timer.period
+		timer.schedule(timer.deadline - now)
+	} else {
+		timer.active = false
+	}
+
+	if timer.c != nil {
+		select {
+		case timer.c <- timer.clock.Now():
+		default:
+		}
+	}
+	f := timer.f
+	timer.mutex.Unlock()
+
+	// already on the goroutine of the Go timer
+	if f != nil {
+		f()
+	}
+}
+
+func (timer *sourceTimer) Stop() bool {
+	timer.mutex.Lock()
+	defer timer.mutex.Unlock()
+
+	active := timer.active
+	timer.active = false
+	timer.disarm()
+	return active
+}
+
+func (timer *sourceTimer) Reset(d time.Duration) bool {
+	timer.mutex.Lock()
+	defer timer.mutex.Unlock()
+
+	active := timer.active
+	timer.arm(d)
+	return active
+}
+
+type sourceTicker struct {
+	timer *sourceTimer
+}
+
+func (ticker sourceTicker) C() <-chan time.Time {
+	return ticker.timer.c
+}
+
+func (ticker sourceTicker) Stop() {
+	ticker.timer.Stop()
+}
+
+func (ticker sourceTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic(errNonPositiveInterval)
+	}
+
+	ticker.timer.mutex.Lock()
+	defer ticker.timer.mutex.Unlock()
+
+	ticker.timer.period = d
+	ticker.timer.arm(d)
+}
@@ -0,0 +1,13 @@
+//go:build !linux || tinygo
+
+package clock
+
+import "time"
+
+func bootTimeSource() func() time.Duration {
+	return nil
+}
+
+func rawMonotonicSource() func() time.Duration {
+	return nil
+}
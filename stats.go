@@ -0,0 +1,56 @@
+package clock
+
+// Stats summarizes the sleepers of a fake clock, so tests can check
+// invariants, such as no ticker being left once a component is closed,
+// without going through PendingTimers.
+type Stats struct {
+	// Timers, Tickers, Sleeps, Afters and AfterFuncs count the sleepers
+	// pending on the clock, by the kind of call that registered them.
+	// A ticker is pending once its C is called, until it ticks.
+	Timers     int
+	Tickers    int
+	Sleeps     int
+	Afters     int
+	AfterFuncs int
+
+	// Fired is the number of sleepers woken since the clock was created,
+	// counting each tick of a ticker.
+	Fired int
+
+	// Stopped is the number of timers and tickers stopped before they fired.
+	Stopped int
+
+	// Blockers is the number of calls to Until and BlockUntil waiting.
+	Blockers int
+}
+
+// Pending returns the number of sleepers pending on the clock.
+func (stats Stats) Pending() int {
+	return stats.Timers + stats.Tickers + stats.Sleeps + stats.Afters + stats.AfterFuncs
+}
+
+func (clock *fakeClock) Stats() Stats {
+	clock.mutex.RLock()
+	defer clock.mutex.RUnlock()
+
+	stats := Stats{
+		Fired:    clock.fired,
+		Stopped:  clock.stopped,
+		Blockers: len(clock.blockers),
+	}
+	for _, sleeper := range clock.sleepers {
+		switch sleeper.kind {
+		case KindTimer:
+			stats.Timers++
+		case KindTicker:
+			stats.Tickers++
+		case KindSleep:
+			stats.Sleeps++
+		case KindAfter:
+			stats.Afters++
+		case KindAfterFunc:
+			stats.AfterFuncs++
+		}
+	}
+	return stats
+}
@@ -0,0 +1,55 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestStats(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	timer := fake.NewTimer(time.Second)
+	timer.C()
+	ticker := fake.NewTicker(time.Second)
+	ticker.C()
+	fake.After(2 * time.Second)
+	fake.AfterFunc(time.Hour, func() {})
+	go fake.Sleep(time.Hour)
+	fake.BlockUntil(5)
+	blocked := fake.Until(6)
+
+	expected := clock.Stats{Timers: 1, Tickers: 1, Sleeps: 1, Afters: 1, AfterFuncs: 1, Blockers: 1}
+	if stats := fake.Stats(); stats != expected {
+		t.Errorf("expected %+v got %+v", expected, stats)
+	}
+
+	// the timer and the tick fire, and the ticker is stopped before ticking
+	// again
+	fake.Advance(time.Second)
+	ticker.C()
+	ticker.Stop()
+
+	expected = clock.Stats{Sleeps: 1, Afters: 1, AfterFuncs: 1, Fired: 2, Stopped: 1, Blockers: 1}
+	if stats := fake.Stats(); stats != expected {
+		t.Errorf("expected %+v got %+v", expected, stats)
+	}
+	if pending := fake.Stats().Pending(); pending != 3 {
+		t.Errorf("expected 3 pending sleepers got %d", pending)
+	}
+
+	fake.Advance(time.Hour)
+	expected = clock.Stats{Fired: 5, Stopped: 1, Blockers: 1}
+	if stats := fake.Stats(); stats != expected {
+		t.Errorf("expected %+v got %+v", expected, stats)
+	}
+
+	for i := 0; i < 6; i++ {
+		fake.After(time.Second)
+	}
+	<-blocked
+	if stats := fake.Stats(); stats.Blockers != 0 {
+		t.Errorf("expected no blockers got %d", stats.Blockers)
+	}
+}
@@ -0,0 +1,139 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Stopwatch measures elapsed time on a Clock, excluding the time it's paused,
+// and records laps.
+//
+// It is safe for concurrent use.
+type Stopwatch struct {
+	clock Clock
+
+	mutex   sync.Mutex
+	running bool
+	stopped bool
+	started time.Time
+	elapsed time.Duration
+	lapped  time.Duration
+	laps    []time.Duration
+}
+
+// NewStopwatch returns a Stopwatch measured by clock, which isn't started.
+func NewStopwatch(clock Clock) *Stopwatch {
+	return &Stopwatch{
+		clock: clock,
+	}
+}
+
+// StartStopwatch returns a Stopwatch measured by clock, started now.
+func StartStopwatch(clock Clock) *Stopwatch {
+	sw := NewStopwatch(clock)
+	sw.Start()
+	return sw
+}
+
+// Start starts the stopwatch, or resumes it if paused.
+// Starting a stopped stopwatch starts a new measurement.
+func (sw *Stopwatch) Start() {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+
+	if sw.running {
+		return
+	}
+	if sw.stopped {
+		sw.reset()
+	}
+
+	sw.running = true
+	sw.started = sw.clock.Now()
+}
+
+// Pause suspends the measurement until the stopwatch is started again.
+func (sw *Stopwatch) Pause() {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+
+	sw.pause()
+}
+
+// Stop ends the measurement, returning the elapsed time.
+func (sw *Stopwatch) Stop() time.Duration {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+
+	sw.pause()
+	sw.stopped = true
+	return sw.elapsed
+}
+
+// Reset stops the stopwatch and clears its elapsed time and laps.
+func (sw *Stopwatch) Reset() {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+
+	sw.reset()
+}
+
+// Running reports whether the stopwatch is measuring time.
+func (sw *Stopwatch) Running() bool {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+
+	return sw.running
+}
+
+// Elapsed returns the time measured, excluding pauses.
+func (sw *Stopwatch) Elapsed() time.Duration {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+
+	return sw.total()
+}
+
+// Lap records and returns the time measured since the previous lap,
+// or since the stopwatch started for the first lap.
+func (sw *Stopwatch) Lap() time.Duration {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+
+	total := sw.total()
+	lap := total - sw.lapped
+	sw.lapped = total
+	sw.laps = append(sw.laps, lap)
+	return lap
+}
+
+// Laps returns the laps recorded, in order.
+func (sw *Stopwatch) Laps() []time.Duration {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+
+	return append([]time.Duration(nil), sw.laps...)
+}
+
+func (sw *Stopwatch) total() time.Duration {
+	if !sw.running {
+		return sw.elapsed
+	}
+	return sw.elapsed + sw.clock.Since(sw.started)
+}
+
+func (sw *Stopwatch) pause() {
+	if !sw.running {
+		return
+	}
+	sw.elapsed += sw.clock.Since(sw.started)
+	sw.running = false
+}
+
+func (sw *Stopwatch) reset() {
+	sw.running = false
+	sw.stopped = false
+	sw.elapsed = 0
+	sw.lapped = 0
+	sw.laps = nil
+}
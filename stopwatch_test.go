@@ -0,0 +1,79 @@
+package clock_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func assertElapsed(t *testing.T, expected time.Duration, sw *clock.Stopwatch) {
+	t.Helper()
+
+	if elapsed := sw.Elapsed(); elapsed != expected {
+		t.Errorf("expected %s elapsed got %s", expected, elapsed)
+	}
+}
+
+func TestStopwatch(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	sw := clock.NewStopwatch(fake)
+	fake.Advance(1 * time.Second)
+	assertElapsed(t, 0, sw)
+
+	sw.Start()
+	fake.Advance(2 * time.Second)
+	assertElapsed(t, 2*time.Second, sw)
+
+	sw.Pause()
+	if sw.Running() {
+		t.Error("expected a paused stopwatch not to be running")
+	}
+	fake.Advance(5 * time.Second)
+	assertElapsed(t, 2*time.Second, sw)
+
+	sw.Start()
+	fake.Advance(3 * time.Second)
+	if elapsed := sw.Stop(); elapsed != 5*time.Second {
+		t.Errorf("expected %s got %s", 5*time.Second, elapsed)
+	}
+
+	fake.Advance(1 * time.Second)
+	assertElapsed(t, 5*time.Second, sw)
+
+	// starting a stopped stopwatch starts over
+	sw.Start()
+	fake.Advance(1 * time.Second)
+	assertElapsed(t, 1*time.Second, sw)
+}
+
+func TestStopwatch_Laps(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	sw := clock.StartStopwatch(fake)
+
+	fake.Advance(1 * time.Second)
+	if lap := sw.Lap(); lap != 1*time.Second {
+		t.Errorf("expected %s got %s", 1*time.Second, lap)
+	}
+
+	fake.Advance(1 * time.Second)
+	sw.Pause()
+	fake.Advance(10 * time.Second)
+	sw.Start()
+	fake.Advance(1 * time.Second)
+	sw.Lap()
+
+	expected := []time.Duration{1 * time.Second, 2 * time.Second}
+	if laps := sw.Laps(); !reflect.DeepEqual(laps, expected) {
+		t.Errorf("expected %v got %v", expected, laps)
+	}
+
+	sw.Reset()
+	assertElapsed(t, 0, sw)
+	if laps := sw.Laps(); len(laps) != 0 {
+		t.Errorf("expected no laps got %v", laps)
+	}
+}
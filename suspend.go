@@ -0,0 +1,99 @@
+package clock
+
+import "time"
+
+// A Suspension is a period the system spent suspended, or the process
+// stalled, as detected once it resumed.
+type Suspension struct {
+	// Resumed is the time of the clock when the suspension was detected.
+	Resumed time.Time
+
+	// Duration is how long the suspension lasted, as far as it can be told.
+	Duration time.Duration
+}
+
+// suspendWatcherClock is implemented by clocks that report their suspensions.
+type suspendWatcherClock interface {
+	watchSuspends(f func(Suspension)) func()
+}
+
+// WatchSuspends calls f with each suspension of the system longer than
+// threshold, until the returned function is called, so that keepalive logic
+// can tell time that passed from time the machine spent asleep.
+//
+// On the fake clock, f is called for each call to Suspend, by the goroutine
+// that made it. On other clocks, a timer checks every second how late it
+// woke up, and f is called from a goroutine of its own; the function returned
+// waits for that goroutine to return. On Linux, where Go's monotonic clock
+// stops while the system is suspended, the time spent suspended is also
+// measured with CLOCK_BOOTTIME. The threshold should be well above the
+// scheduling delays of the process, or stalls are reported as suspensions.
+func WatchSuspends(clock Clock, threshold time.Duration, f func(Suspension)) func() {
+	if clock, ok := clock.(suspendWatcherClock); ok {
+		return clock.watchSuspends(func(suspension Suspension) {
+			if suspension.Duration > threshold {
+				f(suspension)
+			}
+		})
+	}
+
+	boot := bootTimeSource()
+
+	var booted time.Duration
+	if boot != nil {
+		booted = boot()
+	}
+	_, mono := NowBoth(clock)
+
+	return poll(clock, func() {
+		_, nextMono := NowBoth(clock)
+		elapsed := time.Duration(nextMono - mono)
+		mono = nextMono
+
+		// the timer woke up late by the time the process didn't run
+		gap := elapsed - wallPoll
+		if boot != nil {
+			nextBooted := boot()
+			if suspended := nextBooted - booted - elapsed; suspended > gap {
+				gap = suspended
+			}
+			booted = nextBooted
+		}
+
+		if gap > threshold {
+			f(Suspension{Resumed: clock.Now(), Duration: gap})
+		}
+	})
+}
+
+// SuspendWatcher delivers the suspensions of the system on a channel.
+type SuspendWatcher struct {
+	c    chan Suspension
+	stop func()
+}
+
+// NewSuspendWatcher returns a SuspendWatcher of the suspensions longer than
+// threshold, as reported by WatchSuspends. Like a Ticker, it drops
+// suspensions that the consumer isn't ready to receive.
+func NewSuspendWatcher(clock Clock, threshold time.Duration) *SuspendWatcher {
+	watcher := &SuspendWatcher{
+		c: make(chan Suspension, 1),
+	}
+	watcher.stop = WatchSuspends(clock, threshold, func(suspension Suspension) {
+		select {
+		case watcher.c <- suspension:
+		default:
+		}
+	})
+	return watcher
+}
+
+// C returns the channel on which the suspensions are delivered.
+func (watcher *SuspendWatcher) C() <-chan Suspension {
+	return watcher.c
+}
+
+// Stop turns off the watcher. After Stop, no more suspensions will be sent.
+func (watcher *SuspendWatcher) Stop() {
+	watcher.stop()
+}
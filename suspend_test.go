@@ -0,0 +1,71 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestSuspendWatcher(t *testing.T) {
+	fake := clock.NewFakeClock()
+	start := fake.Now()
+
+	watcher := clock.NewSuspendWatcher(fake, time.Second)
+	defer watcher.Stop()
+	jumps := clock.NewJumpWatcher(fake, time.Second)
+	defer jumps.Stop()
+
+	timer := fake.NewTimer(time.Minute)
+	defer timer.Stop()
+	c := timer.C()
+
+	// a suspension within the threshold isn't reported
+	fake.Suspend(time.Second)
+	select {
+	case suspension := <-watcher.C():
+		t.Errorf("unexpected suspension %+v", suspension)
+	default:
+	}
+
+	fake.Suspend(time.Hour)
+	resumed := start.Add(time.Hour + time.Second)
+	select {
+	case suspension := <-watcher.C():
+		if !suspension.Resumed.Equal(resumed) || suspension.Duration != time.Hour {
+			t.Errorf("expected a suspension of 1h until %s got %+v", resumed, suspension)
+		}
+	default:
+		t.Error("expected a suspension")
+	}
+	assertClockAt(t, resumed, fake)
+
+	// the wall clock jumped, but the timer still has a minute to wait
+	select {
+	case <-jumps.C():
+	default:
+		t.Error("expected a jump")
+	}
+	assertNotSent(t, c)
+
+	fake.Advance(time.Minute)
+	assertSent(t, resumed.Add(time.Minute), c)
+}
+
+func TestWatchSuspends_Real(t *testing.T) {
+	stop := clock.WatchSuspends(clock.NewRealClock(), time.Minute, func(suspension clock.Suspension) {
+		t.Errorf("unexpected suspension %+v", suspension)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("timeout: stop didn't return")
+	}
+}
@@ -0,0 +1,39 @@
+package clock
+
+// Tagged returns a view of clock whose sleepers are tagged with tag, so a
+// test can wait for them with UntilTagged or BlockUntilTagged, whatever
+// other sleepers, such as background tickers, are waiting on the clock.
+// The tag also shows in PendingTimers.
+//
+// Tags only apply to fake clocks, whose views can't be asserted back to a
+// FakeClock, like ReadOnly. Tagged returns other clocks as is.
+func Tagged(clock Clock, tag string) Clock {
+	if view, ok := view(clock, func(l *labels) { l.tag = tag }); ok {
+		return view
+	}
+	return clock
+}
+
+func (clock *fakeClock) UntilTagged(tag string, n int) <-chan struct{} {
+	return clock.until(tag, n)
+}
+
+func (clock *fakeClock) BlockUntilTagged(tag string, n int) {
+	<-clock.until(tag, n)
+}
+
+// waiting returns the number of sleepers with tag, or of all sleepers if tag
+// is empty. The caller holds the clock's mutex.
+func (clock *fakeClock) waiting(tag string) int {
+	if tag == "" {
+		return len(clock.sleepers)
+	}
+
+	n := 0
+	for _, sleeper := range clock.sleepers {
+		if sleeper.tag == tag {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,55 @@
+package clock_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestTagged(t *testing.T) {
+	fake := clock.NewFakeClock()
+	retries := clock.Tagged(fake, "retry")
+
+	// a background ticker doesn't count towards the tagged sleepers
+	ticker := fake.NewTicker(time.Second)
+	defer ticker.Stop()
+	ticker.C()
+
+	blocked := fake.UntilTagged("retry", 2)
+	go retries.Sleep(time.Minute)
+	select {
+	case <-blocked:
+		t.Fatal("expected to wait for 2 tagged sleepers")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	c := retries.After(time.Minute)
+	fake.BlockUntilTagged("retry", 2)
+	<-blocked
+
+	var tagged int
+	for _, timer := range fake.PendingTimers() {
+		if timer.Tag == "retry" {
+			tagged++
+			if !strings.HasSuffix(timer.String(), ` tagged "retry"`) {
+				t.Errorf("expected the tag in %q", timer)
+			}
+		}
+	}
+	if tagged != 2 {
+		t.Errorf("expected 2 tagged timers got %d", tagged)
+	}
+
+	// views keep the labels of the view they're made from
+	both := clock.Prioritized(retries, 1)
+	both.AfterFunc(time.Minute, func() {})
+	fake.BlockUntilTagged("retry", 3)
+
+	fake.Advance(time.Minute)
+	assertSent(t, fake.Now(), c)
+	if real := clock.NewRealClock(); clock.Tagged(real, "retry") != real {
+		t.Error("expected other clocks returned as is")
+	}
+}
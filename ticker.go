@@ -0,0 +1,238 @@
+package clock
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A TickerOption configures a CustomTicker.
+type TickerOption func(*tickerConfig)
+
+type tickerConfig struct {
+	immediate    bool
+	backpressure Backpressure
+	fixed        bool
+}
+
+// Backpressure selects what a CustomTicker does with a tick
+// when the previous tick hasn't been received yet.
+type Backpressure int
+
+const (
+	// Drop discards the new tick, like the Ticker returned by NewTicker.
+	Drop Backpressure = iota
+
+	// Coalesce replaces the pending tick with the new one,
+	// so the consumer receives the most recent tick.
+	Coalesce
+
+	// Block waits for the consumer to receive the pending tick
+	// before delivering the new one and scheduling the following tick.
+	// No tick is lost, but the effective period stretches to match the consumer.
+	Block
+)
+
+// WithBackpressure sets what the ticker does when its consumer falls behind.
+// The default is Drop.
+// Ticks discarded by Drop or replaced by Coalesce are counted by Skipped.
+func WithBackpressure(backpressure Backpressure) TickerOption {
+	return func(config *tickerConfig) {
+		config.backpressure = backpressure
+	}
+}
+
+// WithImmediateTick makes the ticker deliver a tick as soon as it's created,
+// then tick at its interval.
+func WithImmediateTick() TickerOption {
+	return func(config *tickerConfig) {
+		config.immediate = true
+	}
+}
+
+// WithDriftCorrection schedules each tick at start + n*d, start being the
+// time the ticker was created or last reset, instead of d after the previous
+// tick fired, so the time it takes to wake up and deliver a tick doesn't
+// accumulate over the ticks. If the ticker falls more than a period behind,
+// as it may with Block, it delivers one tick late right away, and skips the
+// ticks before it, counting them in Skipped.
+func WithDriftCorrection() TickerOption {
+	return func(config *tickerConfig) {
+		config.fixed = true
+	}
+}
+
+// CustomTicker is a Ticker whose behavior is configured by TickerOptions.
+//
+// It's built on the timers of any Clock, so its behavior is the same on the
+// real and fake clocks. Unlike the Ticker returned by Clock.NewTicker,
+// its C method always returns the same channel.
+type CustomTicker struct {
+	clock   Clock
+	config  tickerConfig
+	c       chan time.Time
+	skipped int64
+
+	mutex sync.Mutex
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// NewCustomTicker returns a CustomTicker driven by clock, ticking every d.
+// The duration d must be greater than zero; if not, NewCustomTicker will panic.
+func NewCustomTicker(clock Clock, d time.Duration, opts ...TickerOption) *CustomTicker {
+	if d <= 0 {
+		panic(errNonPositiveInterval)
+	}
+
+	var config tickerConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	ticker := &CustomTicker{
+		clock:  clock,
+		config: config,
+		c:      make(chan time.Time, 1),
+	}
+
+	ticker.mutex.Lock()
+	defer ticker.mutex.Unlock()
+
+	ticker.start(d, config.immediate)
+	return ticker
+}
+
+// NewTickerImmediate returns a CustomTicker driven by clock that delivers
+// a tick right away, then ticks every d.
+func NewTickerImmediate(clock Clock, d time.Duration) *CustomTicker {
+	return NewCustomTicker(clock, d, WithImmediateTick())
+}
+
+// C returns the channel on which the ticks are delivered.
+func (ticker *CustomTicker) C() <-chan time.Time {
+	return ticker.c
+}
+
+// Skipped returns the number of ticks discarded or replaced
+// because the consumer fell behind.
+func (ticker *CustomTicker) Skipped() int64 {
+	return atomic.LoadInt64(&ticker.skipped)
+}
+
+// Stop turns off the ticker. After Stop, no more ticks will be sent.
+func (ticker *CustomTicker) Stop() {
+	ticker.mutex.Lock()
+	defer ticker.mutex.Unlock()
+
+	ticker.halt()
+}
+
+// Reset stops the ticker and resets its period to d.
+// The next tick will arrive after the new period elapses.
+func (ticker *CustomTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic(errNonPositiveInterval)
+	}
+
+	ticker.mutex.Lock()
+	defer ticker.mutex.Unlock()
+
+	ticker.halt()
+	ticker.start(d, false)
+}
+
+func (ticker *CustomTicker) start(d time.Duration, immediate bool) {
+	ticker.stop = make(chan struct{})
+	ticker.done = make(chan struct{})
+	go ticker.run(d, immediate, ticker.stop, ticker.done)
+}
+
+// halt stops the ticking goroutine and waits for it to exit.
+func (ticker *CustomTicker) halt() {
+	if ticker.stop == nil {
+		return
+	}
+	close(ticker.stop)
+	<-ticker.done
+	ticker.stop = nil
+}
+
+func (ticker *CustomTicker) run(d time.Duration, immediate bool, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	if immediate && !ticker.send(ticker.clock.Now(), stop) {
+		return
+	}
+
+	next := ticker.clock.Now().Add(d)
+	timer := ticker.clock.NewTimer(d)
+	defer timer.Stop()
+
+	for {
+		select {
+		case at := <-timer.C():
+			if !ticker.send(at, stop) {
+				return
+			}
+			if ticker.config.fixed {
+				timer.Reset(ticker.schedule(&next, d))
+			} else {
+				timer.Reset(d)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// schedule moves next to the tick following it on the ticker's schedule,
+// skipping the ticks already overdue but the last one, and returns the time
+// left until it.
+func (ticker *CustomTicker) schedule(next *time.Time, d time.Duration) time.Duration {
+	now := ticker.clock.Now()
+
+	*next = next.Add(d)
+	if behind := now.Sub(*next); behind >= d {
+		// the whole periods behind are skipped at once
+		n := behind / d
+		*next = next.Add(n * d)
+		atomic.AddInt64(&ticker.skipped, int64(n))
+	}
+	return next.Sub(now)
+}
+
+// send delivers a tick according to the ticker's backpressure.
+// It returns false if the ticker was stopped while blocked.
+func (ticker *CustomTicker) send(at time.Time, stop <-chan struct{}) bool {
+	switch ticker.config.backpressure {
+	case Block:
+		select {
+		case ticker.c <- at:
+			return true
+		case <-stop:
+			return false
+		}
+	case Coalesce:
+		for {
+			select {
+			case ticker.c <- at:
+				return true
+			default:
+			}
+
+			select {
+			case <-ticker.c:
+				atomic.AddInt64(&ticker.skipped, 1)
+			default:
+			}
+		}
+	default:
+		select {
+		case ticker.c <- at:
+		default:
+			atomic.AddInt64(&ticker.skipped, 1)
+		}
+		return true
+	}
+}
@@ -0,0 +1,238 @@
+package clock_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestCustomTicker(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := clock.NewCustomTicker(fake, 1*time.Second)
+	defer ticker.Stop()
+
+	c := ticker.C()
+	assertClockUntil(t, 1, fake)
+	assertNotSent(t, c)
+
+	fake.Advance(1 * time.Second)
+	assertSent(t, start.Add(1*time.Second), c)
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+	assertSent(t, start.Add(2*time.Second), c)
+}
+
+func TestNewTickerImmediate(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := clock.NewTickerImmediate(fake, 1*time.Second)
+	defer ticker.Stop()
+
+	c := ticker.C()
+	assertSent(t, start, c)
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+	assertSent(t, start.Add(1*time.Second), c)
+}
+
+func TestCustomTicker_Stop(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := clock.NewCustomTicker(fake, 1*time.Second)
+	assertClockUntil(t, 1, fake)
+
+	ticker.Stop()
+	fake.Advance(1 * time.Second)
+	assertNotSent(t, ticker.C())
+}
+
+func TestCustomTicker_Reset(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := clock.NewCustomTicker(fake, 1*time.Second, clock.WithImmediateTick())
+	defer ticker.Stop()
+
+	c := ticker.C()
+	assertSent(t, start, c)
+
+	ticker.Reset(2 * time.Second)
+	assertNotSent(t, c)
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+	assertNotSent(t, c)
+	fake.Advance(1 * time.Second)
+	assertSent(t, start.Add(2*time.Second), c)
+}
+
+func TestCustomTicker_RealClock(t *testing.T) {
+	ticker := clock.NewTickerImmediate(clock.NewRealClock(), 10*time.Millisecond)
+	defer ticker.Stop()
+
+	c := ticker.C()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-c:
+		case <-time.After(sentTimeout):
+			t.Fatal("timeout: waiting for tick")
+		}
+	}
+}
+
+func TestCustomTicker_Drop(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := clock.NewCustomTicker(fake, 1*time.Second)
+	defer ticker.Stop()
+
+	for i := 0; i < 3; i++ {
+		assertClockUntil(t, 1, fake)
+		fake.Advance(1 * time.Second)
+	}
+
+	assertClockUntil(t, 1, fake)
+	assertSent(t, start.Add(1*time.Second), ticker.C())
+	if skipped := ticker.Skipped(); skipped != 2 {
+		t.Errorf("expected %d skipped got %d", 2, skipped)
+	}
+}
+
+func TestCustomTicker_Coalesce(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := clock.NewCustomTicker(fake, 1*time.Second, clock.WithBackpressure(clock.Coalesce))
+	defer ticker.Stop()
+
+	for i := 0; i < 3; i++ {
+		assertClockUntil(t, 1, fake)
+		fake.Advance(1 * time.Second)
+	}
+
+	assertClockUntil(t, 1, fake)
+	assertSent(t, start.Add(3*time.Second), ticker.C())
+	if skipped := ticker.Skipped(); skipped != 2 {
+		t.Errorf("expected %d skipped got %d", 2, skipped)
+	}
+}
+
+func TestCustomTicker_Block(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := clock.NewCustomTicker(fake, 1*time.Second, clock.WithBackpressure(clock.Block))
+	defer ticker.Stop()
+
+	c := ticker.C()
+	for i := 0; i < 2; i++ {
+		assertClockUntil(t, 1, fake)
+		fake.Advance(1 * time.Second)
+	}
+
+	// the ticker is blocked delivering the second tick
+	fake.Advance(5 * time.Second)
+
+	assertSent(t, start.Add(1*time.Second), c)
+	assertSent(t, start.Add(2*time.Second), c)
+
+	// the next tick is scheduled once the consumer caught up
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+	assertSent(t, start.Add(8*time.Second), c)
+
+	if skipped := ticker.Skipped(); skipped != 0 {
+		t.Errorf("expected %d skipped got %d", 0, skipped)
+	}
+}
+
+func TestCustomTicker_DriftCorrection(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	// timers fire up to 100ms late, but the ticks stay on the schedule
+	jittery := clock.Jitter(fake, 100*time.Millisecond, rand.New(rand.NewSource(1)))
+	ticker := clock.NewCustomTicker(jittery, 1*time.Second, clock.WithDriftCorrection())
+	defer ticker.Stop()
+
+	c := ticker.C()
+	for n := 1; n <= 10; n++ {
+		assertClockUntil(t, 1, fake)
+
+		deadline := fake.PendingTimers()[0].Deadline
+		scheduled := start.Add(time.Duration(n) * time.Second)
+		if deadline.Before(scheduled) || deadline.After(scheduled.Add(100*time.Millisecond)) {
+			t.Fatalf("expected tick %d within 100ms of %s got %s", n, scheduled, deadline)
+		}
+
+		fake.Advance(deadline.Sub(fake.Now()))
+		assertSent(t, deadline, c)
+	}
+}
+
+func TestCustomTicker_DriftCorrection_Behind(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := clock.NewCustomTicker(fake, 1*time.Second, clock.WithDriftCorrection(), clock.WithBackpressure(clock.Block))
+	defer ticker.Stop()
+
+	c := ticker.C()
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+	assertClockUntil(t, 1, fake)
+
+	// the ticker blocks on the tick due at 2s until 4.5s, so it delivers
+	// the tick due at 4s late, skipping the one due at 3s, then keeps to
+	// the schedule
+	fake.Advance(3500 * time.Millisecond)
+	assertSent(t, start.Add(1*time.Second), c)
+	assertSent(t, start.Add(2*time.Second), c)
+	assertSent(t, start.Add(4500*time.Millisecond), c)
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(500 * time.Millisecond)
+	assertSent(t, start.Add(5*time.Second), c)
+
+	if skipped := ticker.Skipped(); skipped != 1 {
+		t.Errorf("expected 1 skipped tick got %d", skipped)
+	}
+}
+
+func TestCustomTicker_DriftCorrection_FarBehind(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	ticker := clock.NewCustomTicker(fake, 2*time.Nanosecond, clock.WithDriftCorrection(), clock.WithBackpressure(clock.Block))
+	defer ticker.Stop()
+
+	c := ticker.C()
+	assertClockUntil(t, 1, fake)
+	fake.Advance(2 * time.Nanosecond)
+	assertClockUntil(t, 1, fake)
+
+	// catching up doesn't step through each of the ticks missed
+	late := start.Add(1000*time.Hour + time.Nanosecond)
+	fake.Advance(late.Sub(fake.Now()))
+	assertSent(t, start.Add(2*time.Nanosecond), c)
+	assertSent(t, start.Add(4*time.Nanosecond), c)
+	assertSent(t, late, c)
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(time.Nanosecond)
+	assertSent(t, late.Add(time.Nanosecond), c)
+
+	// the ticks due from 6ns to 1000h, but the last one, delivered late
+	if expected, skipped := int64(1000*time.Hour/(2*time.Nanosecond))-3, ticker.Skipped(); skipped != expected {
+		t.Errorf("expected %d skipped ticks got %d", expected, skipped)
+	}
+}
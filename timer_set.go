@@ -0,0 +1,108 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// TimerSet manages deadlines keyed by K, such as the idle timeout of each
+// session of a server, on a single timer and a single goroutine, however
+// many keys it holds. The keys whose deadline is reached are delivered on
+// its channel, one at a time, ordered by deadline.
+//
+// It is safe for concurrent use.
+type TimerSet[K comparable] struct {
+	clock Clock
+	queue *DeadlineQueue[K]
+	c     chan K
+
+	once sync.Once
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewTimerSet returns an empty TimerSet measuring deadlines by clock.
+// Stop releases its goroutine.
+func NewTimerSet[K comparable](clock Clock) *TimerSet[K] {
+	set := &TimerSet[K]{
+		clock: clock,
+		queue: NewDeadlineQueue[K](clock),
+		c:     make(chan K),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go set.run()
+	return set
+}
+
+// C returns the channel on which the keys are delivered when their
+// deadline is reached.
+func (set *TimerSet[K]) C() <-chan K {
+	return set.c
+}
+
+// Set sets the deadline of key to t, replacing its previous deadline.
+// If t is past, key is delivered right away.
+func (set *TimerSet[K]) Set(key K, t time.Time) {
+	set.queue.Add(key, t)
+}
+
+// Reset sets the deadline of key to d from now, replacing its previous
+// deadline.
+func (set *TimerSet[K]) Reset(key K, d time.Duration) {
+	set.queue.Add(key, set.clock.Now().Add(d))
+}
+
+// Cancel removes the deadline of key. It returns false if key had no
+// deadline, because it was never set, was canceled, or was delivered or is
+// being delivered.
+func (set *TimerSet[K]) Cancel(key K) bool {
+	return set.queue.Remove(key)
+}
+
+// Deadline returns the deadline of key.
+func (set *TimerSet[K]) Deadline(key K) (time.Time, bool) {
+	return set.queue.Deadline(key)
+}
+
+// Len returns the number of keys with a deadline.
+func (set *TimerSet[K]) Len() int {
+	return set.queue.Len()
+}
+
+// Stop releases the set's timer and goroutine. After Stop, no more keys
+// are delivered.
+func (set *TimerSet[K]) Stop() {
+	set.once.Do(func() {
+		close(set.stop)
+		<-set.done
+		set.queue.Stop()
+	})
+}
+
+func (set *TimerSet[K]) run() {
+	defer close(set.done)
+
+	for {
+		select {
+		case <-set.queue.C():
+		case <-set.stop:
+			return
+		}
+
+		// each key is popped just before it's delivered, so Cancel works
+		// on the keys due while the consumer is behind
+		for {
+			key, ok := set.queue.popDue()
+			if !ok {
+				break
+			}
+
+			select {
+			case set.c <- key:
+			case <-set.stop:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func assertKey(t *testing.T, expected string, c <-chan string) {
+	t.Helper()
+
+	timer := time.NewTimer(sentTimeout)
+	defer timer.Stop()
+
+	select {
+	case key := <-c:
+		if key != expected {
+			t.Errorf("expected %s got %s", expected, key)
+		}
+	case <-timer.C:
+		t.Errorf("timeout: after %s", sentTimeout)
+	}
+}
+
+func assertNoKey(t *testing.T, c <-chan string) {
+	t.Helper()
+
+	timer := time.NewTimer(notSentTimeout)
+	defer timer.Stop()
+
+	select {
+	case key := <-c:
+		t.Errorf("key %s sent unexpectedly", key)
+	case <-timer.C:
+	}
+}
+
+func TestTimerSet(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	set := clock.NewTimerSet[string](fake)
+	defer set.Stop()
+
+	set.Set("b", start.Add(2*time.Second))
+	set.Set("a", start.Add(1*time.Second))
+	set.Reset("c", 3*time.Second)
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+	assertKey(t, "a", set.C())
+
+	// moving a deadline replaces it
+	set.Reset("b", 2*time.Second)
+	if deadline, ok := set.Deadline("b"); !ok || deadline != start.Add(3*time.Second) {
+		t.Errorf("expected deadline %s got %s", start.Add(3*time.Second), deadline)
+	}
+
+	if !set.Cancel("c") {
+		t.Error("expected c to be canceled")
+	}
+	if set.Cancel("c") {
+		t.Error("expected c to be canceled once")
+	}
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(1 * time.Second)
+	assertNoKey(t, set.C())
+
+	fake.Advance(1 * time.Second)
+	assertKey(t, "b", set.C())
+
+	if n := set.Len(); n != 0 {
+		t.Errorf("expected %d keys got %d", 0, n)
+	}
+}
+
+func TestTimerSet_SlowConsumer(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	set := clock.NewTimerSet[string](fake)
+	defer set.Stop()
+
+	set.Set("a", start.Add(1*time.Second))
+	set.Set("b", start.Add(2*time.Second))
+	set.Set("c", start.Add(3*time.Second))
+
+	assertClockUntil(t, 1, fake)
+	fake.Advance(3 * time.Second)
+
+	// the keys due while the consumer is behind can still be canceled,
+	// but for the one being delivered
+	assertKey(t, "a", set.C())
+	if !set.Cancel("c") {
+		t.Error("expected c to be canceled")
+	}
+	assertKey(t, "b", set.C())
+	assertNoKey(t, set.C())
+}
+
+func TestTimerSet_Stop(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	set := clock.NewTimerSet[string](fake)
+	set.Set("a", fake.Now())
+	set.Stop()
+	set.Stop()
+
+	fake.Advance(time.Second)
+	assertNoKey(t, set.C())
+}
@@ -0,0 +1,139 @@
+package clock_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+type timerOp string
+
+const (
+	opStop   timerOp = "Stop"
+	opReset  timerOp = "Reset"
+	opExpire timerOp = "expire"
+	opRecv   timerOp = "recv"
+)
+
+// timerDriver creates a timer and lets its deadline pass.
+type timerDriver struct {
+	d      time.Duration
+	clock  clock.Clock
+	expire func()
+}
+
+// runTimerOps applies ops to a new timer, returning the results of Stop and
+// Reset, and whether a value was ready to be received at each recv.
+// If eager, C is called right away; otherwise it's called only to receive.
+func runTimerOps(driver timerDriver, ops []timerOp, eager bool) []string {
+	timer := driver.clock.NewTimer(driver.d)
+	if eager {
+		timer.C()
+	}
+
+	var results []string
+	for _, op := range ops {
+		switch op {
+		case opStop:
+			results = append(results, fmt.Sprintf("Stop=%t", timer.Stop()))
+		case opReset:
+			results = append(results, fmt.Sprintf("Reset=%t", timer.Reset(driver.d)))
+		case opExpire:
+			driver.expire()
+		case opRecv:
+			select {
+			case <-timer.C():
+				results = append(results, "recv=true")
+			default:
+				results = append(results, "recv=false")
+			}
+		}
+	}
+	timer.Stop()
+
+	return results
+}
+
+// TestTimer_MatchesReal checks that fake timers follow the state machine of
+// time.Timer, whether or not C was called before the timer expired.
+//
+// Sequences that stop or reset a timer that expired without being received
+// from are left out: since Go 1.23, time.Timer discards such values and
+// reports the timer as stopped, while the fake keeps reporting it as fired.
+func TestTimer_MatchesReal(t *testing.T) {
+	cases := [][]timerOp{
+		{opStop, opStop, opExpire, opRecv},
+		{opExpire, opRecv, opStop, opRecv},
+		{opReset, opExpire, opRecv, opRecv},
+		{opExpire, opRecv, opReset, opRecv, opExpire, opRecv},
+		{opStop, opReset, opStop, opExpire, opRecv},
+		{opStop, opReset, opExpire, opRecv, opStop},
+		{opExpire, opRecv, opReset, opReset, opStop, opReset, opExpire, opRecv},
+	}
+
+	const d = 50 * time.Millisecond
+
+	for _, ops := range cases {
+		ops := ops
+		name := strings.Trim(fmt.Sprint(ops), "[]")
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			real := timerDriver{
+				d:      d,
+				clock:  clock.NewRealClock(),
+				expire: func() { time.Sleep(2 * d) },
+			}
+			expected := runTimerOps(real, ops, true)
+
+			for _, eager := range []bool{true, false} {
+				fake := clock.NewFakeClock()
+				driver := timerDriver{
+					d:      d,
+					clock:  fake,
+					expire: func() { fake.Advance(d) },
+				}
+
+				if actual := runTimerOps(driver, ops, eager); !reflect.DeepEqual(actual, expected) {
+					t.Errorf("eager C=%t: expected %v got %v", eager, expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestNewTimer_Stop_NeverCalledC_Expired(t *testing.T) {
+	start := time.Unix(1, 0)
+	clock := clock.NewFakeClockAt(start)
+
+	timer := clock.NewTimer(1 * time.Second)
+	clock.Advance(1 * time.Second)
+
+	// an expired timer counts as fired even if C was never called
+	if timer.Stop() {
+		t.Error("expected stop to return false")
+	}
+	assertSent(t, start.Add(1*time.Second), timer.C())
+}
+
+func TestNewTimer_Stop_NeverCalledC_NeverFires(t *testing.T) {
+	clock := clock.NewFakeClock()
+
+	timer := clock.NewTimer(1 * time.Second)
+	if !timer.Stop() {
+		t.Error("expected stop to return true")
+	}
+
+	c := timer.C()
+	clock.Advance(1 * time.Second)
+	assertNotSent(t, c)
+
+	if timers := clock.PendingTimers(); len(timers) != 0 {
+		t.Errorf("expected no pending timers got %v", timers)
+	}
+}
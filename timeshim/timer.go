@@ -0,0 +1,136 @@
+package timeshim
+
+import (
+	"sync"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// Timer mirrors time.Timer, exposing its channel as the C field.
+type Timer struct {
+	C <-chan Time
+
+	timer clock.Timer
+}
+
+// NewTimer creates a new Timer that will send the current time on its channel after at least duration d.
+func NewTimer(d Duration) *Timer {
+	timer := Clock().NewTimer(d)
+	return &Timer{
+		C:     timer.C(),
+		timer: timer,
+	}
+}
+
+// AfterFunc waits for the duration to elapse and then calls f in its own goroutine.
+// The returned Timer's C field is nil.
+func AfterFunc(d Duration, f func()) *Timer {
+	return &Timer{
+		timer: Clock().AfterFunc(d, f),
+	}
+}
+
+// Stop prevents the Timer from firing. See time.Timer.Stop.
+func (t *Timer) Stop() bool {
+	return t.timer.Stop()
+}
+
+// Reset changes the timer to expire after duration d. See time.Timer.Reset.
+func (t *Timer) Reset(d Duration) bool {
+	active := t.timer.Reset(d)
+	if t.C != nil {
+		t.C = t.timer.C()
+	}
+	return active
+}
+
+// Ticker mirrors time.Ticker, exposing its channel as the C field.
+//
+// Ticks are forwarded to C by a goroutine, so that the clock's
+// per-tick C() calls happen behind the scenes.
+// Like time.Ticker, ticks are dropped for slow receivers.
+type Ticker struct {
+	C <-chan Time
+
+	mutex  sync.Mutex
+	ticker clock.Ticker
+	stop   chan struct{}
+	done   chan struct{}
+	c      chan Time
+
+	// last is the channel last returned by the ticker's C, which the
+	// forwarder receives on; a Reset moves its next tick to the new
+	// schedule, so it's kept for the forwarder restarted after a Stop
+	last <-chan Time
+}
+
+// NewTicker returns a new Ticker delivering ticks at intervals of d.
+// It panics if d <= 0.
+func NewTicker(d Duration) *Ticker {
+	c := make(chan Time, 1)
+	ticker := &Ticker{
+		C:      c,
+		ticker: Clock().NewTicker(d),
+		c:      c,
+	}
+	ticker.last = ticker.ticker.C()
+	ticker.start()
+	return ticker
+}
+
+func (t *Ticker) start() {
+	t.stop = make(chan struct{})
+	t.done = make(chan struct{})
+	go t.forward(t.stop, t.done)
+}
+
+// halt stops the forwarding goroutine and waits for it to exit.
+func (t *Ticker) halt() {
+	if t.stop == nil {
+		return
+	}
+	close(t.stop)
+	<-t.done
+	t.stop = nil
+}
+
+// forward forwards the ticks to C until stop is closed. It owns last
+// until done is closed.
+func (t *Ticker) forward(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	for {
+		select {
+		case tick := <-t.last:
+			select {
+			case t.c <- tick:
+			default:
+			}
+			t.last = t.ticker.C()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Stop turns off the ticker. See time.Ticker.Stop.
+func (t *Ticker) Stop() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.ticker.Stop()
+	t.halt()
+}
+
+// Reset stops the ticker and resets its period to d. See time.Ticker.Reset.
+func (t *Ticker) Reset(d Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	// the forwarder keeps receiving on the channel it holds, which Reset
+	// moves to the new schedule; it's only restarted after a Stop
+	t.ticker.Reset(d)
+	if t.stop == nil {
+		t.start()
+	}
+}
@@ -0,0 +1,72 @@
+// Package timeshim exports the surface of the time package,
+// routing every function that reads or waits on the current time through a swappable clock.Clock.
+//
+// It allows a mechanical migration of existing code,
+// by replacing the "time" import with "github.com/go-toolbelt/clock/timeshim",
+// before the clock is injected properly. Tests then swap the clock with SetClock.
+package timeshim
+
+import (
+	"sync"
+
+	"github.com/go-toolbelt/clock"
+)
+
+var (
+	mutex   sync.RWMutex
+	current = clock.NewRealClock()
+)
+
+// SetClock routes the package's functions through c.
+// It returns a function that restores the previous clock.
+func SetClock(c clock.Clock) (restore func()) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	previous := current
+	current = c
+	return func() { SetClock(previous) }
+}
+
+// Clock returns the clock the package's functions are routed through.
+func Clock() clock.Clock {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	return current
+}
+
+// Now returns the current time of the clock.
+func Now() Time {
+	return Clock().Now()
+}
+
+// Since returns the time elapsed since t.
+func Since(t Time) Duration {
+	return Clock().Since(t)
+}
+
+// Until returns the duration until t.
+func Until(t Time) Duration {
+	return t.Sub(Clock().Now())
+}
+
+// Sleep pauses the current goroutine for at least the duration d.
+func Sleep(d Duration) {
+	Clock().Sleep(d)
+}
+
+// After waits for the duration to elapse and then sends the current time on the returned channel.
+func After(d Duration) <-chan Time {
+	return Clock().After(d)
+}
+
+// Tick returns a channel delivering ticks at intervals of d.
+// Unlike time.Tick, the ticker cannot be recovered by the garbage collector.
+// It returns nil if d <= 0.
+func Tick(d Duration) <-chan Time {
+	if d <= 0 {
+		return nil
+	}
+	return NewTicker(d).C
+}
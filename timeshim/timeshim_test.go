@@ -0,0 +1,119 @@
+package timeshim_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/clocktest"
+	"github.com/go-toolbelt/clock/timeshim"
+)
+
+const timeout = 100 * time.Millisecond
+
+func TestSetClock(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	restore := timeshim.SetClock(fake)
+	if actual := timeshim.Now(); actual != start {
+		t.Errorf("expected %s got %s", start, actual)
+	}
+
+	restore()
+	if timeshim.Clock() == fake {
+		t.Error("expected the previous clock to be restored")
+	}
+}
+
+func TestSinceUntil(t *testing.T) {
+	start := time.Unix(2, 0)
+	fake := clock.NewFakeClockAt(start)
+	defer timeshim.SetClock(fake)()
+
+	if actual := timeshim.Since(start.Add(-timeshim.Second)); actual != timeshim.Second {
+		t.Errorf("expected %s got %s", timeshim.Second, actual)
+	}
+	if actual := timeshim.Until(start.Add(timeshim.Second)); actual != timeshim.Second {
+		t.Errorf("expected %s got %s", timeshim.Second, actual)
+	}
+}
+
+func TestNewTimer(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+	defer timeshim.SetClock(fake)()
+
+	timer := timeshim.NewTimer(1 * timeshim.Second)
+
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+	clocktest.RequireFiresAtWithin(t, start.Add(1*time.Second), timer.C, timeout)
+
+	timer.Reset(1 * timeshim.Second)
+	fake.Advance(1 * time.Second)
+	clocktest.RequireFiresAtWithin(t, start.Add(2*time.Second), timer.C, timeout)
+}
+
+func TestNewTicker(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+	defer timeshim.SetClock(fake)()
+
+	ticker := timeshim.NewTicker(1 * timeshim.Second)
+	defer ticker.Stop()
+
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+	clocktest.RequireFiresAtWithin(t, start.Add(1*time.Second), ticker.C, timeout)
+
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+	clocktest.RequireFiresAtWithin(t, start.Add(2*time.Second), ticker.C, timeout)
+}
+
+func TestTicker_Reset(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+	defer timeshim.SetClock(fake)()
+
+	ticker := timeshim.NewTicker(1 * timeshim.Second)
+	defer ticker.Stop()
+
+	fake.BlockUntil(1)
+	ticker.Reset(2 * timeshim.Second)
+	if pending := len(fake.PendingTimers()); pending != 1 {
+		t.Errorf("expected 1 pending ticker got %d", pending)
+	}
+
+	fake.Advance(2 * time.Second)
+	clocktest.RequireFiresAtWithin(t, start.Add(2*time.Second), ticker.C, timeout)
+
+	// a stopped ticker starts forwarding again once reset
+	ticker.Stop()
+	ticker.Reset(1 * timeshim.Second)
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+	clocktest.RequireFiresAtWithin(t, start.Add(3*time.Second), ticker.C, timeout)
+}
+
+func TestSleep(t *testing.T) {
+	fake := clock.NewFakeClock()
+	defer timeshim.SetClock(fake)()
+
+	woke := make(chan struct{})
+	go func() {
+		defer close(woke)
+		timeshim.Sleep(1 * timeshim.Second)
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(1 * time.Second)
+	clocktest.RequireClosedWithin(t, woke, timeout)
+}
+
+func TestTick_NonPositive(t *testing.T) {
+	if timeshim.Tick(0) != nil {
+		t.Error("expected tick to return nil")
+	}
+}
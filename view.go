@@ -0,0 +1,78 @@
+package clock
+
+import "time"
+
+// labels are attached to the sleepers created through a view of a fake
+// clock, returned by Prioritized or Tagged.
+type labels struct {
+	// priority orders the sleepers due at the same time, highest first
+	priority int
+
+	// tag is counted by UntilTagged
+	tag string
+}
+
+// viewClock is a view of a fake clock that labels the sleepers created
+// through it. Like the read-only view, it can't be asserted back to a
+// FakeClock.
+type viewClock struct {
+	readOnlyClock
+	labels labels
+}
+
+// view returns a view of clock with labels changed by f, or false if clock
+// isn't a fake clock or a view of one.
+func view(clock Clock, f func(*labels)) (Clock, bool) {
+	var fake *fakeClock
+	var l labels
+	switch clock := clock.(type) {
+	case *fakeClock:
+		fake = clock
+	case *readOnlyClock:
+		fake = clock.fake
+	case *viewClock:
+		fake = clock.fake
+		l = clock.labels
+	default:
+		return nil, false
+	}
+
+	f(&l)
+	return &viewClock{
+		readOnlyClock: readOnlyClock{
+			Clock: fake,
+			fake:  fake,
+		},
+		labels: l,
+	}, true
+}
+
+func (clock *viewClock) Sleep(d time.Duration) {
+	clock.fake.sleep(d, clock.fake.caller(1), clock.labels)
+}
+
+func (clock *viewClock) After(d time.Duration) <-chan time.Time {
+	c := make(chan time.Time, 1)
+	clock.fake.after(d, c, KindAfter, clock.fake.caller(1), clock.labels)
+	return c
+}
+
+func (clock *viewClock) AfterFunc(d time.Duration, f func()) Timer {
+	return clock.fake.afterFunc(d, f, clock.fake.caller(1), clock.labels)
+}
+
+func (clock *viewClock) NewTimer(d time.Duration) Timer {
+	return clock.fake.newTimer(d, clock.fake.caller(1), clock.labels)
+}
+
+func (clock *viewClock) NewTicker(d time.Duration) Ticker {
+	return clock.fake.newTicker(d, clock.fake.caller(1), clock.labels)
+}
+
+func (clock *viewClock) Tick(d time.Duration) func() <-chan time.Time {
+	if d <= 0 {
+		return func() <-chan time.Time { return nil }
+	}
+
+	return clock.fake.newTicker(d, clock.fake.caller(1), clock.labels).C
+}
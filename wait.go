@@ -0,0 +1,72 @@
+package clock
+
+import (
+	"context"
+	"reflect"
+)
+
+// WaitAny waits for a value from any of the channels cs, such as the C of
+// several Timers, and returns the index of the channel it came from, and the
+// value. It returns ErrClosed, with the index, if that channel is closed, and
+// the error of ctx if ctx is done first.
+//
+// WaitAny waits on all the channels at once, from the calling goroutine,
+// without starting a goroutine per channel. If several channels are ready,
+// it picks one at random, like a select statement.
+func WaitAny[T any](ctx context.Context, cs ...<-chan T) (int, T, error) {
+	cases := selectCases(ctx, cs)
+
+	var zero T
+	chosen, v, ok := reflect.Select(cases)
+	if chosen == 0 {
+		return -1, zero, ctx.Err()
+	}
+	if !ok {
+		return chosen - 1, zero, ErrClosed
+	}
+	return chosen - 1, v.Interface().(T), nil
+}
+
+// WaitAll waits for a value from each of the channels cs, such as the C of
+// several Timers, and returns the values, in the order of the channels.
+// It returns ErrClosed if a channel is closed before it sent a value, and
+// the error of ctx if ctx is done first, with the values received so far;
+// the values not received are zero.
+//
+// Like WaitAny, WaitAll doesn't start a goroutine per channel.
+func WaitAll[T any](ctx context.Context, cs ...<-chan T) ([]T, error) {
+	cases := selectCases(ctx, cs)
+	values := make([]T, len(cs))
+
+	for left := len(cs); left > 0; left-- {
+		chosen, v, ok := reflect.Select(cases)
+		if chosen == 0 {
+			return values, ctx.Err()
+		}
+		if !ok {
+			return values, ErrClosed
+		}
+		values[chosen-1] = v.Interface().(T)
+
+		// a zero Chan disables the case
+		cases[chosen].Chan = reflect.Value{}
+	}
+	return values, nil
+}
+
+// selectCases returns the cases receiving from ctx.Done(), then from each
+// of cs.
+func selectCases[T any](ctx context.Context, cs []<-chan T) []reflect.SelectCase {
+	cases := make([]reflect.SelectCase, 0, len(cs)+1)
+	cases = append(cases, reflect.SelectCase{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(ctx.Done()),
+	})
+	for _, c := range cs {
+		cases = append(cases, reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(c),
+		})
+	}
+	return cases
+}
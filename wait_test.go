@@ -0,0 +1,110 @@
+package clock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestWaitAny(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	first := fake.NewTimer(3 * time.Second)
+	second := fake.NewTimer(1 * time.Second)
+	third := fake.NewTimer(2 * time.Second)
+
+	fake.Advance(2 * time.Second)
+
+	i, at, err := clock.WaitAny(context.Background(), first.C(), second.C())
+	if err != nil || i != 1 || !at.Equal(start.Add(1*time.Second)) {
+		t.Errorf("expected %d at %s got %d at %s, %v", 1, start.Add(1*time.Second), i, at, err)
+	}
+
+	i, at, err = clock.WaitAny(context.Background(), first.C(), third.C())
+	if err != nil || i != 1 || !at.Equal(start.Add(2*time.Second)) {
+		t.Errorf("expected %d at %s got %d at %s, %v", 1, start.Add(2*time.Second), i, at, err)
+	}
+}
+
+func TestWaitAny_Context(t *testing.T) {
+	fake := clock.NewFakeClock()
+	timer := fake.NewTimer(time.Second)
+	defer timer.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if i, _, err := clock.WaitAny(ctx, timer.C()); i != -1 || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %d, %v got %d, %v", -1, context.Canceled, i, err)
+	}
+}
+
+func TestWaitAny_Closed(t *testing.T) {
+	c := make(chan int)
+	close(c)
+
+	if i, _, err := clock.WaitAny(context.Background(), make(chan int), c); i != 1 || !errors.Is(err, clock.ErrClosed) {
+		t.Errorf("expected %d, %v got %d, %v", 1, clock.ErrClosed, i, err)
+	}
+}
+
+func TestWaitAll(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	first := fake.NewTimer(2 * time.Second)
+	second := fake.NewTimer(1 * time.Second)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		values, err := clock.WaitAll(context.Background(), first.C(), second.C())
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		expected := []time.Time{start.Add(2 * time.Second), start.Add(1 * time.Second)}
+		for i := range expected {
+			if !values[i].Equal(expected[i]) {
+				t.Errorf("expected %s got %s", expected[i], values[i])
+			}
+		}
+	}()
+
+	fake.Advance(1 * time.Second)
+	fake.Advance(1 * time.Second)
+	<-done
+}
+
+func TestWaitAll_Context(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	first := fake.NewTimer(1 * time.Second)
+	second := fake.NewTimer(2 * time.Second)
+	defer second.Stop()
+
+	fake.Advance(1 * time.Second)
+
+	ctx, cancel := clock.WithTimeout(context.Background(), fake, 500*time.Millisecond)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		values, err := clock.WaitAll(ctx, first.C(), second.C())
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected %v got %v", context.DeadlineExceeded, err)
+		}
+		if !values[1].IsZero() {
+			t.Errorf("expected no second value got %s", values[1])
+		}
+	}()
+
+	fake.Advance(500 * time.Millisecond)
+	<-done
+}
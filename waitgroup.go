@@ -0,0 +1,121 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WaitGroup is like sync.WaitGroup, but its waits can be bounded by a timeout
+// measured by a clock or by a context.
+//
+// A WaitGroup must not be copied after first use.
+type WaitGroup struct {
+	clock Clock
+	mutex sync.Mutex
+	n     int
+	done  chan struct{}
+}
+
+// NewWaitGroup returns a WaitGroup whose timeouts are measured by clock.
+func NewWaitGroup(clock Clock) *WaitGroup {
+	return &WaitGroup{
+		clock: clock,
+	}
+}
+
+// Add adds delta, which may be negative, to the counter.
+// It panics if the counter goes negative.
+func (wg *WaitGroup) Add(delta int) {
+	wg.mutex.Lock()
+	defer wg.mutex.Unlock()
+
+	wg.n += delta
+	if wg.n < 0 {
+		panic("clock: negative WaitGroup counter")
+	}
+
+	if wg.n == 0 && wg.done != nil {
+		close(wg.done)
+		wg.done = nil
+	}
+}
+
+// Done decrements the counter by one.
+func (wg *WaitGroup) Done() {
+	wg.Add(-1)
+}
+
+// Wait blocks until the counter is zero.
+func (wg *WaitGroup) Wait() {
+	<-wg.wait()
+}
+
+// WaitTimeout blocks until the counter is zero, returning a *TimeoutError
+// once d has elapsed.
+func (wg *WaitGroup) WaitTimeout(d time.Duration) error {
+	done := wg.wait()
+
+	select {
+	case <-done:
+		return nil
+	default:
+	}
+
+	if d <= 0 {
+		return &TimeoutError{Limit: d}
+	}
+
+	start := wg.clock.Now()
+	timer := wg.clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return nil
+	case <-timer.C():
+		return &TimeoutError{
+			Limit:   d,
+			Elapsed: wg.clock.Since(start),
+		}
+	}
+}
+
+// WaitContext blocks until the counter is zero, returning ctx.Err() if ctx is
+// done first.
+func (wg *WaitGroup) WaitContext(ctx context.Context) error {
+	done := wg.wait()
+
+	select {
+	case <-done:
+		return nil
+	default:
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (wg *WaitGroup) wait() <-chan struct{} {
+	wg.mutex.Lock()
+	defer wg.mutex.Unlock()
+
+	if wg.n == 0 {
+		return closedChan
+	}
+
+	if wg.done == nil {
+		wg.done = make(chan struct{})
+	}
+	return wg.done
+}
+
+var closedChan = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
@@ -0,0 +1,82 @@
+package clock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestWaitGroup_WaitTimeout(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	wg := clock.NewWaitGroup(fake)
+	if err := wg.WaitTimeout(0); err != nil {
+		t.Fatalf("expected an empty group to be done got %v", err)
+	}
+
+	wg.Add(2)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- wg.WaitTimeout(1 * time.Second)
+	}()
+
+	assertClockUntil(t, 1, fake)
+	wg.Done()
+	fake.Advance(1 * time.Second)
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected a timeout got %v", err)
+		}
+	case <-time.After(sentTimeout):
+		t.Fatal("timeout: WaitTimeout did not return")
+	}
+
+	go func() {
+		errs <- wg.WaitTimeout(1 * time.Second)
+	}()
+
+	assertClockUntil(t, 1, fake)
+	wg.Done()
+
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Errorf("expected nil got %v", err)
+		}
+	case <-time.After(sentTimeout):
+		t.Fatal("timeout: WaitTimeout did not return")
+	}
+}
+
+func TestWaitGroup_WaitContext(t *testing.T) {
+	wg := clock.NewWaitGroup(clock.NewFakeClock())
+	wg.Add(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := wg.WaitContext(ctx); err != context.Canceled {
+		t.Errorf("expected %v got %v", context.Canceled, err)
+	}
+
+	wg.Done()
+	if err := wg.WaitContext(ctx); err != nil {
+		t.Errorf("expected nil got %v", err)
+	}
+}
+
+func TestWaitGroup_Negative(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+
+	clock.NewWaitGroup(clock.NewFakeClock()).Done()
+}
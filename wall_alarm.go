@@ -0,0 +1,152 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// wallPoll is how often a WallAlarm rechecks the wall time on clocks that
+// don't report their steps, such as the real clock.
+const wallPoll = time.Second
+
+// WallAlarm fires once when the wall time of a clock reaches an instant.
+//
+// Unlike a Timer, which waits for a duration, a WallAlarm follows the wall
+// clock when it's stepped: an alarm at 09:00 fires when the clock reads
+// 09:00, even if the clock was set back or forward in the meantime. On the
+// fake clock, steps made by SetTime are taken into account right away. On
+// other clocks, the alarm rechecks the wall time every second.
+type WallAlarm struct {
+	clock Clock
+	c     chan time.Time
+	poll  time.Duration
+
+	mutex   sync.Mutex
+	at      time.Time
+	timer   Timer
+	active  bool
+	unwatch func()
+}
+
+// NewWallAlarm returns a WallAlarm that sends the time on its channel once
+// the wall time of clock reaches at. If at has passed, it fires right away.
+func NewWallAlarm(clock Clock, at time.Time) *WallAlarm {
+	alarm := &WallAlarm{
+		clock: clock,
+		c:     make(chan time.Time, 1),
+		poll:  wallPoll,
+	}
+	if _, ok := clock.(stepWatcherClock); ok {
+		alarm.poll = 0
+	}
+
+	alarm.mutex.Lock()
+	defer alarm.mutex.Unlock()
+
+	alarm.arm(at)
+	return alarm
+}
+
+// C returns the channel on which the time is delivered.
+func (alarm *WallAlarm) C() <-chan time.Time {
+	return alarm.c
+}
+
+// At returns the wall time at which the alarm fires.
+func (alarm *WallAlarm) At() time.Time {
+	alarm.mutex.Lock()
+	defer alarm.mutex.Unlock()
+
+	return alarm.at
+}
+
+// Stop prevents the alarm from firing. It returns true if the call stops the
+// alarm, false if the alarm has already fired or been stopped.
+func (alarm *WallAlarm) Stop() bool {
+	alarm.mutex.Lock()
+	defer alarm.mutex.Unlock()
+
+	active := alarm.active
+	alarm.disarm()
+	return active
+}
+
+// Reset changes the alarm to fire once the wall time reaches at, draining
+// a time sent before the reset. It returns true if the alarm had been active,
+// false if it had fired or been stopped.
+func (alarm *WallAlarm) Reset(at time.Time) bool {
+	alarm.mutex.Lock()
+	defer alarm.mutex.Unlock()
+
+	active := alarm.active
+	alarm.disarm()
+
+	select {
+	case <-alarm.c:
+	default:
+	}
+
+	alarm.arm(at)
+	return active
+}
+
+// arm schedules the alarm at at. The caller holds the alarm's mutex.
+func (alarm *WallAlarm) arm(at time.Time) {
+	// compare wall times only, even if at has a monotonic reading
+	alarm.at = at.Round(0)
+	alarm.active = true
+
+	if clock, ok := alarm.clock.(stepWatcherClock); ok {
+		alarm.unwatch = clock.watchSteps(func(_, _ time.Time) { alarm.check() })
+	}
+
+	d := alarm.wait(alarm.clock.Now())
+	if alarm.timer == nil {
+		alarm.timer = alarm.clock.AfterFunc(d, alarm.check)
+	} else {
+		alarm.timer.Reset(d)
+	}
+}
+
+// disarm stops the alarm. The caller holds the alarm's mutex.
+func (alarm *WallAlarm) disarm() {
+	alarm.active = false
+	if alarm.timer != nil {
+		alarm.timer.Stop()
+	}
+	if alarm.unwatch != nil {
+		alarm.unwatch()
+		alarm.unwatch = nil
+	}
+}
+
+// wait returns how long to wait from now before checking the alarm again.
+func (alarm *WallAlarm) wait(now time.Time) time.Duration {
+	d := alarm.at.Sub(now.Round(0))
+	if alarm.poll > 0 && d > alarm.poll {
+		d = alarm.poll
+	}
+	return d
+}
+
+// check fires the alarm if the wall time reached it, or waits again.
+func (alarm *WallAlarm) check() {
+	alarm.mutex.Lock()
+	defer alarm.mutex.Unlock()
+
+	if !alarm.active {
+		return
+	}
+
+	now := alarm.clock.Now()
+	if now.Round(0).Before(alarm.at) {
+		alarm.timer.Reset(alarm.wait(now))
+		return
+	}
+
+	alarm.disarm()
+	select {
+	case alarm.c <- now:
+	default:
+	}
+}
@@ -0,0 +1,107 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestWallAlarm(t *testing.T) {
+	fake := clock.NewFakeClock()
+	at := fake.Now().Add(time.Hour)
+
+	alarm := clock.NewWallAlarm(fake, at)
+	defer alarm.Stop()
+
+	fake.Advance(time.Hour - time.Nanosecond)
+	assertNotSent(t, alarm.C())
+
+	fake.Advance(time.Nanosecond)
+	assertSent(t, at, alarm.C())
+}
+
+func TestWallAlarm_Past(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	alarm := clock.NewWallAlarm(fake, fake.Now().Add(-time.Hour))
+	defer alarm.Stop()
+
+	assertSent(t, fake.Now(), alarm.C())
+}
+
+func TestWallAlarm_SetTimeForward(t *testing.T) {
+	fake := clock.NewFakeClock()
+	at := fake.Now().Add(time.Hour)
+
+	alarm := clock.NewWallAlarm(fake, at)
+	defer alarm.Stop()
+
+	timer := fake.NewTimer(time.Hour)
+	defer timer.Stop()
+	c := timer.C()
+
+	// the wall clock is stepped past the alarm, which fires, but the timer
+	// still has an hour to wait
+	fake.SetTime(at.Add(time.Minute))
+	assertSent(t, at.Add(time.Minute), alarm.C())
+	assertNotSent(t, c)
+
+	fake.Advance(time.Hour)
+	assertSent(t, at.Add(time.Hour+time.Minute), c)
+}
+
+func TestWallAlarm_SetTimeBackward(t *testing.T) {
+	fake := clock.NewFakeClock()
+	start := fake.Now()
+	at := start.Add(time.Hour)
+
+	alarm := clock.NewWallAlarm(fake, at)
+	defer alarm.Stop()
+
+	// after an hour of waiting, the wall clock is set back half an hour,
+	// so the alarm waits another half an hour
+	fake.Advance(30 * time.Minute)
+	fake.SetTime(start)
+	fake.Advance(30 * time.Minute)
+	assertNotSent(t, alarm.C())
+
+	fake.Advance(30 * time.Minute)
+	assertSent(t, at, alarm.C())
+}
+
+func TestWallAlarm_StopReset(t *testing.T) {
+	fake := clock.NewFakeClock()
+	at := fake.Now().Add(time.Hour)
+
+	alarm := clock.NewWallAlarm(fake, at)
+	if !alarm.Stop() {
+		t.Error("expected Stop to stop an active alarm")
+	}
+
+	fake.SetTime(at)
+	assertNotSent(t, alarm.C())
+
+	if alarm.Reset(at.Add(time.Minute)) {
+		t.Error("expected Reset to report a stopped alarm")
+	}
+	fake.Advance(time.Minute)
+	assertSent(t, at.Add(time.Minute), alarm.C())
+
+	if alarm.Stop() {
+		t.Error("expected Stop to report a fired alarm")
+	}
+}
+
+func TestWallAlarm_Real(t *testing.T) {
+	c := clock.NewRealClock()
+
+	alarm := clock.NewWallAlarm(c, time.Now().Add(10*time.Millisecond))
+	defer alarm.Stop()
+
+	select {
+	case <-alarm.C():
+	case <-time.After(time.Second):
+		t.Error("timeout: the alarm didn't fire")
+	}
+}
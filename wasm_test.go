@@ -0,0 +1,81 @@
+//go:build js && wasm
+
+package clock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// Under js/wasm, goroutines share a single thread and only switch when one
+// blocks, so these tests check the fake clock never relies on another
+// goroutine running in parallel.
+
+func TestWasm_AfterFunc(t *testing.T) {
+	fake := clock.NewFakeClockAt(time.Unix(1, 0))
+
+	called := make(chan struct{})
+	fake.AfterFunc(time.Second, func() { close(called) })
+	fake.Advance(time.Second)
+
+	select {
+	case <-called:
+	case <-time.After(sentTimeout):
+		t.Fatalf("expected the callback to run")
+	}
+}
+
+func TestWasm_BlockUntil(t *testing.T) {
+	fake := clock.NewFakeClockAt(time.Unix(1, 0))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fake.Sleep(time.Minute)
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Minute)
+
+	select {
+	case <-done:
+	case <-time.After(sentTimeout):
+		t.Fatalf("expected Sleep to return")
+	}
+}
+
+func TestWasm_WithTimeout(t *testing.T) {
+	fake := clock.NewFakeClockAt(time.Unix(1, 0))
+
+	ctx, cancel := clock.WithTimeout(context.Background(), fake, time.Second)
+	defer cancel()
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+
+	select {
+	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			t.Errorf("expected %v got %v", context.DeadlineExceeded, ctx.Err())
+		}
+	case <-time.After(sentTimeout):
+		t.Fatalf("expected the context to be done")
+	}
+}
+
+func TestWasm_Ticker(t *testing.T) {
+	fake := clock.NewFakeClockAt(time.Unix(1, 0))
+
+	ticker := fake.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for i := 1; i <= 3; i++ {
+		c := ticker.C()
+		fake.Advance(time.Second)
+		assertSent(t, time.Unix(int64(1+i), 0), c)
+	}
+}
@@ -0,0 +1,187 @@
+// Package window counts events over windows of time measured by a clock.Clock,
+// and limits events per window.
+package window
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+// A Counter counts events over a window of time.
+type Counter interface {
+	// Add records n events at the current time.
+	Add(n int64)
+
+	// Count returns the number of events in the current window.
+	Count() int64
+}
+
+// FixedWindow counts events in consecutive, non-overlapping windows.
+// Windows are aligned on multiples of their size since the zero time,
+// so all FixedWindows of the same size roll over at the same instants.
+type FixedWindow struct {
+	clock clock.Clock
+	size  time.Duration
+
+	mutex sync.Mutex
+	start time.Time
+	count int64
+}
+
+// NewFixedWindow returns a FixedWindow of the given size, measured by clock.
+// It panics if size <= 0.
+func NewFixedWindow(clock clock.Clock, size time.Duration) *FixedWindow {
+	if size <= 0 {
+		panic("window: non-positive size for NewFixedWindow")
+	}
+
+	return &FixedWindow{
+		clock: clock,
+		size:  size,
+	}
+}
+
+func (w *FixedWindow) Add(n int64) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.roll()
+	w.count += n
+}
+
+func (w *FixedWindow) Count() int64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.roll()
+	return w.count
+}
+
+// Reset returns the time at which the current window ends.
+func (w *FixedWindow) Reset() time.Time {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.roll()
+	return w.start.Add(w.size)
+}
+
+func (w *FixedWindow) roll() {
+	if start := w.clock.Now().Truncate(w.size); !start.Equal(w.start) {
+		w.start = start
+		w.count = 0
+	}
+}
+
+// SlidingWindow counts events in the trailing window ending at the current time.
+//
+// Events are grouped in buckets of size/buckets, and expire a whole bucket at a time,
+// so Count may include events up to one bucket older than the window.
+type SlidingWindow struct {
+	clock  clock.Clock
+	bucket time.Duration
+
+	mutex   sync.Mutex
+	starts  []time.Time
+	counts  []int64
+	current int
+}
+
+// NewSlidingWindow returns a SlidingWindow of the given size, measured by clock,
+// with the given number of buckets.
+// It panics if size <= 0 or buckets <= 0.
+func NewSlidingWindow(clock clock.Clock, size time.Duration, buckets int) *SlidingWindow {
+	if size <= 0 || buckets <= 0 {
+		panic("window: non-positive size or buckets for NewSlidingWindow")
+	}
+
+	bucket := size / time.Duration(buckets)
+	if bucket <= 0 {
+		bucket = 1
+	}
+
+	return &SlidingWindow{
+		clock:  clock,
+		bucket: bucket,
+		starts: make([]time.Time, buckets),
+		counts: make([]int64, buckets),
+	}
+}
+
+func (w *SlidingWindow) Add(n int64) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.roll()
+	w.counts[w.current] += n
+}
+
+func (w *SlidingWindow) Count() int64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.roll()
+
+	var count int64
+	for _, n := range w.counts {
+		count += n
+	}
+	return count
+}
+
+// roll moves the current bucket to the one containing the current time,
+// clearing the buckets that fell out of the window.
+func (w *SlidingWindow) roll() {
+	start := w.clock.Now().Truncate(w.bucket)
+	if start.Equal(w.starts[w.current]) {
+		return
+	}
+
+	size := time.Duration(len(w.counts)) * w.bucket
+	for i, s := range w.starts {
+		if !s.After(start.Add(-size)) {
+			w.starts[i] = time.Time{}
+			w.counts[i] = 0
+		}
+	}
+
+	previous := w.starts[w.current]
+	if steps := start.Sub(previous) / w.bucket; !previous.IsZero() && steps > 0 {
+		w.current = int((time.Duration(w.current) + steps%time.Duration(len(w.counts))) % time.Duration(len(w.counts)))
+	}
+	w.starts[w.current] = start
+}
+
+// A Limiter allows at most limit events per window of its Counter.
+type Limiter struct {
+	mutex   sync.Mutex
+	counter Counter
+	limit   int64
+}
+
+// NewLimiter returns a Limiter allowing limit events per window of counter.
+func NewLimiter(counter Counter, limit int64) *Limiter {
+	return &Limiter{
+		counter: counter,
+		limit:   limit,
+	}
+}
+
+// Allow is shorthand for AllowN(1).
+func (l *Limiter) Allow() bool {
+	return l.AllowN(1)
+}
+
+// AllowN reports whether n events may happen now, recording them if so.
+func (l *Limiter) AllowN(n int64) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.counter.Count()+n > l.limit {
+		return false
+	}
+	l.counter.Add(n)
+	return true
+}
@@ -0,0 +1,82 @@
+package window_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/window"
+)
+
+func TestFixedWindow(t *testing.T) {
+	fake := clock.NewFakeClockAt(time.Unix(10, 0))
+
+	w := window.NewFixedWindow(fake, 10*time.Second)
+	w.Add(2)
+	fake.Advance(9 * time.Second)
+	w.Add(1)
+
+	assertCount(t, 3, w)
+	if reset := w.Reset(); reset != time.Unix(20, 0) {
+		t.Errorf("expected reset at %s got %s", time.Unix(20, 0), reset)
+	}
+
+	fake.Advance(1 * time.Second)
+	assertCount(t, 0, w)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	fake := clock.NewFakeClockAt(time.Unix(10, 0))
+
+	w := window.NewSlidingWindow(fake, 10*time.Second, 10)
+	w.Add(1)
+	fake.Advance(5 * time.Second)
+	w.Add(2)
+
+	assertCount(t, 3, w)
+
+	fake.Advance(5 * time.Second)
+	assertCount(t, 2, w)
+
+	fake.Advance(5 * time.Second)
+	assertCount(t, 0, w)
+}
+
+func TestSlidingWindow_LongIdle(t *testing.T) {
+	fake := clock.NewFakeClockAt(time.Unix(10, 0))
+
+	w := window.NewSlidingWindow(fake, 10*time.Second, 10)
+	w.Add(1)
+
+	fake.Advance(1 * time.Hour)
+	w.Add(1)
+	fake.Advance(3 * time.Second)
+	w.Add(1)
+
+	assertCount(t, 2, w)
+}
+
+func TestLimiter(t *testing.T) {
+	fake := clock.NewFakeClockAt(time.Unix(10, 0))
+
+	limiter := window.NewLimiter(window.NewSlidingWindow(fake, 10*time.Second, 10), 2)
+	if !limiter.Allow() || !limiter.Allow() {
+		t.Fatal("expected events under the limit to be allowed")
+	}
+	if limiter.Allow() {
+		t.Fatal("expected event over the limit to be denied")
+	}
+
+	fake.Advance(10 * time.Second)
+	if !limiter.AllowN(2) {
+		t.Error("expected events to be allowed once the window slid")
+	}
+}
+
+func assertCount(t *testing.T, expected int64, counter window.Counter) {
+	t.Helper()
+
+	if actual := counter.Count(); actual != expected {
+		t.Errorf("expected count %d got %d", expected, actual)
+	}
+}
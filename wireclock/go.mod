@@ -0,0 +1,7 @@
+module github.com/go-toolbelt/clock/wireclock
+
+go 1.22
+
+require github.com/go-toolbelt/clock v0.0.0
+
+require github.com/google/wire v0.6.0
@@ -0,0 +1,24 @@
+// Package wireclock provides Google Wire provider sets that supply a clock.Clock.
+package wireclock
+
+import (
+	"github.com/go-toolbelt/clock"
+	"github.com/google/wire"
+)
+
+// ProviderSet provides a clock.Clock backed by the real clock.
+var ProviderSet = wire.NewSet(clock.NewRealClock)
+
+// FakeProviderSet provides a clock.FakeClock, and the same clock as a clock.Clock.
+var FakeProviderSet = wire.NewSet(NewFakeClock, ProvideFakeClock)
+
+// NewFakeClock returns a clock.FakeClock with the default options.
+// Wire can't call the variadic clock.NewFakeClock directly.
+func NewFakeClock() clock.FakeClock {
+	return clock.NewFakeClock()
+}
+
+// ProvideFakeClock exposes a clock.FakeClock as a clock.Clock.
+func ProvideFakeClock(fake clock.FakeClock) clock.Clock {
+	return fake
+}
@@ -0,0 +1,16 @@
+package wireclock_test
+
+import (
+	"testing"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/wireclock"
+)
+
+func TestProvideFakeClock(t *testing.T) {
+	fake := clock.NewFakeClock()
+
+	if c := wireclock.ProvideFakeClock(fake); c != fake {
+		t.Error("expected the fake clock to be provided as the clock")
+	}
+}
@@ -0,0 +1,147 @@
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDecorator is returned by Builder.Build when a decorator was
+// given an invalid argument.
+var ErrInvalidDecorator = errors.New("clock: invalid decorator")
+
+// A Builder composes decorators around a clock:
+//
+//	c, err := clock.Wrap(base).Offset(time.Hour).Scale(2).Record(&log).Build()
+//
+// Decorators apply in the order they're added, each wrapping the clock built
+// so far: above, Scale speeds up the offset clock, and Record sees the
+// scaled time. Arguments are validated by Build, instead of panicking like
+// the decorator functions.
+type Builder struct {
+	layers []layer
+}
+
+type layer struct {
+	name  string
+	err   error
+	apply func(Clock) Clock
+}
+
+// Wrap returns a Builder decorating base.
+func Wrap(base Clock) *Builder {
+	return &Builder{
+		layers: []layer{{
+			name:  "base",
+			apply: func(Clock) Clock { return base },
+		}},
+	}
+}
+
+func (b *Builder) add(name string, err error, apply func(Clock) Clock) *Builder {
+	b.layers = append(b.layers, layer{name: name, err: err, apply: apply})
+	return b
+}
+
+// Offset adds an Offset decorator.
+func (b *Builder) Offset(d time.Duration) *Builder {
+	return b.add(fmt.Sprintf("Offset(%s)", d), nil, func(c Clock) Clock {
+		return Offset(c, d)
+	})
+}
+
+// Scale adds a Scale decorator.
+func (b *Builder) Scale(factor float64) *Builder {
+	var err error
+	if factor <= 0 {
+		err = errNonPositiveFactor
+	}
+	return b.add(fmt.Sprintf("Scale(%g)", factor), err, func(c Clock) Clock {
+		return Scale(c, factor)
+	})
+}
+
+// Jitter adds a Jitter decorator.
+func (b *Builder) Jitter(max time.Duration, r *rand.Rand) *Builder {
+	var err error
+	if max < 0 {
+		err = errNegativeJitter
+	}
+	return b.add(fmt.Sprintf("Jitter(%s)", max), err, func(c Clock) Clock {
+		return Jitter(c, max, r)
+	})
+}
+
+// Quantize adds a Quantize decorator.
+func (b *Builder) Quantize(q time.Duration) *Builder {
+	var err error
+	if q <= 0 {
+		err = errNonPositiveQuantum
+	}
+	return b.add(fmt.Sprintf("Quantize(%s)", q), err, func(c Clock) Clock {
+		return Quantize(c, q)
+	})
+}
+
+// Chaos adds a Chaos decorator.
+func (b *Builder) Chaos(policy ChaosPolicy, seed int64) *Builder {
+	var err error
+	if !policy.valid() {
+		err = errInvalidChaos
+	}
+	return b.add(fmt.Sprintf("Chaos(%d)", seed), err, func(c Clock) Clock {
+		return Chaos(c, policy, seed)
+	})
+}
+
+// Record adds a Record decorator, logging calls to log.
+func (b *Builder) Record(log *CallLog) *Builder {
+	var err error
+	if log == nil {
+		err = errors.New("nil CallLog")
+	}
+	return b.add("Record", err, func(c Clock) Clock {
+		return Record(c, log)
+	})
+}
+
+// Recover adds a RecoverCallbacks decorator.
+func (b *Builder) Recover(handler func(*CallbackPanic)) *Builder {
+	return b.add("Recover", nil, func(c Clock) Clock {
+		return RecoverCallbacks(c, handler)
+	})
+}
+
+// Build returns the decorated clock. It returns an error wrapping
+// ErrInvalidDecorator, naming the first invalid decorator,
+// if any was given an invalid argument.
+func (b *Builder) Build() (Clock, error) {
+	for i, layer := range b.layers {
+		if layer.err != nil {
+			return nil, fmt.Errorf("%w: %s (decorator %d): %s", ErrInvalidDecorator, layer.name, i, layer.err)
+		}
+	}
+
+	var c Clock
+	for _, layer := range b.layers {
+		c = layer.apply(c)
+	}
+	return c, nil
+}
+
+// String describes the decorators, from the outermost to the base clock,
+// such as "Record(Scale(2, Offset(1h0m0s, base)))".
+func (b *Builder) String() string {
+	s := "base"
+	for _, layer := range b.layers[1:] {
+		name := layer.name
+		if i := strings.IndexByte(name, '('); i >= 0 {
+			s = name[:len(name)-1] + ", " + s + ")"
+		} else {
+			s = name + "(" + s + ")"
+		}
+	}
+	return s
+}
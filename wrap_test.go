@@ -0,0 +1,63 @@
+package clock_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+)
+
+func TestWrap(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	var log clock.CallLog
+	b := clock.Wrap(fake).Offset(time.Hour).Scale(2).Record(&log)
+	if s, expected := b.String(), "Record(Scale(2, Offset(1h0m0s, base)))"; s != expected {
+		t.Errorf("expected %q got %q", expected, s)
+	}
+
+	c, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the offset clock is scaled from its own time
+	fake.Advance(time.Second)
+	if now, expected := c.Now(), start.Add(time.Hour+2*time.Second); !now.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, now)
+	}
+
+	// the recorder sees the offset, scaled clock
+	if calls := log.Calls(); len(calls) != 1 || !calls[0].At.Equal(start.Add(time.Hour+2*time.Second)) {
+		t.Errorf("unexpected calls %v", calls)
+	}
+}
+
+func TestWrap_Order(t *testing.T) {
+	start := time.Unix(0, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	// quantizing the offset clock truncates the offset,
+	// offsetting the quantized clock doesn't
+	quantizeLast, _ := clock.Wrap(fake).Offset(30 * time.Minute).Quantize(time.Hour).Build()
+	offsetLast, _ := clock.Wrap(fake).Quantize(time.Hour).Offset(30 * time.Minute).Build()
+
+	if now := quantizeLast.Now(); !now.Equal(start) {
+		t.Errorf("expected %s got %s", start, now)
+	}
+	if now, expected := offsetLast.Now(), start.Add(30*time.Minute); !now.Equal(expected) {
+		t.Errorf("expected %s got %s", expected, now)
+	}
+}
+
+func TestWrap_Invalid(t *testing.T) {
+	_, err := clock.Wrap(clock.NewFakeClock()).Offset(time.Hour).Quantize(0).Scale(-1).Build()
+	if !errors.Is(err, clock.ErrInvalidDecorator) {
+		t.Fatalf("expected %v got %v", clock.ErrInvalidDecorator, err)
+	}
+	if expected := "clock: invalid decorator: Quantize(0s) (decorator 2): clock: non-positive quantum for Quantize"; err.Error() != expected {
+		t.Errorf("expected %q got %q", expected, err)
+	}
+}
@@ -0,0 +1,14 @@
+module github.com/go-toolbelt/clock/zerologclock
+
+go 1.22
+
+require (
+	github.com/go-toolbelt/clock v0.0.0
+	github.com/rs/zerolog v1.33.0
+)
+
+require (
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
+	golang.org/x/sys v0.12.0 // indirect
+)
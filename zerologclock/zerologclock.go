@@ -0,0 +1,25 @@
+// Package zerologclock sources zerolog timestamps from a clock.Clock,
+// so logs written by code under test carry the same fake time as the code itself.
+package zerologclock
+
+import (
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/rs/zerolog"
+)
+
+// TimestampFunc returns a function suitable for zerolog.TimestampFunc.
+// Since zerolog.TimestampFunc is global, prefer Hook in tests that run in parallel.
+func TimestampFunc(clock clock.Clock) func() time.Time {
+	return clock.Now
+}
+
+// Hook returns a zerolog.Hook that adds the clock's current time to every event,
+// under zerolog.TimestampFieldName.
+// Use it instead of, not in addition to, zerolog.Context.Timestamp.
+func Hook(clock clock.Clock) zerolog.Hook {
+	return zerolog.HookFunc(func(e *zerolog.Event, level zerolog.Level, msg string) {
+		e.Time(zerolog.TimestampFieldName, clock.Now())
+	})
+}
@@ -0,0 +1,37 @@
+package zerologclock_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-toolbelt/clock"
+	"github.com/go-toolbelt/clock/zerologclock"
+	"github.com/rs/zerolog"
+)
+
+func TestHook(t *testing.T) {
+	fake := clock.NewFakeClockAt(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Hook(zerologclock.Hook(fake))
+	logger.Info().Msg("hello")
+
+	expected := `"time":"2020-01-02T03:04:05Z"`
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("expected %s in %s", expected, buf.String())
+	}
+}
+
+func TestTimestampFunc(t *testing.T) {
+	start := time.Unix(1, 0)
+	fake := clock.NewFakeClockAt(start)
+
+	now := zerologclock.TimestampFunc(fake)
+	fake.Advance(1 * time.Second)
+
+	if actual := now(); actual != start.Add(1*time.Second) {
+		t.Errorf("expected %s got %s", start.Add(1*time.Second), actual)
+	}
+}